@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/llm/models"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage provider API keys in the OS keyring",
+	Long: `Store and remove provider API keys in the OS keyring, so they don't have to be
+kept in the config file or environment variables. Keys from the keyring are used
+for providers that are listed in the config without an apiKey or apiKeyCommand.`,
+	Example: `
+  # Store the Anthropic API key, you will be prompted for it
+  opencode auth set anthropic
+
+  # Store a key from another command
+  pass show openai | opencode auth set openai
+
+  # Show which providers have a stored key
+  opencode auth list
+
+  # Remove the stored key
+  opencode auth remove anthropic
+  `,
+}
+
+var authSetCmd = &cobra.Command{
+	Use:       "set <provider>",
+	Short:     "Store the API key of a provider",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: credentialProviderNames(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		provider, err := credentialProvider(args[0])
+		if err != nil {
+			return err
+		}
+		key, err := readAPIKey(provider)
+		if err != nil {
+			return err
+		}
+		if err := config.StoreAPIKey(provider, key); err != nil {
+			return err
+		}
+		fmt.Printf("Stored API key for %s\n", provider)
+		return nil
+	},
+}
+
+var authRemoveCmd = &cobra.Command{
+	Use:       "remove <provider>",
+	Short:     "Remove the stored API key of a provider",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: credentialProviderNames(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		provider, err := credentialProvider(args[0])
+		if err != nil {
+			return err
+		}
+		if err := config.DeleteAPIKey(provider); err != nil {
+			return err
+		}
+		fmt.Printf("Removed API key for %s\n", provider)
+		return nil
+	},
+}
+
+var authListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the providers with a stored API key",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, provider := range config.CredentialProviders {
+			stored, err := config.HasStoredAPIKey(provider)
+			if err != nil {
+				return fmt.Errorf("failed to read the keyring: %w", err)
+			}
+			status := "not stored"
+			if stored {
+				status = "stored"
+			}
+			fmt.Printf("%-12s %s\n", provider, status)
+		}
+		return nil
+	},
+}
+
+func credentialProviderNames() []string {
+	names := make([]string, len(config.CredentialProviders))
+	for i, provider := range config.CredentialProviders {
+		names[i] = string(provider)
+	}
+	return names
+}
+
+func credentialProvider(name string) (models.ModelProvider, error) {
+	if !slices.Contains(credentialProviderNames(), name) {
+		return "", fmt.Errorf("unknown provider %q, expected one of: %s", name, strings.Join(credentialProviderNames(), ", "))
+	}
+	return models.ModelProvider(name), nil
+}
+
+// readAPIKey prompts for the key without echoing it, or reads it from stdin
+// when that is not a terminal.
+func readAPIKey(provider models.ModelProvider) (string, error) {
+	var key string
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		fmt.Printf("API key for %s: ", provider)
+		data, err := term.ReadPassword(fd)
+		fmt.Println()
+		if err != nil {
+			return "", fmt.Errorf("failed to read API key: %w", err)
+		}
+		key = string(data)
+	} else {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("failed to read API key: %w", err)
+		}
+		key = line
+	}
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", fmt.Errorf("API key is empty")
+	}
+	return key, nil
+}
+
+func init() {
+	authCmd.AddCommand(authSetCmd, authRemoveCmd, authListCmd)
+	rootCmd.AddCommand(authCmd)
+}
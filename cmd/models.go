@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/llm/models"
+	"github.com/spf13/cobra"
+)
+
+var modelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "List models and manage the model manifest",
+	Long: `List the models OpenCode knows about. The catalog is built into OpenCode and can
+be updated with a newer manifest file in the config directory, models in that file
+replace or add to the built-in ones.`,
+	Example: `
+  # List all models
+  opencode models
+
+  # List the Anthropic models
+  opencode models --provider anthropic
+
+  # Check the manifest file in the config directory
+  opencode models check
+
+  # Show what a manifest changes compared to the built-in catalog
+  opencode models diff ./models.json
+  `,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := models.LoadManifestFile(config.ModelManifestPath()); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		provider, _ := cmd.Flags().GetString("provider")
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tPROVIDER\tCONTEXT\tMAX OUTPUT\tINPUT $/1M\tOUTPUT $/1M\tFEATURES")
+		for _, model := range models.SortedModels(models.SupportedModels) {
+			if provider != "" && string(model.Provider) != provider {
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%.2f\t%.2f\t%s\n",
+				model.ID,
+				model.Provider,
+				model.ContextWindow,
+				model.DefaultMaxTokens,
+				model.CostPer1MIn,
+				model.CostPer1MOut,
+				modelFeatures(model),
+			)
+		}
+		return w.Flush()
+	},
+}
+
+var modelsCheckCmd = &cobra.Command{
+	Use:   "check [manifest]",
+	Short: "Check a model manifest, the one in the config directory by default",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := manifestPathArg(args)
+		manifest, err := models.ReadManifest(path)
+		if err != nil {
+			return err
+		}
+
+		problems := models.CheckManifest(manifest)
+		if builtin := models.BuiltinManifest(); manifest.Version < builtin.Version {
+			problems = append(problems, fmt.Errorf("version %q is older than the built-in catalog (%s), the file would be ignored", manifest.Version, builtin.Version))
+		}
+		if len(problems) > 0 {
+			for _, problem := range problems {
+				fmt.Printf("  %v\n", problem)
+			}
+			return fmt.Errorf("%s has %d problem(s)", path, len(problems))
+		}
+		fmt.Printf("%s is valid: version %s, %d models\n", path, manifest.Version, len(manifest.Models))
+		return nil
+	},
+}
+
+var modelsDiffCmd = &cobra.Command{
+	Use:   "diff [manifest]",
+	Short: "Show how a model manifest differs from the built-in catalog",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manifest, err := models.ReadManifest(manifestPathArg(args))
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Built-in version %s, manifest version %s\n", models.BuiltinManifest().Version, manifest.Version)
+		differences := 0
+		for _, model := range manifest.Models {
+			builtin, ok := models.BuiltinModel(model.ID)
+			if !ok {
+				differences++
+				fmt.Printf("+ %s (%s, %s)\n", model.ID, model.Provider, model.APIModel)
+				continue
+			}
+			changes := models.DiffModel(builtin, model)
+			if len(changes) == 0 {
+				continue
+			}
+			differences++
+			fmt.Printf("~ %s\n", model.ID)
+			for _, change := range changes {
+				fmt.Printf("    %s: %v -> %v\n", change.Field, change.Old, change.New)
+			}
+		}
+		if differences == 0 {
+			fmt.Println("No differences")
+		}
+		return nil
+	},
+}
+
+func manifestPathArg(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	return config.ModelManifestPath()
+}
+
+func modelFeatures(model models.Model) string {
+	var features []string
+	if model.CanReason {
+		features = append(features, "reasoning")
+	}
+	if model.SupportsAttachments {
+		features = append(features, "attachments")
+	}
+	if model.UseResponsesAPI {
+		features = append(features, "responses-api")
+	}
+	if models.OverriddenModels[model.ID] {
+		features = append(features, "from manifest")
+	}
+	return strings.Join(features, ", ")
+}
+
+func init() {
+	modelsCmd.Flags().String("provider", "", "Only list the models of this provider")
+	modelsCmd.AddCommand(modelsCheckCmd, modelsDiffCmd)
+	rootCmd.AddCommand(modelsCmd)
+}
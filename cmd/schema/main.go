@@ -181,6 +181,10 @@ func generateSchema() map[string]any {
 					"type":        "string",
 					"description": "API key for the provider",
 				},
+				"apiKeyCommand": map[string]any{
+					"type":        "string",
+					"description": "Command that prints the API key, run when OpenCode starts and again when the key is rejected",
+				},
 				"disabled": map[string]any{
 					"type":        "boolean",
 					"description": "Whether the provider is disabled",
@@ -299,10 +303,266 @@ func generateSchema() map[string]any {
 					"type":        "object",
 					"description": "Additional options for the LSP server",
 				},
+				"formatOnWrite": map[string]any{
+					"type":        "array",
+					"description": "File extensions (e.g. \".go\") the server formats after the agent writes them",
+					"items": map[string]any{
+						"type": "string",
+					},
+				},
 			},
 			"required": []string{"command"},
 		},
 	}
 
+	// Add tracing configuration
+	schema["properties"].(map[string]any)["tracing"] = map[string]any{
+		"type":        "object",
+		"description": "OpenTelemetry tracing of agent turns, provider calls and tool runs",
+		"properties": map[string]any{
+			"exporter": map[string]any{
+				"type":        "string",
+				"description": "Where to export traces, tracing is disabled when not set",
+				"enum":        []string{string(config.TracingExporterOTLP), string(config.TracingExporterFile)},
+			},
+			"endpoint": map[string]any{
+				"type":        "string",
+				"description": "OTLP/HTTP endpoint for the otlp exporter",
+				"default":     "http://localhost:4318",
+			},
+			"headers": map[string]any{
+				"type":        "object",
+				"description": "HTTP headers for the otlp exporter",
+				"additionalProperties": map[string]any{
+					"type": "string",
+				},
+			},
+			"file": map[string]any{
+				"type":        "string",
+				"description": "JSON lines file for the file exporter, defaults to traces.jsonl in the data directory",
+			},
+		},
+	}
+
+	// Add shell configuration
+	schema["properties"].(map[string]any)["shell"] = map[string]any{
+		"type":        "object",
+		"description": "Shell used by the bash tool",
+		"properties": map[string]any{
+			"path": map[string]any{
+				"type":        "string",
+				"description": "Path to the shell, defaults to $SHELL or /bin/bash",
+			},
+			"args": map[string]any{
+				"type":        "array",
+				"description": "Arguments for the shell, defaults to -l",
+				"items": map[string]any{
+					"type": "string",
+				},
+			},
+			"allowCommands": map[string]any{
+				"type":        "array",
+				"description": "Read-only commands that run without asking for permission, matched by their first words (e.g. \"make lint\")",
+				"items": map[string]any{
+					"type": "string",
+				},
+			},
+			"denyCommands": map[string]any{
+				"type":        "array",
+				"description": "Commands that are never run, matched by their first words (e.g. \"git push\")",
+				"items": map[string]any{
+					"type": "string",
+				},
+			},
+			"sandbox": map[string]any{
+				"type":        "object",
+				"description": "Run commands in a bubblewrap sandbox where only the working directory and the listed paths are writable (Linux only)",
+				"properties": map[string]any{
+					"enabled": map[string]any{
+						"type":        "boolean",
+						"description": "Whether commands run in the sandbox",
+						"default":     false,
+					},
+					"allowWrite": map[string]any{
+						"type":        "array",
+						"description": "Paths that are writable in addition to the working directory and the temporary directory",
+						"items": map[string]any{
+							"type": "string",
+						},
+					},
+					"network": map[string]any{
+						"type":        "boolean",
+						"description": "Whether commands in the sandbox can access the network",
+						"default":     false,
+					},
+					"autoApprove": map[string]any{
+						"type":        "boolean",
+						"description": "Run sandboxed commands without asking for permission",
+						"default":     false,
+					},
+				},
+			},
+		},
+	}
+
+	// Add network configuration
+	schema["properties"].(map[string]any)["network"] = map[string]any{
+		"type":        "object",
+		"description": "Settings for every outgoing HTTP request, including providers, tools, MCP servers and local model discovery",
+		"properties": map[string]any{
+			"proxy": map[string]any{
+				"type":        "string",
+				"description": "Proxy URL for HTTP and HTTPS requests, HTTP_PROXY and HTTPS_PROXY are used when not set",
+			},
+			"noProxy": map[string]any{
+				"type":        "array",
+				"description": "Hosts, domains and CIDRs that are reached without the proxy",
+				"items": map[string]any{
+					"type": "string",
+				},
+			},
+			"caCerts": map[string]any{
+				"type":        "array",
+				"description": "PEM files with CA certificates to trust in addition to the system ones",
+				"items": map[string]any{
+					"type": "string",
+				},
+			},
+			"clientCert": map[string]any{
+				"type":        "string",
+				"description": "PEM file with a client certificate for mutual TLS",
+			},
+			"clientKey": map[string]any{
+				"type":        "string",
+				"description": "PEM file with the key of the client certificate, defaults to clientCert",
+			},
+			"connectTimeout": map[string]any{
+				"type":        "integer",
+				"description": "Seconds allowed for connecting and the TLS handshake",
+				"minimum":     1,
+			},
+			"requestTimeout": map[string]any{
+				"type":        "integer",
+				"description": "Seconds allowed for requests made by tools and model discovery, model responses are not limited",
+				"minimum":     1,
+			},
+		},
+	}
+
+	// Add fetch tool configuration
+	schema["properties"].(map[string]any)["fetch"] = map[string]any{
+		"type":        "object",
+		"description": "Domain policies, limits and caching for the fetch tool",
+		"properties": map[string]any{
+			"autoApproveDomains": map[string]any{
+				"type":        "array",
+				"description": "Domains fetched without asking for permission, including their subdomains (e.g. \"pkg.go.dev\", \"*.github.io\"). Other domains are still fetched when the user approves",
+				"items": map[string]any{
+					"type": "string",
+				},
+			},
+			"denyDomains": map[string]any{
+				"type":        "array",
+				"description": "Domains that are never fetched, including their subdomains",
+				"items": map[string]any{
+					"type": "string",
+				},
+			},
+			"maxSize": map[string]any{
+				"type":        "integer",
+				"description": "Largest response in bytes, larger ones are refused. When not set, responses are cut at 5MB",
+				"minimum":     1,
+			},
+			"contentTypes": map[string]any{
+				"type":        "array",
+				"description": "Content types that can be fetched (e.g. \"text/*\", \"application/json\"), all when not set",
+				"items": map[string]any{
+					"type": "string",
+				},
+			},
+			"cache": map[string]any{
+				"type":        "object",
+				"description": "On-disk cache of fetched responses in the data directory",
+				"properties": map[string]any{
+					"enabled": map[string]any{
+						"type":        "boolean",
+						"description": "Whether fetched responses are cached",
+						"default":     false,
+					},
+					"ttl": map[string]any{
+						"type":        "integer",
+						"description": "Seconds a cached response is used before it is revalidated with the server",
+						"default":     900,
+						"minimum":     1,
+					},
+				},
+			},
+		},
+	}
+
+	// Add sourcegraph tool configuration
+	schema["properties"].(map[string]any)["sourcegraph"] = map[string]any{
+		"type":        "object",
+		"description": "The Sourcegraph instance searched by the sourcegraph tool",
+		"properties": map[string]any{
+			"endpoint": map[string]any{
+				"type":        "string",
+				"description": "Base URL of the Sourcegraph instance, SRC_ENDPOINT is used when not set",
+				"default":     "https://sourcegraph.com",
+			},
+			"token": map[string]any{
+				"type":        "string",
+				"description": "Access token for the instance, SRC_ACCESS_TOKEN is used when not set",
+			},
+			"repoFilters": map[string]any{
+				"type":        "array",
+				"description": "Filters added to queries without a repo: or context: filter of their own (e.g. \"repo:^git\\.example\\.com/team/\")",
+				"items": map[string]any{
+					"type": "string",
+				},
+			},
+		},
+	}
+
+	// Add web search tool configuration
+	schema["properties"].(map[string]any)["webSearch"] = map[string]any{
+		"type":        "object",
+		"description": "The search engine used by the web_search tool, which is only available when one is configured",
+		"properties": map[string]any{
+			"backend": map[string]any{
+				"type":        "string",
+				"description": "The search backend, searxng when only an endpoint is set",
+				"enum":        []string{"searxng", "brave"},
+			},
+			"endpoint": map[string]any{
+				"type":        "string",
+				"description": "Base URL of the SearxNG instance (e.g. \"http://localhost:8888\"), or another API URL for the backend",
+			},
+			"apiKey": map[string]any{
+				"type":        "string",
+				"description": "API key for backends that need one",
+			},
+			"maxResults": map[string]any{
+				"type":        "integer",
+				"description": "Number of results returned when the AI doesn't ask for a number",
+				"default":     5,
+				"minimum":     1,
+				"maximum":     20,
+			},
+		},
+	}
+
+	// Add ask_user tool configuration
+	schema["properties"].(map[string]any)["askUser"] = map[string]any{
+		"type":        "object",
+		"description": "Settings of the ask_user tool, which lets the AI ask the user a question",
+		"properties": map[string]any{
+			"defaultAnswer": map[string]any{
+				"type":        "string",
+				"description": "Answer the tool returns in non-interactive mode, where nobody can answer",
+			},
+		},
+	}
+
 	return schema
 }
@@ -14,11 +14,15 @@ import (
 	"github.com/opencode-ai/opencode/internal/format"
 	"github.com/opencode-ai/opencode/internal/history"
 	"github.com/opencode-ai/opencode/internal/llm/agent"
+	"github.com/opencode-ai/opencode/internal/llm/tools"
+	"github.com/opencode-ai/opencode/internal/llm/tools/shell"
 	"github.com/opencode-ai/opencode/internal/logging"
 	"github.com/opencode-ai/opencode/internal/lsp"
 	"github.com/opencode-ai/opencode/internal/message"
 	"github.com/opencode-ai/opencode/internal/permission"
+	"github.com/opencode-ai/opencode/internal/question"
 	"github.com/opencode-ai/opencode/internal/session"
+	"github.com/opencode-ai/opencode/internal/todo"
 	"github.com/opencode-ai/opencode/internal/tui/theme"
 )
 
@@ -27,6 +31,9 @@ type App struct {
 	Messages    message.Service
 	History     history.Service
 	Permissions permission.Service
+	Processes   shell.BackgroundService
+	Todos       todo.Service
+	Questions   question.Service
 
 	CoderAgent agent.Service
 
@@ -50,6 +57,9 @@ func New(ctx context.Context, conn *sql.DB) (*App, error) {
 		Messages:    messages,
 		History:     files,
 		Permissions: permission.NewPermissionService(),
+		Processes:   shell.NewBackgroundService(),
+		Todos:       todo.NewService(q),
+		Questions:   question.NewService(),
 		LSPClients:  make(map[string]*lsp.Client),
 	}
 
@@ -59,17 +69,24 @@ func New(ctx context.Context, conn *sql.DB) (*App, error) {
 	// Initialize LSP clients in the background
 	go app.initLSPClients(ctx)
 
+	// Remove the tool outputs saved for sessions that are no longer used
+	go tools.PruneSavedOutputs()
+
 	var err error
 	app.CoderAgent, err = agent.NewAgent(
 		config.AgentCoder,
 		app.Sessions,
 		app.Messages,
+		app.Todos,
 		agent.CoderAgentTools(
 			app.Permissions,
 			app.Sessions,
 			app.Messages,
 			app.History,
 			app.LSPClients,
+			app.Processes,
+			app.Todos,
+			app.Questions,
 		),
 	)
 	if err != nil {
@@ -127,6 +144,8 @@ func (a *App) RunNonInteractive(ctx context.Context, prompt string, outputFormat
 
 	// Automatically approve all permission requests for this non-interactive session
 	a.Permissions.AutoApproveSession(sess.ID)
+	// Nobody can answer the questions of the agent either
+	a.Questions.NoUserSession(sess.ID)
 
 	done, err := a.CoderAgent.Run(ctx, sess.ID, prompt)
 	if err != nil {
@@ -162,6 +181,9 @@ func (a *App) RunNonInteractive(ctx context.Context, prompt string, outputFormat
 
 // Shutdown performs a clean shutdown of the application
 func (app *App) Shutdown() {
+	// Stop the background processes started by the agent
+	app.Processes.Shutdown()
+
 	// Cancel all watcher goroutines
 	app.cancelFuncsMutex.Lock()
 	for _, cancel := range app.watcherCancelFuncs {
@@ -9,7 +9,9 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
+	"github.com/opencode-ai/opencode/internal/httpclient"
 	"github.com/opencode-ai/opencode/internal/llm/models"
 	"github.com/opencode-ai/opencode/internal/logging"
 	"github.com/spf13/viper"
@@ -52,8 +54,9 @@ type Agent struct {
 
 // Provider defines configuration for an LLM provider.
 type Provider struct {
-	APIKey   string `json:"apiKey"`
-	Disabled bool   `json:"disabled"`
+	APIKey        string `json:"apiKey"`
+	APIKeyCommand string `json:"apiKeyCommand,omitempty"` // Command printing the API key, e.g. "pass show anthropic"
+	Disabled      bool   `json:"disabled"`
 }
 
 // Data defines storage configuration.
@@ -67,6 +70,9 @@ type LSPConfig struct {
 	Command  string   `json:"command"`
 	Args     []string `json:"args"`
 	Options  any      `json:"options"`
+	// File extensions, like ".go", that are formatted by the server after the
+	// agent writes them
+	FormatOnWrite []string `json:"formatOnWrite,omitempty"`
 }
 
 // TUIConfig defines the configuration for the Terminal User Interface.
@@ -76,8 +82,88 @@ type TUIConfig struct {
 
 // ShellConfig defines the configuration for the shell used by the bash tool.
 type ShellConfig struct {
-	Path string   `json:"path,omitempty"`
-	Args []string `json:"args,omitempty"`
+	Path          string        `json:"path,omitempty"`
+	Args          []string      `json:"args,omitempty"`
+	Sandbox       SandboxConfig `json:"sandbox,omitempty"`
+	AllowCommands []string      `json:"allowCommands,omitempty"` // Read-only commands that run without asking, like "make lint"
+	DenyCommands  []string      `json:"denyCommands,omitempty"`  // Commands that are never run
+}
+
+// SandboxConfig runs the commands of the bash tool in a bubblewrap sandbox
+// where only the working directory and the listed paths are writable.
+type SandboxConfig struct {
+	Enabled     bool     `json:"enabled,omitempty"`
+	AllowWrite  []string `json:"allowWrite,omitempty"`  // Extra writable paths, ~ is expanded
+	Network     bool     `json:"network,omitempty"`     // Allow network access from the sandbox
+	AutoApprove bool     `json:"autoApprove,omitempty"` // Run sandboxed commands without asking for permission
+}
+
+// TracingExporter defines where traces are exported to.
+type TracingExporter string
+
+// Supported tracing exporters
+const (
+	TracingExporterOTLP TracingExporter = "otlp"
+	TracingExporterFile TracingExporter = "file"
+)
+
+// TracingConfig defines the OpenTelemetry export of agent turns, provider calls
+// and tool runs. Tracing is disabled when no exporter is set.
+type TracingConfig struct {
+	Exporter TracingExporter   `json:"exporter,omitempty"`
+	Endpoint string            `json:"endpoint,omitempty"` // OTLP/HTTP endpoint, e.g. http://localhost:4318
+	Headers  map[string]string `json:"headers,omitempty"`
+	File     string            `json:"file,omitempty"` // JSON lines file, defaults to traces.jsonl in the data directory
+}
+
+// NetworkConfig applies to every outgoing HTTP request: providers, tools, MCP
+// servers and local model discovery.
+type NetworkConfig struct {
+	Proxy          string   `json:"proxy,omitempty"`          // Proxy URL, HTTP_PROXY/HTTPS_PROXY are used when empty
+	NoProxy        []string `json:"noProxy,omitempty"`        // Hosts, domains and CIDRs that bypass the proxy
+	CACerts        []string `json:"caCerts,omitempty"`        // Extra PEM files with trusted CA certificates
+	ClientCert     string   `json:"clientCert,omitempty"`     // PEM client certificate for mutual TLS
+	ClientKey      string   `json:"clientKey,omitempty"`      // PEM key of the client certificate
+	ConnectTimeout int      `json:"connectTimeout,omitempty"` // Seconds for connecting and the TLS handshake
+	RequestTimeout int      `json:"requestTimeout,omitempty"` // Seconds for requests made by tools and discovery
+}
+
+// FetchConfig defines which URLs the fetch tool can get and how responses
+// are cached.
+type FetchConfig struct {
+	AutoApproveDomains []string         `json:"autoApproveDomains,omitempty"` // Domains fetched without asking for permission, other domains can still be fetched once approved
+	DenyDomains        []string         `json:"denyDomains,omitempty"`        // Domains that are never fetched
+	MaxSize            int64            `json:"maxSize,omitempty"`            // Larger responses are refused, when not set they are cut at 5MB
+	ContentTypes       []string         `json:"contentTypes,omitempty"`       // Accepted content types like "text/*", all when empty
+	Cache              FetchCacheConfig `json:"cache,omitempty"`
+}
+
+// FetchCacheConfig defines the on-disk cache of fetched responses.
+type FetchCacheConfig struct {
+	Enabled bool `json:"enabled,omitempty"`
+	TTL     int  `json:"ttl,omitempty"` // Seconds a response is used without revalidating it, 15 minutes when not set
+}
+
+// SourcegraphConfig defines the Sourcegraph instance the sourcegraph tool
+// searches.
+type SourcegraphConfig struct {
+	Endpoint    string   `json:"endpoint,omitempty"`    // Base URL of the instance, https://sourcegraph.com when not set
+	Token       string   `json:"token,omitempty"`       // Access token sent with every search
+	RepoFilters []string `json:"repoFilters,omitempty"` // Filters added to queries that have no repo: or context: filter
+}
+
+// WebSearchConfig defines the search engine used by the web_search tool.
+// The tool is only available when a backend is configured.
+type WebSearchConfig struct {
+	Backend    string `json:"backend,omitempty"`    // searxng or brave, searxng when only an endpoint is set
+	Endpoint   string `json:"endpoint,omitempty"`   // Base URL of the SearxNG instance, or another API URL for the backend
+	APIKey     string `json:"apiKey,omitempty"`     // Key for backends that need one
+	MaxResults int    `json:"maxResults,omitempty"` // Results returned when the AI doesn't ask for a number, 5 when not set
+}
+
+// AskUserConfig defines how the ask_user tool behaves without a user.
+type AskUserConfig struct {
+	DefaultAnswer string `json:"defaultAnswer,omitempty"` // Returned in non-interactive mode, where nobody can answer
 }
 
 // Config is the main configuration structure for the application.
@@ -94,6 +180,12 @@ type Config struct {
 	TUI          TUIConfig                         `json:"tui"`
 	Shell        ShellConfig                       `json:"shell,omitempty"`
 	AutoCompact  bool                              `json:"autoCompact,omitempty"`
+	Tracing      TracingConfig                     `json:"tracing,omitempty"`
+	Network      NetworkConfig                     `json:"network,omitempty"`
+	Fetch        FetchConfig                       `json:"fetch,omitempty"`
+	Sourcegraph  SourcegraphConfig                 `json:"sourcegraph,omitempty"`
+	WebSearch    WebSearchConfig                   `json:"webSearch,omitempty"`
+	AskUser      AskUserConfig                     `json:"askUser,omitempty"`
 }
 
 // Application constants
@@ -148,6 +240,16 @@ func Load(workingDir string, debug bool) (*Config, error) {
 	// Load and merge local config
 	mergeLocalConfig(workingDir)
 
+	// Network settings are needed before the first request goes out
+	if err := configureNetwork(); err != nil {
+		return cfg, err
+	}
+	// A newer model catalog in the config directory replaces the built-in one
+	if err := models.LoadManifestFile(ModelManifestPath()); err != nil {
+		logging.Warn("failed to load model manifest, using the built-in catalog", "error", err)
+	}
+	models.DiscoverLocalModels()
+
 	setProviderDefaults()
 
 	// Apply configuration to the struct
@@ -241,6 +343,14 @@ func setDefaults(debug bool) {
 	viper.SetDefault("shell.path", shellPath)
 	viper.SetDefault("shell.args", []string{"-l"})
 
+	// The variables the Sourcegraph CLI uses
+	if endpoint := os.Getenv("SRC_ENDPOINT"); endpoint != "" {
+		viper.SetDefault("sourcegraph.endpoint", endpoint)
+	}
+	if token := os.Getenv("SRC_ACCESS_TOKEN"); token != "" {
+		viper.SetDefault("sourcegraph.token", token)
+	}
+
 	if debug {
 		viper.SetDefault("debug", true)
 		viper.Set("log.level", "debug")
@@ -250,6 +360,37 @@ func setDefaults(debug bool) {
 	}
 }
 
+// ModelManifestPath returns the path of the model manifest that overrides the
+// built-in catalog, e.g. ~/.config/opencode/models.json.
+func ModelManifestPath() string {
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		configDir = filepath.Join(os.Getenv("HOME"), ".config")
+	}
+	return filepath.Join(configDir, appName, models.ManifestFileName)
+}
+
+// configureNetwork sets up the HTTP transport shared by all outgoing requests.
+func configureNetwork() error {
+	var network NetworkConfig
+	if err := viper.UnmarshalKey("network", &network); err != nil {
+		return fmt.Errorf("failed to unmarshal network config: %w", err)
+	}
+	err := httpclient.Configure(httpclient.Options{
+		Proxy:          network.Proxy,
+		NoProxy:        network.NoProxy,
+		CACerts:        network.CACerts,
+		ClientCert:     network.ClientCert,
+		ClientKey:      network.ClientKey,
+		ConnectTimeout: time.Duration(network.ConnectTimeout) * time.Second,
+		RequestTimeout: time.Duration(network.RequestTimeout) * time.Second,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to configure network: %w", err)
+	}
+	return nil
+}
+
 // setProviderDefaults configures LLM provider defaults based on provider provided by
 // environment variables and configuration file.
 func setProviderDefaults() {
@@ -284,6 +425,9 @@ func setProviderDefaults() {
 		}
 	}
 
+	// Keys from commands and the keyring count for the default models too
+	resolveCredentials()
+
 	// Use this order to set the default models
 	// 1. Copilot
 	// 2. Anthropic
@@ -637,6 +781,14 @@ func Validate() error {
 		}
 	}
 
+	// Validate tracing configuration
+	switch cfg.Tracing.Exporter {
+	case "", TracingExporterOTLP, TracingExporterFile:
+	default:
+		logging.Warn("unknown tracing exporter, disabling tracing", "exporter", cfg.Tracing.Exporter)
+		cfg.Tracing.Exporter = ""
+	}
+
 	return nil
 }
 
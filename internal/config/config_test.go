@@ -0,0 +1,47 @@
+package config
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opencode-ai/opencode/internal/httpclient"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigureNetwork(t *testing.T) {
+	viper.Reset()
+	viper.SetConfigType("json")
+	require.NoError(t, viper.ReadConfig(strings.NewReader(`{"network": {
+		"proxy": "http://proxy.example:3128",
+		"noProxy": ["internal.example"],
+		"requestTimeout": 30
+	}}`)))
+	t.Cleanup(func() {
+		viper.Reset()
+		httpclient.Configure(httpclient.Options{})
+	})
+
+	require.NoError(t, configureNetwork())
+	assert.Equal(t, 30*time.Second, httpclient.Timeout(time.Minute))
+	for url, want := range map[string]string{
+		"https://api.example/v1":      "http://proxy.example:3128",
+		"https://internal.example/v1": "",
+	} {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		proxyURL, err := httpclient.Proxy()(req)
+		require.NoError(t, err)
+		if want == "" {
+			assert.Nil(t, proxyURL, url)
+		} else {
+			assert.Equal(t, want, proxyURL.String(), url)
+		}
+	}
+
+	viper.Set("network.caCerts", []string{"/nonexistent/ca.pem"})
+	assert.ErrorContains(t, configureNetwork(), "failed to read CA certificates")
+}
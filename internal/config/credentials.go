@@ -0,0 +1,220 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/opencode-ai/opencode/internal/llm/models"
+	"github.com/opencode-ai/opencode/internal/logging"
+	"github.com/spf13/viper"
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	keyringService   = "opencode"
+	apiKeyCmdTimeout = 30 * time.Second
+)
+
+// CredentialProviders are the providers that authenticate with an API key.
+var CredentialProviders = []models.ModelProvider{
+	models.ProviderAnthropic,
+	models.ProviderOpenAI,
+	models.ProviderGemini,
+	models.ProviderGROQ,
+	models.ProviderOpenRouter,
+	models.ProviderXAI,
+	models.ProviderAzure,
+}
+
+// Where the API key of a provider came from, only these keys can be loaded
+// again
+const (
+	keyFromCommand = "command"
+	keyFromKeyring = "keyring"
+)
+
+var (
+	// API keys refreshed after the provider rejected the one loaded at startup
+	apiKeysMu sync.RWMutex
+	apiKeys   = map[models.ModelProvider]string{}
+	// By provider, for the keys from an apiKeyCommand or the keyring
+	apiKeySources = map[models.ModelProvider]string{}
+
+	// Set once the keyring failed so we don't keep asking an absent service
+	keyringUnavailable atomic.Bool
+)
+
+// ErrAPIKeyNotRefreshable is returned when the API key of a provider does not
+// come from a command or the keyring, so there is nothing to reload.
+var ErrAPIKeyNotRefreshable = errors.New("API key can't be refreshed")
+
+// resolveCredentials loads the API keys that come from an apiKeyCommand or the
+// OS keyring. They are kept in memory and never written to the config file.
+// Only providers in the config are looked up, so the keyring isn't asked,
+// and maybe unlocked, for providers that aren't used.
+func resolveCredentials() {
+	for name := range viper.GetStringMap("providers") {
+		provider := models.ModelProvider(name)
+		if viper.GetBool(fmt.Sprintf("providers.%s.disabled", provider)) {
+			continue
+		}
+		key, source, err := lookupAPIKey(provider)
+		if err != nil {
+			logging.Warn("failed to load API key", "provider", provider, "error", err)
+			continue
+		}
+		if key != "" {
+			viper.Set(fmt.Sprintf("providers.%s.apiKey", provider), key)
+			apiKeysMu.Lock()
+			apiKeySources[provider] = source
+			apiKeysMu.Unlock()
+		}
+	}
+}
+
+// lookupAPIKey runs the apiKeyCommand of the provider if it has one. Otherwise
+// the keyring is checked for credential providers without a key. It returns
+// an empty key when neither applies, and where the key came from.
+func lookupAPIKey(provider models.ModelProvider) (string, string, error) {
+	if command := viper.GetString(fmt.Sprintf("providers.%s.apiKeyCommand", provider)); command != "" {
+		key, err := runAPIKeyCommand(command)
+		return key, keyFromCommand, err
+	}
+	if strings.TrimSpace(viper.GetString(fmt.Sprintf("providers.%s.apiKey", provider))) != "" {
+		return "", "", nil
+	}
+	if !slices.Contains(CredentialProviders, provider) {
+		return "", "", nil
+	}
+	key, err := keyringAPIKey(provider)
+	return key, keyFromKeyring, err
+}
+
+func runAPIKeyCommand(command string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), apiKeyCmdTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("apiKeyCommand %q failed: %w: %s", command, err, msg)
+		}
+		return "", fmt.Errorf("apiKeyCommand %q failed: %w", command, err)
+	}
+	key := strings.TrimSpace(string(out))
+	if key == "" {
+		return "", fmt.Errorf("apiKeyCommand %q printed no key", command)
+	}
+	return key, nil
+}
+
+func keyringAPIKey(provider models.ModelProvider) (string, error) {
+	if keyringUnavailable.Load() {
+		return "", nil
+	}
+	key, err := keyring.Get(keyringService, string(provider))
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		keyringUnavailable.Store(true)
+		logging.Debug("OS keyring is not available", "error", err)
+		return "", nil
+	}
+	return key, nil
+}
+
+// APIKey returns the current API key of the provider.
+func APIKey(provider models.ModelProvider) string {
+	apiKeysMu.RLock()
+	key, ok := apiKeys[provider]
+	apiKeysMu.RUnlock()
+	if ok {
+		return key
+	}
+	return cfg.Providers[provider].APIKey
+}
+
+// RefreshAPIKey loads the API key of the provider again, from its
+// apiKeyCommand or the keyring. Providers call it when their key was rejected.
+// Keys from the config or the environment can't be refreshed.
+func RefreshAPIKey(provider models.ModelProvider) (string, error) {
+	apiKeysMu.RLock()
+	source := apiKeySources[provider]
+	apiKeysMu.RUnlock()
+
+	var key string
+	var err error
+	switch source {
+	case keyFromCommand:
+		key, err = runAPIKeyCommand(viper.GetString(fmt.Sprintf("providers.%s.apiKeyCommand", provider)))
+	case keyFromKeyring:
+		key, err = keyringAPIKey(provider)
+		if err == nil && key == "" {
+			return "", ErrAPIKeyNotRefreshable
+		}
+	default:
+		return "", ErrAPIKeyNotRefreshable
+	}
+	if err != nil {
+		return "", err
+	}
+	if key == APIKey(provider) {
+		// Trying again with the same key would fail the same way
+		return "", ErrAPIKeyNotRefreshable
+	}
+
+	apiKeysMu.Lock()
+	apiKeys[provider] = key
+	apiKeysMu.Unlock()
+	return key, nil
+}
+
+// StoreAPIKey saves the API key of the provider in the OS keyring.
+func StoreAPIKey(provider models.ModelProvider, key string) error {
+	if err := keyring.Set(keyringService, string(provider), key); err != nil {
+		return fmt.Errorf("failed to store API key in the keyring: %w", err)
+	}
+	return nil
+}
+
+// DeleteAPIKey removes the API key of the provider from the OS keyring.
+func DeleteAPIKey(provider models.ModelProvider) error {
+	err := keyring.Delete(keyringService, string(provider))
+	if errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("no API key stored for %s", provider)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove API key from the keyring: %w", err)
+	}
+	return nil
+}
+
+// HasStoredAPIKey reports whether the keyring holds an API key for the provider.
+func HasStoredAPIKey(provider models.ModelProvider) (bool, error) {
+	_, err := keyring.Get(keyringService, string(provider))
+	if errors.Is(err, keyring.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opencode-ai/opencode/internal/llm/models"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+// setupCredentials loads configJSON as the config file, with an empty
+// keyring in memory.
+func setupCredentials(t *testing.T, configJSON string) {
+	t.Helper()
+	viper.Reset()
+	viper.SetConfigType("json")
+	require.NoError(t, viper.ReadConfig(strings.NewReader(configJSON)))
+	keyring.MockInit()
+	apiKeys = map[models.ModelProvider]string{}
+	apiKeySources = map[models.ModelProvider]string{}
+	keyringUnavailable.Store(false)
+
+	previous := cfg
+	cfg = &Config{Providers: map[models.ModelProvider]Provider{}}
+	t.Cleanup(func() {
+		viper.Reset()
+		cfg = previous
+	})
+}
+
+func TestResolveCredentials(t *testing.T) {
+	setupCredentials(t, `{"providers": {
+		"anthropic": {"apiKeyCommand": "echo command-key", "apiKey": "config-key"},
+		"openai": {},
+		"gemini": {"apiKey": "config-key"},
+		"groq": {}
+	}}`)
+	// Like setProviderDefaults does for GROQ_API_KEY
+	viper.SetDefault("providers.groq.apiKey", "env-key")
+	for provider, key := range map[models.ModelProvider]string{
+		models.ProviderOpenAI: "keyring-key",
+		models.ProviderGemini: "keyring-key",
+		models.ProviderGROQ:   "keyring-key",
+		models.ProviderXAI:    "keyring-key",
+	} {
+		require.NoError(t, StoreAPIKey(provider, key))
+	}
+
+	resolveCredentials()
+
+	for provider, want := range map[models.ModelProvider]string{
+		// apiKeyCommand comes first, then apiKey and the environment, then
+		// the keyring
+		models.ProviderAnthropic: "command-key",
+		models.ProviderOpenAI:    "keyring-key",
+		models.ProviderGemini:    "config-key",
+		models.ProviderGROQ:      "env-key",
+		// Not in the config
+		models.ProviderXAI: "",
+	} {
+		assert.Equal(t, want, viper.GetString("providers."+string(provider)+".apiKey"), provider)
+	}
+}
+
+func TestResolveCredentialsOnlyReadsKeyringForListedProviders(t *testing.T) {
+	setupCredentials(t, `{"providers": {"anthropic": {"apiKey": "config-key"}}}`)
+	keyring.MockInitWithError(errors.New("the keyring is locked"))
+
+	resolveCredentials()
+	assert.False(t, keyringUnavailable.Load())
+
+	setupCredentials(t, `{"providers": {"anthropic": {}}}`)
+	keyring.MockInitWithError(errors.New("the keyring is locked"))
+
+	resolveCredentials()
+	assert.True(t, keyringUnavailable.Load())
+	assert.Equal(t, "", viper.GetString("providers.anthropic.apiKey"))
+}
+
+func TestRefreshAPIKey(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("command-key-1\n"), 0o600))
+	setupCredentials(t, `{"providers": {
+		"anthropic": {"apiKeyCommand": "cat '`+keyFile+`'"},
+		"openai": {},
+		"gemini": {"apiKey": "config-key"}
+	}}`)
+	viper.SetDefault("providers.groq.apiKey", "env-key")
+	require.NoError(t, StoreAPIKey(models.ProviderOpenAI, "keyring-key-1"))
+
+	resolveCredentials()
+	for _, provider := range []models.ModelProvider{models.ProviderAnthropic, models.ProviderOpenAI, models.ProviderGemini, models.ProviderGROQ} {
+		cfg.Providers[provider] = Provider{APIKey: viper.GetString("providers." + string(provider) + ".apiKey")}
+	}
+
+	// The command prints the same key, trying it again would not help
+	_, err := RefreshAPIKey(models.ProviderAnthropic)
+	assert.ErrorIs(t, err, ErrAPIKeyNotRefreshable)
+
+	require.NoError(t, os.WriteFile(keyFile, []byte("command-key-2\n"), 0o600))
+	key, err := RefreshAPIKey(models.ProviderAnthropic)
+	require.NoError(t, err)
+	assert.Equal(t, "command-key-2", key)
+	assert.Equal(t, "command-key-2", APIKey(models.ProviderAnthropic))
+
+	require.NoError(t, StoreAPIKey(models.ProviderOpenAI, "keyring-key-2"))
+	key, err = RefreshAPIKey(models.ProviderOpenAI)
+	require.NoError(t, err)
+	assert.Equal(t, "keyring-key-2", key)
+
+	// Keys from the config and the environment are never reloaded, not
+	// even from the keyring
+	require.NoError(t, StoreAPIKey(models.ProviderGemini, "keyring-key"))
+	_, err = RefreshAPIKey(models.ProviderGemini)
+	assert.ErrorIs(t, err, ErrAPIKeyNotRefreshable)
+	_, err = RefreshAPIKey(models.ProviderGROQ)
+	assert.ErrorIs(t, err, ErrAPIKeyNotRefreshable)
+}
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS todos (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    content TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    position INTEGER NOT NULL,
+    created_at INTEGER NOT NULL,  -- Unix timestamp in milliseconds
+    updated_at INTEGER NOT NULL,  -- Unix timestamp in milliseconds
+    FOREIGN KEY (session_id) REFERENCES sessions (id) ON DELETE CASCADE,
+    UNIQUE(session_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_todos_session_id ON todos (session_id);
+
+CREATE TRIGGER IF NOT EXISTS update_todos_updated_at
+AFTER UPDATE ON todos
+BEGIN
+UPDATE todos SET updated_at = strftime('%s', 'now')
+WHERE id = new.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS update_todos_updated_at;
+DROP TABLE IF EXISTS todos;
+-- +goose StatementEnd
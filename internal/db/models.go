@@ -41,3 +41,13 @@ type Session struct {
 	CreatedAt        int64          `json:"created_at"`
 	SummaryMessageID sql.NullString `json:"summary_message_id"`
 }
+
+type Todo struct {
+	ID        string `json:"id"`
+	SessionID string `json:"session_id"`
+	Content   string `json:"content"`
+	Status    string `json:"status"`
+	Position  int64  `json:"position"`
+	CreatedAt int64  `json:"created_at"`
+	UpdatedAt int64  `json:"updated_at"`
+}
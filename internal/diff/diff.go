@@ -846,6 +846,33 @@ func FormatDiff(diffText string, opts ...SideBySideOption) (string, error) {
 	return sb.String(), nil
 }
 
+// FormatFileDiffs renders the diffs of several files one after the other,
+// each under its title, e.g. the path of the file.
+func FormatFileDiffs(titles, diffTexts []string, opts ...SideBySideOption) (string, error) {
+	t := theme.CurrentTheme()
+	config := NewSideBySideConfig(opts...)
+	titleStyle := lipgloss.NewStyle().
+		Foreground(t.TextMuted()).
+		Background(t.Background()).
+		Bold(true).
+		Width(config.TotalWidth)
+
+	var sb strings.Builder
+	for i, diffText := range diffTexts {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(titleStyle.Render(titles[i]))
+		sb.WriteString("\n")
+		formatted, err := FormatDiff(diffText, opts...)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(formatted)
+	}
+	return sb.String(), nil
+}
+
 // GenerateDiff creates a unified diff from two file contents
 func GenerateDiff(beforeContent, afterContent, fileName string) (string, int, int) {
 	// remove the cwd prefix and ensure consistent path format
@@ -0,0 +1,163 @@
+// Package httpclient builds the HTTP clients used for every outgoing request,
+// so proxy, certificate and timeout settings apply to providers, tools, MCP
+// servers and local model discovery alike.
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Options configures the shared transport.
+type Options struct {
+	Proxy          string        // Proxy URL for HTTP and HTTPS requests, the environment is used when empty
+	NoProxy        []string      // Hosts, domains and CIDRs that are reached directly
+	CACerts        []string      // PEM files with certificates trusted on top of the system pool
+	ClientCert     string        // PEM file with a client certificate for mutual TLS
+	ClientKey      string        // PEM file with the key of the client certificate
+	ConnectTimeout time.Duration // Limit for dialing and the TLS handshake
+	RequestTimeout time.Duration // Limit for whole requests made by tools and discovery
+}
+
+var (
+	// Settings always start from Go's defaults, not from an earlier Configure
+	baseTransport = http.DefaultTransport.(*http.Transport).Clone()
+
+	mu             sync.RWMutex
+	transport      = http.DefaultTransport
+	requestTimeout time.Duration
+)
+
+// Configure replaces the shared transport. It also becomes
+// http.DefaultTransport for libraries that don't take a client.
+func Configure(opts Options) error {
+	t, err := newTransport(opts)
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	transport = t
+	requestTimeout = opts.RequestTimeout
+	http.DefaultTransport = t
+	return nil
+}
+
+func newTransport(opts Options) (*http.Transport, error) {
+	t := baseTransport.Clone()
+
+	if opts.Proxy != "" || len(opts.NoProxy) > 0 {
+		proxyConfig := httpproxy.FromEnvironment()
+		if opts.Proxy != "" {
+			if _, err := url.Parse(opts.Proxy); err != nil {
+				return nil, fmt.Errorf("invalid proxy URL: %w", err)
+			}
+			proxyConfig.HTTPProxy = opts.Proxy
+			proxyConfig.HTTPSProxy = opts.Proxy
+		}
+		if len(opts.NoProxy) > 0 {
+			proxyConfig.NoProxy = strings.Join(opts.NoProxy, ",")
+		}
+		proxyFunc := proxyConfig.ProxyFunc()
+		t.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+
+	if len(opts.CACerts) > 0 || opts.ClientCert != "" {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if len(opts.CACerts) > 0 {
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			for _, file := range opts.CACerts {
+				pem, err := os.ReadFile(file)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read CA certificates: %w", err)
+				}
+				if !pool.AppendCertsFromPEM(pem) {
+					return nil, fmt.Errorf("no certificates found in %s", file)
+				}
+			}
+			tlsConfig.RootCAs = pool
+		}
+		if opts.ClientCert != "" {
+			keyFile := opts.ClientKey
+			if keyFile == "" {
+				// The key can be in the same file as the certificate
+				keyFile = opts.ClientCert
+			}
+			cert, err := tls.LoadX509KeyPair(opts.ClientCert, keyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load client certificate: %w", err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		t.TLSClientConfig = tlsConfig
+	}
+
+	if opts.ConnectTimeout > 0 {
+		dialer := &net.Dialer{
+			Timeout:   opts.ConnectTimeout,
+			KeepAlive: 30 * time.Second,
+		}
+		t.DialContext = dialer.DialContext
+		t.TLSHandshakeTimeout = opts.ConnectTimeout
+	}
+	return t, nil
+}
+
+// Transport returns the shared transport.
+func Transport() http.RoundTripper {
+	mu.RLock()
+	defer mu.RUnlock()
+	return transport
+}
+
+// Proxy returns the proxy function of the shared transport, for libraries
+// that take it but not a client.
+func Proxy() func(*http.Request) (*url.URL, error) {
+	if t, ok := Transport().(*http.Transport); ok {
+		return t.Proxy
+	}
+	return http.ProxyFromEnvironment
+}
+
+// TLSConfig returns a copy of the TLS settings of the shared transport, or nil
+// when it has none.
+func TLSConfig() *tls.Config {
+	if t, ok := Transport().(*http.Transport); ok && t.TLSClientConfig != nil {
+		return t.TLSClientConfig.Clone()
+	}
+	return nil
+}
+
+// New returns a client on the shared transport. A zero timeout means no limit,
+// which is what streaming provider requests need.
+func New(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Transport: Transport(),
+		Timeout:   timeout,
+	}
+}
+
+// Timeout returns the configured request timeout, or fallback when none is set.
+func Timeout(fallback time.Duration) time.Duration {
+	mu.RLock()
+	defer mu.RUnlock()
+	if requestTimeout > 0 {
+		return requestTimeout
+	}
+	return fallback
+}
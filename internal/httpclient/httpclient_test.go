@@ -0,0 +1,143 @@
+package httpclient
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// configure sets up the shared transport for one test.
+func configure(t *testing.T, opts Options) {
+	t.Helper()
+	previousTransport, previousDefault := Transport(), http.DefaultTransport
+	require.NoError(t, Configure(opts))
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		transport = previousTransport
+		requestTimeout = 0
+		http.DefaultTransport = previousDefault
+	})
+}
+
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "file.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	return path
+}
+
+func get(t *testing.T, url string) (string, error) {
+	t.Helper()
+	resp, err := New(5 * time.Second).Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body), nil
+}
+
+func TestProxy(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "proxied "+r.URL.String())
+	}))
+	defer proxy.Close()
+
+	configure(t, Options{Proxy: proxy.URL, NoProxy: []string{"direct.invalid"}})
+
+	body, err := get(t, "http://example.invalid/path")
+	require.NoError(t, err)
+	assert.Equal(t, "proxied http://example.invalid/path", body)
+
+	_, err = get(t, "http://direct.invalid/path")
+	assert.Error(t, err, "hosts in NoProxy are reached directly")
+
+	req, err := http.NewRequest(http.MethodGet, "http://example.invalid", nil)
+	require.NoError(t, err)
+	proxyURL, err := Proxy()(req)
+	require.NoError(t, err)
+	assert.Equal(t, proxy.URL, proxyURL.String())
+}
+
+func TestCACerts(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	configure(t, Options{})
+	_, err := get(t, server.URL)
+	assert.Error(t, err, "the test certificate is not trusted by default")
+
+	configure(t, Options{CACerts: []string{writePEM(t, "CERTIFICATE", server.Certificate().Raw)}})
+	body, err := get(t, server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", body)
+	assert.NotNil(t, TLSConfig().RootCAs)
+
+	_, err = newTransport(Options{CACerts: []string{writePEM(t, "PRIVATE KEY", []byte("not a certificate"))}})
+	assert.ErrorContains(t, err, "no certificates found")
+}
+
+func TestClientCert(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "opencode"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.TLS.PeerCertificates[0].Subject.CommonName)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+	caCert := writePEM(t, "CERTIFICATE", server.Certificate().Raw)
+
+	configure(t, Options{CACerts: []string{caCert}})
+	_, err = get(t, server.URL)
+	assert.Error(t, err, "the server requires a client certificate")
+
+	configure(t, Options{
+		CACerts:    []string{caCert},
+		ClientCert: writePEM(t, "CERTIFICATE", certDER),
+		ClientKey:  writePEM(t, "PRIVATE KEY", keyDER),
+	})
+	body, err := get(t, server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "opencode", body)
+	assert.Len(t, TLSConfig().Certificates, 1)
+}
+
+func TestTimeout(t *testing.T) {
+	configure(t, Options{})
+	assert.Equal(t, time.Minute, Timeout(time.Minute))
+
+	configure(t, Options{RequestTimeout: time.Second})
+	assert.Equal(t, time.Second, Timeout(time.Minute))
+}
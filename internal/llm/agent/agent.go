@@ -12,18 +12,24 @@ import (
 	"github.com/opencode-ai/opencode/internal/llm/models"
 	"github.com/opencode-ai/opencode/internal/llm/prompt"
 	"github.com/opencode-ai/opencode/internal/llm/provider"
+	"github.com/opencode-ai/opencode/internal/llm/tokens"
 	"github.com/opencode-ai/opencode/internal/llm/tools"
 	"github.com/opencode-ai/opencode/internal/logging"
 	"github.com/opencode-ai/opencode/internal/message"
 	"github.com/opencode-ai/opencode/internal/permission"
 	"github.com/opencode-ai/opencode/internal/pubsub"
 	"github.com/opencode-ai/opencode/internal/session"
+	"github.com/opencode-ai/opencode/internal/todo"
+	"github.com/opencode-ai/opencode/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Common errors
 var (
 	ErrRequestCancelled = errors.New("request cancelled by user")
 	ErrSessionBusy      = errors.New("session is currently processing another request")
+
+	errContextTooLong = errors.New("request doesn't fit in the context window")
 )
 
 type AgentEventType string
@@ -39,6 +45,10 @@ type AgentEvent struct {
 	Message message.Message
 	Error   error
 
+	// Set when older messages had to be left out of the request to fit in
+	// the context window, compacting the session keeps them around as a summary
+	ContextTrimmed bool
+
 	// When summarizing
 	SessionID string
 	Progress  string
@@ -60,6 +70,7 @@ type agent struct {
 	*pubsub.Broker[AgentEvent]
 	sessions session.Service
 	messages message.Service
+	todos    todo.Service
 
 	tools    []tools.BaseTool
 	provider provider.Provider
@@ -74,6 +85,7 @@ func NewAgent(
 	agentName config.AgentName,
 	sessions session.Service,
 	messages message.Service,
+	todos todo.Service,
 	agentTools []tools.BaseTool,
 ) (Service, error) {
 	agentProvider, err := createAgentProvider(agentName)
@@ -101,6 +113,7 @@ func NewAgent(
 		provider:          agentProvider,
 		messages:          messages,
 		sessions:          sessions,
+		todos:             todos,
 		tools:             agentTools,
 		titleProvider:     titleProvider,
 		summarizeProvider: summarizeProvider,
@@ -230,7 +243,12 @@ func (a *agent) Run(ctx context.Context, sessionID string, content string, attac
 	return events, nil
 }
 
-func (a *agent) processGeneration(ctx context.Context, sessionID, content string, attachmentParts []message.ContentPart) AgentEvent {
+func (a *agent) processGeneration(ctx context.Context, sessionID, content string, attachmentParts []message.ContentPart) (result AgentEvent) {
+	ctx, span := tracing.Start(ctx, "agent.turn",
+		attribute.String("session.id", sessionID),
+		attribute.String("gen_ai.request.model", string(a.provider.Model().ID)),
+	)
+	defer func() { tracing.End(span, result.Error) }()
 	cfg := config.Get()
 	// List existing messages; if none, start title generation asynchronously.
 	msgs, err := a.messages.List(ctx, sessionID)
@@ -273,6 +291,8 @@ func (a *agent) processGeneration(ctx context.Context, sessionID, content string
 	// Append the new user message to the conversation history.
 	msgHistory := append(msgs, userMsg)
 
+	contextTrimmed := false
+	summarized := false
 	for {
 		// Check for cancellation before each iteration
 		select {
@@ -281,7 +301,30 @@ func (a *agent) processGeneration(ctx context.Context, sessionID, content string
 		default:
 			// Continue processing
 		}
-		agentMessage, toolResults, err := a.streamAndHandleEvents(ctx, sessionID, msgHistory)
+		var estimate int64
+		var trimmed bool
+		msgHistory, estimate, trimmed, err = a.fitContext(msgHistory, session.SummaryMessageID)
+		if errors.Is(err, errContextTooLong) && !summarized && a.summarizeProvider != nil {
+			// Even the latest turn alone is too long, so the history is
+			// replaced with a summary of it, which includes the new request
+			logging.WarnPersist("Conversation doesn't fit in the context window, summarizing it to continue")
+			summarized = true
+			summaryMsg, err := a.summarizeMessages(ctx, sessionID, msgHistory)
+			if err != nil {
+				return a.err(err)
+			}
+			session.SummaryMessageID = summaryMsg.ID
+			summaryMsg.Role = message.User
+			msgHistory = []message.Message{summaryMsg}
+			// The summary already keeps what was dropped
+			contextTrimmed = false
+			continue
+		}
+		if err != nil {
+			return a.err(err)
+		}
+		contextTrimmed = contextTrimmed || trimmed
+		agentMessage, toolResults, err := a.streamAndHandleEvents(ctx, sessionID, msgHistory, estimate)
 		if err != nil {
 			if errors.Is(err, context.Canceled) {
 				agentMessage.AddFinish(message.FinishReasonCanceled)
@@ -303,13 +346,52 @@ func (a *agent) processGeneration(ctx context.Context, sessionID, content string
 			continue
 		}
 		return AgentEvent{
-			Type:    AgentEventTypeResponse,
-			Message: agentMessage,
-			Done:    true,
+			Type:           AgentEventTypeResponse,
+			Message:        agentMessage,
+			Done:           true,
+			ContextTrimmed: contextTrimmed,
 		}
 	}
 }
 
+// fitContext estimates the input tokens of the next request. When they would
+// not leave room for the response in the model's context window, the oldest
+// turns are dropped from the request and a warning is shown. A summary the
+// history starts with is kept, as it stands for everything before it. It
+// returns the history to send, its estimate and whether anything was dropped,
+// or errContextTooLong when dropping turns is not enough.
+func (a *agent) fitContext(msgHistory []message.Message, summaryMessageID string) ([]message.Message, int64, bool, error) {
+	model := a.provider.Model()
+	estimate := a.provider.EstimateTokens(msgHistory, a.tools)
+	if model.ContextWindow <= 0 {
+		return msgHistory, estimate, false, nil
+	}
+	limit := model.ContextWindow - a.provider.MaxTokens()
+	if estimate <= limit {
+		return msgHistory, estimate, false, nil
+	}
+
+	logging.WarnPersist(fmt.Sprintf("Conversation is about %d tokens, over the %d token limit of %s, leaving out the oldest messages", estimate, limit, model.Name))
+	var summary []message.Message
+	rest := msgHistory
+	if summaryMessageID != "" && len(msgHistory) > 0 && msgHistory[0].ID == summaryMessageID {
+		// Capped so appending to it doesn't overwrite the history
+		summary, rest = msgHistory[:1:1], msgHistory[1:]
+	}
+	// Only cut before user messages so tool results never lose their tool calls
+	for i := 1; i < len(rest); i++ {
+		if rest[i].Role != message.User {
+			continue
+		}
+		trimmed := append(summary, rest[i:]...)
+		estimate = a.provider.EstimateTokens(trimmed, a.tools)
+		if estimate <= limit {
+			return trimmed, estimate, true, nil
+		}
+	}
+	return msgHistory, estimate, true, fmt.Errorf("%w: it is about %d tokens, over the %d token limit of %s", errContextTooLong, estimate, limit, model.Name)
+}
+
 func (a *agent) createUserMessage(ctx context.Context, sessionID, content string, attachmentParts []message.ContentPart) (message.Message, error) {
 	parts := []message.ContentPart{message.TextContent{Text: content}}
 	parts = append(parts, attachmentParts...)
@@ -319,9 +401,18 @@ func (a *agent) createUserMessage(ctx context.Context, sessionID, content string
 	})
 }
 
-func (a *agent) streamAndHandleEvents(ctx context.Context, sessionID string, msgHistory []message.Message) (message.Message, *message.Message, error) {
+func (a *agent) streamAndHandleEvents(ctx context.Context, sessionID string, msgHistory []message.Message, estimate int64) (message.Message, *message.Message, error) {
 	ctx = context.WithValue(ctx, tools.SessionIDContextKey, sessionID)
-	eventChan := a.provider.StreamResponse(ctx, msgHistory, a.tools)
+	model := a.provider.Model()
+	streamCtx, streamSpan := tracing.Start(ctx, "provider.stream",
+		attribute.String("gen_ai.system", string(model.Provider)),
+		attribute.String("gen_ai.request.model", model.APIModel),
+		attribute.Int("gen_ai.request.messages", len(msgHistory)),
+		attribute.Int64("gen_ai.request.estimated_input_tokens", estimate),
+	)
+	streamStart := time.Now()
+	firstToken := false
+	eventChan := a.provider.StreamResponse(streamCtx, msgHistory, a.tools)
 
 	assistantMsg, err := a.messages.Create(ctx, sessionID, message.CreateMessageParams{
 		Role:  message.Assistant,
@@ -329,6 +420,7 @@ func (a *agent) streamAndHandleEvents(ctx context.Context, sessionID string, msg
 		Model: a.provider.Model().ID,
 	})
 	if err != nil {
+		tracing.End(streamSpan, err)
 		return assistantMsg, nil, fmt.Errorf("failed to create assistant message: %w", err)
 	}
 
@@ -337,15 +429,37 @@ func (a *agent) streamAndHandleEvents(ctx context.Context, sessionID string, msg
 
 	// Process each event in the stream.
 	for event := range eventChan {
+		switch event.Type {
+		case provider.EventContentDelta, provider.EventThinkingDelta, provider.EventToolUseStart:
+			if !firstToken {
+				firstToken = true
+				streamSpan.AddEvent("first_token")
+				streamSpan.SetAttributes(attribute.Int64("gen_ai.time_to_first_token_ms", time.Since(streamStart).Milliseconds()))
+			}
+		case provider.EventComplete:
+			usage := event.Response.Usage
+			tokens.Calibrate(model, estimate, usage.InputTokens+usage.CacheCreationTokens+usage.CacheReadTokens)
+			streamSpan.SetAttributes(
+				attribute.Int64("gen_ai.usage.input_tokens", usage.InputTokens),
+				attribute.Int64("gen_ai.usage.output_tokens", usage.OutputTokens),
+				attribute.Int64("gen_ai.usage.cache_creation_tokens", usage.CacheCreationTokens),
+				attribute.Int64("gen_ai.usage.cache_read_tokens", usage.CacheReadTokens),
+				attribute.Float64("gen_ai.usage.cost", usageCost(model, usage)),
+				attribute.String("gen_ai.response.finish_reason", string(event.Response.FinishReason)),
+			)
+		}
 		if processErr := a.processEvent(ctx, sessionID, &assistantMsg, event); processErr != nil {
+			tracing.End(streamSpan, processErr)
 			a.finishMessage(ctx, &assistantMsg, message.FinishReasonCanceled)
 			return assistantMsg, nil, processErr
 		}
 		if ctx.Err() != nil {
+			tracing.End(streamSpan, ctx.Err())
 			a.finishMessage(context.Background(), &assistantMsg, message.FinishReasonCanceled)
 			return assistantMsg, nil, ctx.Err()
 		}
 	}
+	tracing.End(streamSpan, nil)
 
 	toolResults := make([]message.ToolResult, len(assistantMsg.ToolCalls()))
 	toolCalls := assistantMsg.ToolCalls()
@@ -387,11 +501,20 @@ func (a *agent) streamAndHandleEvents(ctx context.Context, sessionID string, msg
 				}
 				continue
 			}
-			toolResult, toolErr := tool.Run(ctx, tools.ToolCall{
+			toolCtx, toolSpan := tracing.Start(ctx, "tool.run",
+				attribute.String("tool.name", toolCall.Name),
+				attribute.String("tool.call_id", toolCall.ID),
+			)
+			toolResult, toolErr := tool.Run(toolCtx, tools.ToolCall{
 				ID:    toolCall.ID,
 				Name:  toolCall.Name,
 				Input: toolCall.Input,
 			})
+			toolSpan.SetAttributes(
+				attribute.Bool("tool.is_error", toolResult.IsError),
+				attribute.Int("tool.output_length", len(toolResult.Content)),
+			)
+			tracing.End(toolSpan, toolErr)
 			if toolErr != nil {
 				if errors.Is(toolErr, permission.ErrorPermissionDenied) {
 					toolResults[i] = message.ToolResult{
@@ -410,12 +533,7 @@ func (a *agent) streamAndHandleEvents(ctx context.Context, sessionID string, msg
 					break
 				}
 			}
-			toolResults[i] = message.ToolResult{
-				ToolCallID: toolCall.ID,
-				Content:    toolResult.Content,
-				Metadata:   toolResult.Metadata,
-				IsError:    toolResult.IsError,
-			}
+			toolResults[i] = newToolResult(a.provider.Model(), toolCall.ID, toolResult)
 		}
 	}
 out:
@@ -437,6 +555,23 @@ out:
 	return assistantMsg, &msg, err
 }
 
+// newToolResult turns a tool response into the result sent to the model. Only
+// models that can see images get them, the others get the text content.
+func newToolResult(model models.Model, toolCallID string, response tools.ToolResponse) message.ToolResult {
+	result := message.ToolResult{
+		ToolCallID: toolCallID,
+		Content:    response.Content,
+		Metadata:   response.Metadata,
+		IsError:    response.IsError,
+	}
+	if response.Type == tools.ToolResponseTypeImage && model.SupportsAttachments {
+		result.Images = []message.BinaryContent{
+			{MIMEType: response.MIMEType, Data: response.Data},
+		}
+	}
+	return result
+}
+
 func (a *agent) finishMessage(ctx context.Context, msg *message.Message, finishReson message.FinishReason) {
 	msg.AddFinish(finishReson)
 	_ = a.messages.Update(ctx, *msg)
@@ -452,7 +587,7 @@ func (a *agent) processEvent(ctx context.Context, sessionID string, assistantMsg
 
 	switch event.Type {
 	case provider.EventThinkingDelta:
-		assistantMsg.AppendReasoningContent(event.Content)
+		assistantMsg.AppendReasoningContent(event.Thinking)
 		return a.messages.Update(ctx, *assistantMsg)
 	case provider.EventContentDelta:
 		assistantMsg.AppendContent(event.Content)
@@ -481,6 +616,9 @@ func (a *agent) processEvent(ctx context.Context, sessionID string, assistantMsg
 		return event.Error
 	case provider.EventComplete:
 		assistantMsg.SetToolCalls(event.Response.ToolCalls)
+		if len(event.Response.ReasoningStates) > 0 {
+			assistantMsg.SetReasoningStates(event.Response.ReasoningStates)
+		}
 		assistantMsg.AddFinish(event.Response.FinishReason)
 		if err := a.messages.Update(ctx, *assistantMsg); err != nil {
 			return fmt.Errorf("failed to update message: %w", err)
@@ -497,12 +635,7 @@ func (a *agent) TrackUsage(ctx context.Context, sessionID string, model models.M
 		return fmt.Errorf("failed to get session: %w", err)
 	}
 
-	cost := model.CostPer1MInCached/1e6*float64(usage.CacheCreationTokens) +
-		model.CostPer1MOutCached/1e6*float64(usage.CacheReadTokens) +
-		model.CostPer1MIn/1e6*float64(usage.InputTokens) +
-		model.CostPer1MOut/1e6*float64(usage.OutputTokens)
-
-	sess.Cost += cost
+	sess.Cost += usageCost(model, usage)
 	sess.CompletionTokens = usage.OutputTokens + usage.CacheReadTokens
 	sess.PromptTokens = usage.InputTokens + usage.CacheCreationTokens
 
@@ -513,6 +646,14 @@ func (a *agent) TrackUsage(ctx context.Context, sessionID string, model models.M
 	return nil
 }
 
+// usageCost returns the cost in dollars of the given token usage.
+func usageCost(model models.Model, usage provider.TokenUsage) float64 {
+	return model.CostPer1MInCached/1e6*float64(usage.CacheCreationTokens) +
+		model.CostPer1MOutCached/1e6*float64(usage.CacheReadTokens) +
+		model.CostPer1MIn/1e6*float64(usage.InputTokens) +
+		model.CostPer1MOut/1e6*float64(usage.OutputTokens)
+}
+
 func (a *agent) Update(agentName config.AgentName, modelID models.ModelID) (models.Model, error) {
 	if a.IsBusy() {
 		return models.Model{}, fmt.Errorf("cannot change model while processing requests")
@@ -568,8 +709,6 @@ func (a *agent) Summarize(ctx context.Context, sessionID string) error {
 			a.Publish(pubsub.CreatedEvent, event)
 			return
 		}
-		summarizeCtx = context.WithValue(summarizeCtx, tools.SessionIDContextKey, sessionID)
-
 		if len(msgs) == 0 {
 			event = AgentEvent{
 				Type:  AgentEventTypeError,
@@ -580,119 +719,25 @@ func (a *agent) Summarize(ctx context.Context, sessionID string) error {
 			return
 		}
 
-		event = AgentEvent{
-			Type:     AgentEventTypeSummarize,
-			Progress: "Analyzing conversation...",
-		}
-		a.Publish(pubsub.CreatedEvent, event)
-
-		// Add a system message to guide the summarization
-		summarizePrompt := "Provide a detailed but concise summary of our conversation above. Focus on information that would be helpful for continuing the conversation, including what we did, what we're doing, which files we're working on, and what we're going to do next."
-
-		// Create a new message with the summarize prompt
-		promptMsg := message.Message{
-			Role:  message.User,
-			Parts: []message.ContentPart{message.TextContent{Text: summarizePrompt}},
-		}
-
-		// Append the prompt to the messages
-		msgsWithPrompt := append(msgs, promptMsg)
-
 		event = AgentEvent{
 			Type:     AgentEventTypeSummarize,
 			Progress: "Generating summary...",
 		}
-
 		a.Publish(pubsub.CreatedEvent, event)
 
-		// Send the messages to the summarize provider
-		response, err := a.summarizeProvider.SendMessages(
-			summarizeCtx,
-			msgsWithPrompt,
-			make([]tools.BaseTool, 0),
-		)
-		if err != nil {
+		if _, err := a.summarizeMessages(summarizeCtx, sessionID, msgs); err != nil {
 			event = AgentEvent{
 				Type:  AgentEventTypeError,
-				Error: fmt.Errorf("failed to summarize: %w", err),
+				Error: err,
 				Done:  true,
 			}
 			a.Publish(pubsub.CreatedEvent, event)
 			return
 		}
 
-		summary := strings.TrimSpace(response.Content)
-		if summary == "" {
-			event = AgentEvent{
-				Type:  AgentEventTypeError,
-				Error: fmt.Errorf("empty summary returned"),
-				Done:  true,
-			}
-			a.Publish(pubsub.CreatedEvent, event)
-			return
-		}
-		event = AgentEvent{
-			Type:     AgentEventTypeSummarize,
-			Progress: "Creating new session...",
-		}
-
-		a.Publish(pubsub.CreatedEvent, event)
-		oldSession, err := a.sessions.Get(summarizeCtx, sessionID)
-		if err != nil {
-			event = AgentEvent{
-				Type:  AgentEventTypeError,
-				Error: fmt.Errorf("failed to get session: %w", err),
-				Done:  true,
-			}
-
-			a.Publish(pubsub.CreatedEvent, event)
-			return
-		}
-		// Create a message in the new session with the summary
-		msg, err := a.messages.Create(summarizeCtx, oldSession.ID, message.CreateMessageParams{
-			Role: message.Assistant,
-			Parts: []message.ContentPart{
-				message.TextContent{Text: summary},
-				message.Finish{
-					Reason: message.FinishReasonEndTurn,
-					Time:   time.Now().Unix(),
-				},
-			},
-			Model: a.summarizeProvider.Model().ID,
-		})
-		if err != nil {
-			event = AgentEvent{
-				Type:  AgentEventTypeError,
-				Error: fmt.Errorf("failed to create summary message: %w", err),
-				Done:  true,
-			}
-
-			a.Publish(pubsub.CreatedEvent, event)
-			return
-		}
-		oldSession.SummaryMessageID = msg.ID
-		oldSession.CompletionTokens = response.Usage.OutputTokens
-		oldSession.PromptTokens = 0
-		model := a.summarizeProvider.Model()
-		usage := response.Usage
-		cost := model.CostPer1MInCached/1e6*float64(usage.CacheCreationTokens) +
-			model.CostPer1MOutCached/1e6*float64(usage.CacheReadTokens) +
-			model.CostPer1MIn/1e6*float64(usage.InputTokens) +
-			model.CostPer1MOut/1e6*float64(usage.OutputTokens)
-		oldSession.Cost += cost
-		_, err = a.sessions.Save(summarizeCtx, oldSession)
-		if err != nil {
-			event = AgentEvent{
-				Type:  AgentEventTypeError,
-				Error: fmt.Errorf("failed to save session: %w", err),
-				Done:  true,
-			}
-			a.Publish(pubsub.CreatedEvent, event)
-		}
-
 		event = AgentEvent{
 			Type:      AgentEventTypeSummarize,
-			SessionID: oldSession.ID,
+			SessionID: sessionID,
 			Progress:  "Summary complete",
 			Done:      true,
 		}
@@ -703,6 +748,63 @@ func (a *agent) Summarize(ctx context.Context, sessionID string) error {
 	return nil
 }
 
+// summarizeMessages asks the summarize provider for a summary of msgs and
+// makes it the message the session continues from.
+func (a *agent) summarizeMessages(ctx context.Context, sessionID string, msgs []message.Message) (message.Message, error) {
+	ctx = context.WithValue(ctx, tools.SessionIDContextKey, sessionID)
+	summarizePrompt := "Provide a detailed but concise summary of our conversation above. Focus on information that would be helpful for continuing the conversation, including what we did, what we're doing, which files we're working on, and what we're going to do next."
+	promptMsg := message.Message{
+		Role:  message.User,
+		Parts: []message.ContentPart{message.TextContent{Text: summarizePrompt}},
+	}
+	// Capped so appending the prompt doesn't overwrite the caller's slice
+	msgsWithPrompt := append(msgs[:len(msgs):len(msgs)], promptMsg)
+
+	response, err := a.summarizeProvider.SendMessages(
+		ctx,
+		msgsWithPrompt,
+		make([]tools.BaseTool, 0),
+	)
+	if err != nil {
+		return message.Message{}, fmt.Errorf("failed to summarize: %w", err)
+	}
+
+	summary := strings.TrimSpace(response.Content)
+	if summary == "" {
+		return message.Message{}, fmt.Errorf("empty summary returned")
+	}
+	// The todo tool calls are dropped with the rest of the conversation,
+	// so the summary carries what is left to do
+	summary += a.unfinishedTodos(ctx, sessionID)
+
+	sess, err := a.sessions.Get(ctx, sessionID)
+	if err != nil {
+		return message.Message{}, fmt.Errorf("failed to get session: %w", err)
+	}
+	msg, err := a.messages.Create(ctx, sess.ID, message.CreateMessageParams{
+		Role: message.Assistant,
+		Parts: []message.ContentPart{
+			message.TextContent{Text: summary},
+			message.Finish{
+				Reason: message.FinishReasonEndTurn,
+				Time:   time.Now().Unix(),
+			},
+		},
+		Model: a.summarizeProvider.Model().ID,
+	})
+	if err != nil {
+		return message.Message{}, fmt.Errorf("failed to create summary message: %w", err)
+	}
+	sess.SummaryMessageID = msg.ID
+	sess.CompletionTokens = response.Usage.OutputTokens
+	sess.PromptTokens = 0
+	sess.Cost += usageCost(a.summarizeProvider.Model(), response.Usage)
+	if _, err := a.sessions.Save(ctx, sess); err != nil {
+		return message.Message{}, fmt.Errorf("failed to save session: %w", err)
+	}
+	return msg, nil
+}
+
 func createAgentProvider(agentName config.AgentName) (provider.Provider, error) {
 	cfg := config.Get()
 	agentConfig, ok := cfg.Agents[agentName]
@@ -726,7 +828,10 @@ func createAgentProvider(agentName config.AgentName) (provider.Provider, error)
 		maxTokens = agentConfig.MaxTokens
 	}
 	opts := []provider.ProviderClientOption{
-		provider.WithAPIKey(providerCfg.APIKey),
+		provider.WithAPIKey(config.APIKey(model.Provider)),
+		provider.WithAPIKeyRefresh(func() (string, error) {
+			return config.RefreshAPIKey(model.Provider)
+		}),
 		provider.WithModel(model),
 		provider.WithSystemMessage(prompt.GetAgentPrompt(agentName, model.Provider)),
 		provider.WithMaxTokens(maxTokens),
@@ -756,3 +861,22 @@ func createAgentProvider(agentName config.AgentName) (provider.Provider, error)
 
 	return agentProvider, nil
 }
+
+// unfinishedTodos lists the todo items of the session when some of them
+// aren't completed yet, for appending to the summary.
+func (a *agent) unfinishedTodos(ctx context.Context, sessionID string) string {
+	if a.todos == nil {
+		return ""
+	}
+	todos, err := a.todos.List(ctx, sessionID)
+	if err != nil {
+		logging.Warn("failed to list todos for summary", "error", err)
+		return ""
+	}
+	for _, item := range todos {
+		if item.Status != todo.StatusCompleted {
+			return "\n\nTodo list:\n" + todo.Format(todos)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,31 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/opencode-ai/opencode/internal/llm/models"
+	"github.com/opencode-ai/opencode/internal/llm/tools"
+	"github.com/opencode-ai/opencode/internal/message"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewToolResult(t *testing.T) {
+	vision := models.Model{SupportsAttachments: true}
+	textOnly := models.Model{}
+	image := tools.NewImageResponse("Image a.png", "image/png", []byte("png"))
+
+	assert.Equal(t, message.ToolResult{
+		ToolCallID: "call_1",
+		Content:    "Image a.png",
+		Images:     []message.BinaryContent{{MIMEType: "image/png", Data: []byte("png")}},
+	}, newToolResult(vision, "call_1", image))
+	assert.Equal(t, message.ToolResult{
+		ToolCallID: "call_1",
+		Content:    "Image a.png",
+	}, newToolResult(textOnly, "call_1", image))
+	assert.Equal(t, message.ToolResult{
+		ToolCallID: "call_1",
+		Content:    "not found",
+		IsError:    true,
+	}, newToolResult(vision, "call_1", tools.NewTextErrorResponse("not found")))
+}
@@ -2,6 +2,7 @@ package agent
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 
@@ -72,15 +73,32 @@ func runTool(ctx context.Context, c MCPClient, toolName string, input string) (t
 	}
 
 	output := ""
+	var image *mcp.ImageContent
 	for _, v := range result.Content {
-		if v, ok := v.(mcp.TextContent); ok {
+		switch v := v.(type) {
+		case mcp.TextContent:
 			output = v.Text
-		} else {
+		case mcp.ImageContent:
+			if image == nil {
+				image = &v
+			}
+		default:
 			output = fmt.Sprintf("%v", v)
 		}
 	}
 
-	return tools.NewTextResponse(output), nil
+	if image != nil {
+		data, err := base64.StdEncoding.DecodeString(image.Data)
+		if err != nil {
+			return tools.NewTextErrorResponse(fmt.Sprintf("error decoding image returned by %s: %s", toolName, err)), nil
+		}
+		if output == "" {
+			output = fmt.Sprintf("%s returned an image of type %s", toolName, image.MIMEType)
+		}
+		return tools.NewImageResponse(output, image.MIMEType, data), nil
+	}
+
+	return tools.NewTextResponse(tools.SpillOutput(ctx, toolName, output)), nil
 }
 
 func (b *mcpTool) Run(ctx context.Context, params tools.ToolCall) (tools.ToolResponse, error) {
@@ -115,6 +133,8 @@ func (b *mcpTool) Run(ctx context.Context, params tools.ToolCall) (tools.ToolRes
 		}
 		return runTool(ctx, c, b.tool.Name, params.Input)
 	case config.MCPSse:
+		// The SSE client has no client option, it goes through
+		// http.DefaultTransport which the network config sets up
 		c, err := client.NewSSEMCPClient(
 			b.mcpConfig.URL,
 			client.WithHeaders(b.mcpConfig.Headers),
@@ -5,10 +5,13 @@ import (
 
 	"github.com/opencode-ai/opencode/internal/history"
 	"github.com/opencode-ai/opencode/internal/llm/tools"
+	"github.com/opencode-ai/opencode/internal/llm/tools/shell"
 	"github.com/opencode-ai/opencode/internal/lsp"
 	"github.com/opencode-ai/opencode/internal/message"
 	"github.com/opencode-ai/opencode/internal/permission"
+	"github.com/opencode-ai/opencode/internal/question"
 	"github.com/opencode-ai/opencode/internal/session"
+	"github.com/opencode-ai/opencode/internal/todo"
 )
 
 func CoderAgentTools(
@@ -17,35 +20,66 @@ func CoderAgentTools(
 	messages message.Service,
 	history history.Service,
 	lspClients map[string]*lsp.Client,
+	processes shell.BackgroundService,
+	todos todo.Service,
+	questions question.Service,
 ) []tools.BaseTool {
 	ctx := context.Background()
 	otherTools := GetMcpTools(ctx, permissions)
 	if len(lspClients) > 0 {
 		otherTools = append(otherTools, tools.NewDiagnosticsTool(lspClients))
+		otherTools = append(otherTools, navigationTools(lspClients)...)
+		otherTools = append(otherTools, tools.NewRenameSymbolTool(lspClients, permissions, history))
+		otherTools = append(otherTools, tools.NewCodeActionTool(lspClients, permissions, history))
+	}
+	if tools.WebSearchEnabled() {
+		otherTools = append(otherTools, tools.NewWebSearchTool(permissions))
 	}
 	return append(
 		[]tools.BaseTool{
-			tools.NewBashTool(permissions),
+			tools.NewBashTool(permissions, processes),
+			tools.NewProcessTool(processes),
+			tools.NewGitTool(permissions),
+			tools.NewRunTestsTool(permissions),
 			tools.NewEditTool(lspClients, permissions, history),
+			tools.NewMultiEditTool(lspClients, permissions, history),
 			tools.NewFetchTool(permissions),
 			tools.NewGlobTool(),
 			tools.NewGrepTool(),
 			tools.NewLsTool(),
 			tools.NewSourcegraphTool(),
 			tools.NewViewTool(lspClients),
+			tools.NewReadOutputTool(),
 			tools.NewPatchTool(lspClients, permissions, history),
 			tools.NewWriteTool(lspClients, permissions, history),
+			tools.NewTodoTool(todos),
+			tools.NewAskUserTool(questions),
 			NewAgentTool(sessions, messages, lspClients),
 		}, otherTools...,
 	)
 }
 
 func TaskAgentTools(lspClients map[string]*lsp.Client) []tools.BaseTool {
-	return []tools.BaseTool{
+	taskTools := []tools.BaseTool{
 		tools.NewGlobTool(),
 		tools.NewGrepTool(),
 		tools.NewLsTool(),
 		tools.NewSourcegraphTool(),
 		tools.NewViewTool(lspClients),
 	}
+	if len(lspClients) > 0 {
+		taskTools = append(taskTools, navigationTools(lspClients)...)
+	}
+	return taskTools
+}
+
+// navigationTools are the read-only tools backed by the language servers.
+func navigationTools(lspClients map[string]*lsp.Client) []tools.BaseTool {
+	return []tools.BaseTool{
+		tools.NewDefinitionTool(lspClients),
+		tools.NewReferencesTool(lspClients),
+		tools.NewHoverTool(lspClients),
+		tools.NewCallHierarchyTool(lspClients),
+		tools.NewSymbolsTool(lspClients),
+	}
 }
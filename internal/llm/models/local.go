@@ -8,8 +8,10 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"time"
 	"unicode"
 
+	"github.com/opencode-ai/opencode/internal/httpclient"
 	"github.com/opencode-ai/opencode/internal/logging"
 	"github.com/spf13/viper"
 )
@@ -21,7 +23,9 @@ const (
 	lmStudioBetaModelsPath = "api/v0/models"
 )
 
-func init() {
+// DiscoverLocalModels registers the models served at LOCAL_ENDPOINT. It runs
+// once the network settings are loaded so discovery goes through the proxy.
+func DiscoverLocalModels() {
 	if endpoint := os.Getenv("LOCAL_ENDPOINT"); endpoint != "" {
 		localEndpoint, err := url.Parse(endpoint)
 		if err != nil {
@@ -75,7 +79,8 @@ type localModel struct {
 }
 
 func listLocalModels(modelsEndpoint string) []localModel {
-	res, err := http.Get(modelsEndpoint)
+	client := httpclient.New(httpclient.Timeout(10 * time.Second))
+	res, err := client.Get(modelsEndpoint)
 	if err != nil {
 		logging.Debug("Failed to list local models",
 			"error", err,
@@ -0,0 +1,216 @@
+package models
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ManifestFileName is the name of the manifest file in the config directory
+// that overrides the built-in catalog.
+const ManifestFileName = "models.json"
+
+//go:embed manifest.json
+var builtinManifestData []byte
+
+// Manifest is a model catalog. The version is a date, a manifest file older
+// than the built-in one is ignored since it would bring back stale data.
+type Manifest struct {
+	Version string  `json:"version"`
+	Models  []Model `json:"models"`
+}
+
+var (
+	// SupportedModels is the catalog of all known models.
+	SupportedModels = map[ModelID]Model{}
+
+	builtinManifest Manifest
+	builtinModels   = map[ModelID]Model{}
+
+	// OverriddenModels are the models the manifest file added or changed.
+	OverriddenModels = map[ModelID]bool{}
+)
+
+func init() {
+	manifest, err := ParseManifest(builtinManifestData, nil)
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in model manifest: %v", err))
+	}
+	builtinManifest = manifest
+	for _, model := range manifest.Models {
+		builtinModels[model.ID] = model
+		SupportedModels[model.ID] = model
+	}
+}
+
+// BuiltinManifest returns the catalog embedded in the binary.
+func BuiltinManifest() Manifest {
+	return builtinManifest
+}
+
+// BuiltinModel returns the built-in definition of a model.
+func BuiltinModel(id ModelID) (Model, bool) {
+	model, ok := builtinModels[id]
+	return model, ok
+}
+
+// ParseManifest parses a manifest. Entries for models that are in base only
+// need the fields that differ, the others are taken from base.
+func ParseManifest(data []byte, base map[ModelID]Model) (Manifest, error) {
+	var raw struct {
+		Version string            `json:"version"`
+		Models  []json.RawMessage `json:"models"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Manifest{}, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	manifest := Manifest{Version: raw.Version}
+	for i, entry := range raw.Models {
+		var id struct {
+			ID ModelID `json:"id"`
+		}
+		if err := json.Unmarshal(entry, &id); err != nil {
+			return Manifest{}, fmt.Errorf("failed to parse model %d: %w", i, err)
+		}
+		model := base[id.ID]
+		if err := json.Unmarshal(entry, &model); err != nil {
+			return Manifest{}, fmt.Errorf("failed to parse model %s: %w", id.ID, err)
+		}
+		manifest.Models = append(manifest.Models, model)
+	}
+	return manifest, nil
+}
+
+// ReadManifest reads a manifest file on top of the built-in catalog.
+func ReadManifest(path string) (Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Manifest{}, err
+	}
+	return ParseManifest(data, builtinModels)
+}
+
+// CheckManifest returns the problems found in a manifest.
+func CheckManifest(manifest Manifest) []error {
+	var problems []error
+	if manifest.Version == "" {
+		problems = append(problems, errors.New("version is missing"))
+	}
+	seen := map[ModelID]bool{}
+	for i, model := range manifest.Models {
+		if model.ID == "" {
+			problems = append(problems, fmt.Errorf("model %d has no id", i))
+			continue
+		}
+		if seen[model.ID] {
+			problems = append(problems, fmt.Errorf("%s: duplicate id", model.ID))
+		}
+		seen[model.ID] = true
+		if model.Name == "" {
+			problems = append(problems, fmt.Errorf("%s: name is missing", model.ID))
+		}
+		if model.Provider == "" {
+			problems = append(problems, fmt.Errorf("%s: provider is missing", model.ID))
+		} else if !knownProvider(model.Provider) {
+			problems = append(problems, fmt.Errorf("%s: unknown provider %s", model.ID, model.Provider))
+		}
+		if model.APIModel == "" {
+			problems = append(problems, fmt.Errorf("%s: api_model is missing", model.ID))
+		}
+		if model.ContextWindow < 0 || model.DefaultMaxTokens < 0 {
+			problems = append(problems, fmt.Errorf("%s: token limits can't be negative", model.ID))
+		}
+		if model.ContextWindow > 0 && model.DefaultMaxTokens > model.ContextWindow {
+			problems = append(problems, fmt.Errorf("%s: default_max_tokens is larger than context_window", model.ID))
+		}
+		if model.CostPer1MIn < 0 || model.CostPer1MOut < 0 || model.CostPer1MInCached < 0 || model.CostPer1MOutCached < 0 {
+			problems = append(problems, fmt.Errorf("%s: costs can't be negative", model.ID))
+		}
+	}
+	return problems
+}
+
+// LoadManifestFile applies a manifest file on top of the built-in catalog. A
+// missing file is not an error.
+func LoadManifestFile(path string) error {
+	manifest, err := ReadManifest(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if manifest.Version < builtinManifest.Version {
+		return fmt.Errorf("%s (version %q) is older than the built-in catalog (version %s), ignoring it", path, manifest.Version, builtinManifest.Version)
+	}
+	if problems := CheckManifest(manifest); len(problems) > 0 {
+		return fmt.Errorf("%s is invalid: %w", path, errors.Join(problems...))
+	}
+	for _, model := range manifest.Models {
+		if builtin, ok := builtinModels[model.ID]; !ok || builtin != model {
+			OverriddenModels[model.ID] = true
+		}
+		SupportedModels[model.ID] = model
+	}
+	return nil
+}
+
+// FieldChange is a field that differs between two definitions of a model.
+type FieldChange struct {
+	Field string
+	Old   any
+	New   any
+}
+
+// DiffModel returns the fields that differ between two definitions of a
+// model, named after their JSON keys.
+func DiffModel(old, new Model) []FieldChange {
+	var changes []FieldChange
+	oldValue, newValue := reflect.ValueOf(old), reflect.ValueOf(new)
+	modelType := oldValue.Type()
+	for i := range modelType.NumField() {
+		a, b := oldValue.Field(i).Interface(), newValue.Field(i).Interface()
+		if a == b {
+			continue
+		}
+		name, _, _ := strings.Cut(modelType.Field(i).Tag.Get("json"), ",")
+		changes = append(changes, FieldChange{Field: name, Old: a, New: b})
+	}
+	return changes
+}
+
+// SortedModels returns the models ordered by provider popularity, then ID.
+func SortedModels(catalog map[ModelID]Model) []Model {
+	list := make([]Model, 0, len(catalog))
+	for _, model := range catalog {
+		list = append(list, model)
+	}
+	slices.SortFunc(list, func(a, b Model) int {
+		pa, pb := providerRank(a.Provider), providerRank(b.Provider)
+		if pa != pb {
+			return pa - pb
+		}
+		return strings.Compare(string(a.ID), string(b.ID))
+	})
+	return list
+}
+
+func knownProvider(provider ModelProvider) bool {
+	if _, ok := ProviderPopularity[provider]; ok {
+		return true
+	}
+	return provider == ProviderXAI || provider == ProviderLocal
+}
+
+func providerRank(provider ModelProvider) int {
+	if rank, ok := ProviderPopularity[provider]; ok {
+		return rank
+	}
+	return len(ProviderPopularity) + 1
+}
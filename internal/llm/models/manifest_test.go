@@ -0,0 +1,79 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinManifest(t *testing.T) {
+	manifest := BuiltinManifest()
+	assert.NotEmpty(t, manifest.Version)
+	assert.Empty(t, CheckManifest(manifest))
+
+	// Models referenced from code must be in the catalog
+	for _, id := range []ModelID{Claude4Sonnet, GPT41, GPT41Mini, Gemini25, CopilotGPT4o, BedrockClaude37Sonnet, AzureGPT41} {
+		_, ok := SupportedModels[id]
+		assert.True(t, ok, "missing %s", id)
+	}
+}
+
+func TestParseManifest(t *testing.T) {
+	data := []byte(`{
+		"version": "2999-01-01",
+		"models": [
+			{"id": "claude-4-sonnet", "cost_per_1m_in": 2.5},
+			{"id": "new-model", "name": "New", "provider": "anthropic", "api_model": "new", "context_window": 1000}
+		]
+	}`)
+	manifest, err := ParseManifest(data, builtinModels)
+	require.NoError(t, err)
+	require.Len(t, manifest.Models, 2)
+
+	// Fields that are not in the entry come from the built-in model
+	updated := manifest.Models[0]
+	builtin := builtinModels[Claude4Sonnet]
+	assert.Equal(t, 2.5, updated.CostPer1MIn)
+	assert.Equal(t, builtin.ContextWindow, updated.ContextWindow)
+	assert.Equal(t, []FieldChange{{Field: "cost_per_1m_in", Old: builtin.CostPer1MIn, New: 2.5}}, DiffModel(builtin, updated))
+
+	assert.Equal(t, int64(1000), manifest.Models[1].ContextWindow)
+	assert.Empty(t, CheckManifest(manifest))
+}
+
+func TestCheckManifest(t *testing.T) {
+	problems := CheckManifest(Manifest{Models: []Model{
+		{ID: "a", Name: "A", Provider: "nope", APIModel: "a"},
+		{ID: "b", Name: "B", Provider: ProviderOpenAI, APIModel: "b", ContextWindow: 10, DefaultMaxTokens: 20},
+		{ID: "b", Name: "B", Provider: ProviderOpenAI, APIModel: "b", CostPer1MIn: -1},
+	}})
+	var messages []string
+	for _, problem := range problems {
+		messages = append(messages, problem.Error())
+	}
+	assert.Equal(t, []string{
+		"version is missing",
+		"a: unknown provider nope",
+		"b: default_max_tokens is larger than context_window",
+		"b: duplicate id",
+		"b: costs can't be negative",
+	}, messages)
+}
+
+func TestLoadManifestFile(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("missing file is fine", func(t *testing.T) {
+		assert.NoError(t, LoadManifestFile(filepath.Join(dir, "missing.json")))
+	})
+
+	t.Run("older manifest is ignored", func(t *testing.T) {
+		path := filepath.Join(dir, "old.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"version": "2000-01-01", "models": [{"id": "claude-4-sonnet", "cost_per_1m_in": 1}]}`), 0o644))
+		assert.Error(t, LoadManifestFile(path))
+		assert.Equal(t, builtinModels[Claude4Sonnet], SupportedModels[Claude4Sonnet])
+	})
+}
@@ -211,6 +211,9 @@ Tools that support it will also include useful diagnostics such as linting and t
 - These diagnostics will be automatically enabled when you run the tool, and will be displayed in the output at the bottom within the <file_diagnostics></file_diagnostics> and <project_diagnostics></project_diagnostics> tags.
 - Take necessary actions to fix the issues.
 - You should ignore diagnostics of files that you did not change or are not related or caused by your changes unless the user explicitly asks you to fix them.
+- Prefer the definition, references, hover, call_hierarchy and symbols tools over grepping when looking for where an identifier is defined or used.
+- Use rename_symbol to rename identifiers instead of editing each use.
+- Use code_action to list and apply the fixes a language server offers for diagnostics, such as adding a missing import.
 `
 }
 
@@ -13,10 +13,12 @@ import (
 	"github.com/anthropics/anthropic-sdk-go/bedrock"
 	"github.com/anthropics/anthropic-sdk-go/option"
 	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/httpclient"
 	"github.com/opencode-ai/opencode/internal/llm/models"
 	toolsPkg "github.com/opencode-ai/opencode/internal/llm/tools"
 	"github.com/opencode-ai/opencode/internal/logging"
 	"github.com/opencode-ai/opencode/internal/message"
+	"github.com/opencode-ai/opencode/internal/tracing"
 )
 
 type anthropicOptions struct {
@@ -41,7 +43,9 @@ func newAnthropicClient(opts providerClientOptions) AnthropicClient {
 		o(&anthropicOpts)
 	}
 
-	anthropicClientOptions := []option.RequestOption{}
+	anthropicClientOptions := []option.RequestOption{
+		option.WithHTTPClient(httpclient.New(0)),
+	}
 	if opts.apiKey != "" {
 		anthropicClientOptions = append(anthropicClientOptions, option.WithAPIKey(opts.apiKey))
 	}
@@ -111,6 +115,12 @@ func (a *anthropicClient) convertMessages(messages []message.Message) (anthropic
 			results := make([]anthropic.ContentBlockParamUnion, len(msg.ToolResults()))
 			for i, toolResult := range msg.ToolResults() {
 				results[i] = anthropic.NewToolResultBlock(toolResult.ToolCallID, toolResult.Content, toolResult.IsError)
+				for _, image := range toolResult.Images {
+					imageBlock := anthropic.NewImageBlockBase64(image.MIMEType, image.String(models.ProviderAnthropic))
+					results[i].OfToolResult.Content = append(results[i].OfToolResult.Content, anthropic.ToolResultBlockParamContentUnion{
+						OfImage: imageBlock.OfImage,
+					})
+				}
 			}
 			anthropicMessages = append(anthropicMessages, anthropic.NewUserMessage(results...))
 		}
@@ -219,6 +229,7 @@ func (a *anthropicClient) send(ctx context.Context, messages []message.Message,
 			}
 			if retry {
 				logging.WarnPersist(fmt.Sprintf("Retrying due to rate limit... attempt %d of %d", attempts, maxRetries), logging.PersistTimeArg, time.Millisecond*time.Duration(after+100))
+				tracing.AddRetry(ctx, attempts, after)
 				select {
 				case <-ctx.Done():
 					return nil, ctx.Err()
@@ -369,6 +380,7 @@ func (a *anthropicClient) stream(ctx context.Context, messages []message.Message
 			}
 			if retry {
 				logging.WarnPersist(fmt.Sprintf("Retrying due to rate limit... attempt %d of %d", attempts, maxRetries), logging.PersistTimeArg, time.Millisecond*time.Duration(after+100))
+				tracing.AddRetry(ctx, attempts, after)
 				select {
 				case <-ctx.Done():
 					// context cancelled
@@ -7,6 +7,7 @@ import (
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/azure"
 	"github.com/openai/openai-go/option"
+	"github.com/opencode-ai/opencode/internal/httpclient"
 )
 
 type azureClient struct {
@@ -26,6 +27,7 @@ func newAzureClient(opts providerClientOptions) AzureClient {
 
 	reqOpts := []option.RequestOption{
 		azure.WithEndpoint(endpoint, apiVersion),
+		option.WithHTTPClient(httpclient.New(0)),
 	}
 
 	if opts.apiKey != "" || os.Getenv("AZURE_OPENAI_API_KEY") != "" {
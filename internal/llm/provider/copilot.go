@@ -14,10 +14,12 @@ import (
 	"github.com/openai/openai-go/option"
 	"github.com/openai/openai-go/shared"
 	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/httpclient"
 	"github.com/opencode-ai/opencode/internal/llm/models"
 	toolsPkg "github.com/opencode-ai/opencode/internal/llm/tools"
 	"github.com/opencode-ai/opencode/internal/logging"
 	"github.com/opencode-ai/opencode/internal/message"
+	"github.com/opencode-ai/opencode/internal/tracing"
 )
 
 type copilotOptions struct {
@@ -93,9 +95,7 @@ func newCopilotClient(opts providerClientOptions) CopilotClient {
 	}
 
 	// Create HTTP client for token exchange
-	httpClient := &http.Client{
-		Timeout: 30 * time.Second,
-	}
+	httpClient := httpclient.New(httpclient.Timeout(30 * time.Second))
 
 	var bearerToken string
 
@@ -160,6 +160,7 @@ func newCopilotClient(opts providerClientOptions) CopilotClient {
 	openaiClientOptions := []option.RequestOption{
 		option.WithBaseURL(baseURL),
 		option.WithAPIKey(bearerToken), // Use bearer token as API key
+		option.WithHTTPClient(httpclient.New(0)),
 	}
 
 	// Add GitHub Copilot specific headers
@@ -235,10 +236,21 @@ func (c *copilotClient) convertMessages(messages []message.Message) (copilotMess
 			})
 
 		case message.Tool:
+			var images []openai.ChatCompletionContentPartUnionParam
 			for _, result := range msg.ToolResults() {
 				copilotMessages = append(copilotMessages,
 					openai.ToolMessage(result.Content, result.ToolCallID),
 				)
+				for _, image := range result.Images {
+					imageURL := openai.ChatCompletionContentPartImageImageURLParam{URL: image.String(models.ProviderOpenAI)}
+					images = append(images, openai.ChatCompletionContentPartUnionParam{OfImageURL: &openai.ChatCompletionContentPartImageParam{ImageURL: imageURL}})
+				}
+			}
+			// Tool messages can only hold text, so images returned by tools follow in a user message
+			if len(images) > 0 {
+				textBlock := openai.ChatCompletionContentPartTextParam{Text: "Images returned by the tool calls above:"}
+				content := []openai.ChatCompletionContentPartUnionParam{{OfText: &textBlock}}
+				copilotMessages = append(copilotMessages, openai.UserMessage(append(content, images...)))
 			}
 		}
 	}
@@ -342,6 +354,7 @@ func (c *copilotClient) send(ctx context.Context, messages []message.Message, to
 			}
 			if retry {
 				logging.WarnPersist(fmt.Sprintf("Retrying due to rate limit... attempt %d of %d", attempts, maxRetries), logging.PersistTimeArg, time.Millisecond*time.Duration(after+100))
+				tracing.AddRetry(ctx, attempts, after)
 				select {
 				case <-ctx.Done():
 					return nil, ctx.Err()
@@ -522,6 +535,7 @@ func (c *copilotClient) stream(ctx context.Context, messages []message.Message,
 			}
 			if retry {
 				logging.WarnPersist(fmt.Sprintf("Retrying due to rate limit... attempt %d of %d (paused for %d ms)", attempts, maxRetries, after), logging.PersistTimeArg, time.Millisecond*time.Duration(after+100))
+				tracing.AddRetry(ctx, attempts, after)
 				select {
 				case <-ctx.Done():
 					// context cancelled
@@ -11,9 +11,11 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/httpclient"
 	"github.com/opencode-ai/opencode/internal/llm/tools"
 	"github.com/opencode-ai/opencode/internal/logging"
 	"github.com/opencode-ai/opencode/internal/message"
+	"github.com/opencode-ai/opencode/internal/tracing"
 	"google.golang.org/genai"
 )
 
@@ -37,7 +39,7 @@ func newGeminiClient(opts providerClientOptions) GeminiClient {
 		o(&geminiOpts)
 	}
 
-	client, err := genai.NewClient(context.Background(), &genai.ClientConfig{APIKey: opts.apiKey, Backend: genai.BackendGeminiAPI})
+	client, err := genai.NewClient(context.Background(), &genai.ClientConfig{APIKey: opts.apiKey, Backend: genai.BackendGeminiAPI, HTTPClient: httpclient.New(0)})
 	if err != nil {
 		logging.Error("Failed to create Gemini client", "error", err)
 		return nil
@@ -95,7 +97,14 @@ func (g *geminiClient) convertMessages(messages []message.Message) []*genai.Cont
 			}
 
 		case message.Tool:
+			var imageParts []*genai.Part
 			for _, result := range msg.ToolResults() {
+				for _, image := range result.Images {
+					imageParts = append(imageParts, &genai.Part{InlineData: &genai.Blob{
+						MIMEType: image.MIMEType,
+						Data:     image.Data,
+					}})
+				}
 				response := map[string]interface{}{"result": result.Content}
 				parsed, err := parseJsonToMap(result.Content)
 				if err == nil {
@@ -126,6 +135,13 @@ func (g *geminiClient) convertMessages(messages []message.Message) []*genai.Cont
 					Role: "function",
 				})
 			}
+			// Function responses can't carry inline data, so images returned by tools follow as user content
+			if len(imageParts) > 0 {
+				history = append(history, &genai.Content{
+					Parts: append([]*genai.Part{{Text: "Images returned by the tool calls above:"}}, imageParts...),
+					Role:  "user",
+				})
+			}
 		}
 	}
 
@@ -206,6 +222,7 @@ func (g *geminiClient) send(ctx context.Context, messages []message.Message, too
 			}
 			if retry {
 				logging.WarnPersist(fmt.Sprintf("Retrying due to rate limit... attempt %d of %d", attempts, maxRetries), logging.PersistTimeArg, time.Millisecond*time.Duration(after+100))
+				tracing.AddRetry(ctx, attempts, after)
 				select {
 				case <-ctx.Done():
 					return nil, ctx.Err()
@@ -305,6 +322,7 @@ func (g *geminiClient) stream(ctx context.Context, messages []message.Message, t
 					}
 					if retry {
 						logging.WarnPersist(fmt.Sprintf("Retrying due to rate limit... attempt %d of %d", attempts, maxRetries), logging.PersistTimeArg, time.Millisecond*time.Duration(after+100))
+						tracing.AddRetry(ctx, attempts, after)
 						select {
 						case <-ctx.Done():
 							if ctx.Err() != nil {
@@ -12,10 +12,12 @@ import (
 	"github.com/openai/openai-go/option"
 	"github.com/openai/openai-go/shared"
 	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/httpclient"
 	"github.com/opencode-ai/opencode/internal/llm/models"
 	"github.com/opencode-ai/opencode/internal/llm/tools"
 	"github.com/opencode-ai/opencode/internal/logging"
 	"github.com/opencode-ai/opencode/internal/message"
+	"github.com/opencode-ai/opencode/internal/tracing"
 )
 
 type openaiOptions struct {
@@ -43,7 +45,9 @@ func newOpenAIClient(opts providerClientOptions) OpenAIClient {
 		o(&openaiOpts)
 	}
 
-	openaiClientOptions := []option.RequestOption{}
+	openaiClientOptions := []option.RequestOption{
+		option.WithHTTPClient(httpclient.New(0)),
+	}
 	if opts.apiKey != "" {
 		openaiClientOptions = append(openaiClientOptions, option.WithAPIKey(opts.apiKey))
 	}
@@ -114,10 +118,21 @@ func (o *openaiClient) convertMessages(messages []message.Message) (openaiMessag
 			})
 
 		case message.Tool:
+			var images []openai.ChatCompletionContentPartUnionParam
 			for _, result := range msg.ToolResults() {
 				openaiMessages = append(openaiMessages,
 					openai.ToolMessage(result.Content, result.ToolCallID),
 				)
+				for _, image := range result.Images {
+					imageURL := openai.ChatCompletionContentPartImageImageURLParam{URL: image.String(models.ProviderOpenAI)}
+					images = append(images, openai.ChatCompletionContentPartUnionParam{OfImageURL: &openai.ChatCompletionContentPartImageParam{ImageURL: imageURL}})
+				}
+			}
+			// Tool messages can only hold text, so images returned by tools follow in a user message
+			if len(images) > 0 {
+				textBlock := openai.ChatCompletionContentPartTextParam{Text: "Images returned by the tool calls above:"}
+				content := []openai.ChatCompletionContentPartUnionParam{{OfText: &textBlock}}
+				openaiMessages = append(openaiMessages, openai.UserMessage(append(content, images...)))
 			}
 		}
 	}
@@ -207,6 +222,7 @@ func (o *openaiClient) send(ctx context.Context, messages []message.Message, too
 			}
 			if retry {
 				logging.WarnPersist(fmt.Sprintf("Retrying due to rate limit... attempt %d of %d", attempts, maxRetries), logging.PersistTimeArg, time.Millisecond*time.Duration(after+100))
+				tracing.AddRetry(ctx, attempts, after)
 				select {
 				case <-ctx.Done():
 					return nil, ctx.Err()
@@ -313,6 +329,7 @@ func (o *openaiClient) stream(ctx context.Context, messages []message.Message, t
 			}
 			if retry {
 				logging.WarnPersist(fmt.Sprintf("Retrying due to rate limit... attempt %d of %d", attempts, maxRetries), logging.PersistTimeArg, time.Millisecond*time.Duration(after+100))
+				tracing.AddRetry(ctx, attempts, after)
 				select {
 				case <-ctx.Done():
 					// context cancelled
@@ -0,0 +1,503 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	"github.com/openai/openai-go/responses"
+	"github.com/openai/openai-go/shared"
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/httpclient"
+	"github.com/opencode-ai/opencode/internal/llm/models"
+	"github.com/opencode-ai/opencode/internal/llm/tools"
+	"github.com/opencode-ai/opencode/internal/logging"
+	"github.com/opencode-ai/opencode/internal/message"
+	"github.com/opencode-ai/opencode/internal/tracing"
+)
+
+// openaiResponsesClient talks to the OpenAI Responses API. Requests are sent
+// with store disabled, so the encrypted reasoning items returned by the API
+// are kept in the message parts and sent back on the next turn.
+type openaiResponsesClient struct {
+	providerOptions providerClientOptions
+	options         openaiOptions
+	client          openai.Client
+}
+
+type OpenAIResponsesClient ProviderClient
+
+func newOpenAIResponsesClient(opts providerClientOptions) OpenAIResponsesClient {
+	openaiOpts := openaiOptions{
+		reasoningEffort: "medium",
+	}
+	for _, o := range opts.openaiOptions {
+		o(&openaiOpts)
+	}
+
+	openaiClientOptions := []option.RequestOption{
+		option.WithHTTPClient(httpclient.New(0)),
+	}
+	if opts.apiKey != "" {
+		openaiClientOptions = append(openaiClientOptions, option.WithAPIKey(opts.apiKey))
+	}
+	if openaiOpts.baseURL != "" {
+		openaiClientOptions = append(openaiClientOptions, option.WithBaseURL(openaiOpts.baseURL))
+	}
+
+	if openaiOpts.extraHeaders != nil {
+		for key, value := range openaiOpts.extraHeaders {
+			openaiClientOptions = append(openaiClientOptions, option.WithHeader(key, value))
+		}
+	}
+
+	client := openai.NewClient(openaiClientOptions...)
+	return &openaiResponsesClient{
+		providerOptions: opts,
+		options:         openaiOpts,
+		client:          client,
+	}
+}
+
+func (o *openaiResponsesClient) convertMessages(messages []message.Message) (input responses.ResponseInputParam) {
+	for _, msg := range messages {
+		switch msg.Role {
+		case message.User:
+			content := responses.ResponseInputMessageContentListParam{
+				{OfInputText: &responses.ResponseInputTextParam{Text: msg.Content().String()}},
+			}
+			for _, binaryContent := range msg.BinaryContent() {
+				content = append(content, o.imageContent(binaryContent))
+			}
+			input = append(input, responses.ResponseInputItemUnionParam{
+				OfMessage: &responses.EasyInputMessageParam{
+					Role:    responses.EasyInputMessageRoleUser,
+					Content: responses.EasyInputMessageContentUnionParam{OfInputItemContentList: content},
+				},
+			})
+
+		case message.Assistant:
+			// Reasoning items have to come before the output they belong to
+			for _, state := range msg.ReasoningStates() {
+				reasoning := responses.ResponseReasoningItemParam{
+					ID:      state.ID,
+					Summary: []responses.ResponseReasoningItemSummaryParam{},
+				}
+				for _, summary := range state.Summary {
+					reasoning.Summary = append(reasoning.Summary, responses.ResponseReasoningItemSummaryParam{Text: summary})
+				}
+				if state.EncryptedContent != "" {
+					reasoning.WithExtraFields(map[string]any{
+						"encrypted_content": state.EncryptedContent,
+					})
+				}
+				input = append(input, responses.ResponseInputItemUnionParam{OfReasoning: &reasoning})
+			}
+
+			if msg.Content().String() != "" {
+				input = append(input, responses.ResponseInputItemUnionParam{
+					OfMessage: &responses.EasyInputMessageParam{
+						Role:    responses.EasyInputMessageRoleAssistant,
+						Content: responses.EasyInputMessageContentUnionParam{OfString: openai.String(msg.Content().String())},
+					},
+				})
+			}
+
+			for _, call := range msg.ToolCalls() {
+				input = append(input, responses.ResponseInputItemUnionParam{
+					OfFunctionCall: &responses.ResponseFunctionToolCallParam{
+						CallID:    call.ID,
+						Name:      call.Name,
+						Arguments: call.Input,
+					},
+				})
+			}
+
+		case message.Tool:
+			var images responses.ResponseInputMessageContentListParam
+			for _, result := range msg.ToolResults() {
+				input = append(input, responses.ResponseInputItemUnionParam{
+					OfFunctionCallOutput: &responses.ResponseInputItemFunctionCallOutputParam{
+						CallID: result.ToolCallID,
+						Output: result.Content,
+					},
+				})
+				for _, image := range result.Images {
+					images = append(images, o.imageContent(image))
+				}
+			}
+			// Function call outputs can only hold text, so images returned by tools follow in a user message
+			if len(images) > 0 {
+				content := responses.ResponseInputMessageContentListParam{
+					{OfInputText: &responses.ResponseInputTextParam{Text: "Images returned by the tool calls above:"}},
+				}
+				input = append(input, responses.ResponseInputItemUnionParam{
+					OfMessage: &responses.EasyInputMessageParam{
+						Role:    responses.EasyInputMessageRoleUser,
+						Content: responses.EasyInputMessageContentUnionParam{OfInputItemContentList: append(content, images...)},
+					},
+				})
+			}
+		}
+	}
+
+	return
+}
+
+func (o *openaiResponsesClient) imageContent(binaryContent message.BinaryContent) responses.ResponseInputContentUnionParam {
+	return responses.ResponseInputContentUnionParam{
+		OfInputImage: &responses.ResponseInputImageParam{
+			Detail:   responses.ResponseInputImageDetailAuto,
+			ImageURL: openai.String(binaryContent.String(models.ProviderOpenAI)),
+		},
+	}
+}
+
+func (o *openaiResponsesClient) convertTools(tools []tools.BaseTool) []responses.ToolUnionParam {
+	openaiTools := make([]responses.ToolUnionParam, len(tools))
+
+	for i, tool := range tools {
+		info := tool.Info()
+		openaiTools[i] = responses.ToolUnionParam{
+			OfFunction: &responses.FunctionToolParam{
+				Name:        info.Name,
+				Description: openai.String(info.Description),
+				Parameters: map[string]any{
+					"type":       "object",
+					"properties": info.Parameters,
+					"required":   info.Required,
+				},
+			},
+		}
+	}
+
+	return openaiTools
+}
+
+func (o *openaiResponsesClient) finishReason(response responses.Response, toolCalls []message.ToolCall) message.FinishReason {
+	if len(toolCalls) > 0 {
+		return message.FinishReasonToolUse
+	}
+	switch response.Status {
+	case responses.ResponseStatusCompleted:
+		return message.FinishReasonEndTurn
+	case responses.ResponseStatusIncomplete:
+		if response.IncompleteDetails.Reason == "max_output_tokens" {
+			return message.FinishReasonMaxTokens
+		}
+		return message.FinishReasonUnknown
+	case responses.ResponseStatusFailed:
+		return message.FinishReasonError
+	default:
+		return message.FinishReasonUnknown
+	}
+}
+
+func (o *openaiResponsesClient) preparedParams(input responses.ResponseInputParam, tools []responses.ToolUnionParam) responses.ResponseNewParams {
+	params := responses.ResponseNewParams{
+		Model:           shared.ResponsesModel(o.providerOptions.model.APIModel),
+		Input:           responses.ResponseNewParamsInputUnion{OfInputItemList: input},
+		Instructions:    openai.String(o.providerOptions.systemMessage),
+		MaxOutputTokens: openai.Int(o.providerOptions.maxTokens),
+		Tools:           tools,
+		Store:           openai.Bool(false),
+	}
+
+	if o.providerOptions.model.CanReason {
+		params.Include = []responses.ResponseIncludable{"reasoning.encrypted_content"}
+		params.Reasoning = shared.ReasoningParam{}
+		switch o.options.reasoningEffort {
+		case "low":
+			params.Reasoning.Effort = shared.ReasoningEffortLow
+		case "medium":
+			params.Reasoning.Effort = shared.ReasoningEffortMedium
+		case "high":
+			params.Reasoning.Effort = shared.ReasoningEffortHigh
+		default:
+			params.Reasoning.Effort = shared.ReasoningEffortMedium
+		}
+		params.Reasoning.WithExtraFields(map[string]any{
+			"summary": "auto",
+		})
+	}
+
+	return params
+}
+
+func (o *openaiResponsesClient) send(ctx context.Context, messages []message.Message, tools []tools.BaseTool) (response *ProviderResponse, err error) {
+	params := o.preparedParams(o.convertMessages(messages), o.convertTools(tools))
+	cfg := config.Get()
+	if cfg.Debug {
+		jsonData, _ := json.Marshal(params)
+		logging.Debug("Prepared messages", "messages", string(jsonData))
+	}
+	attempts := 0
+	for {
+		attempts++
+		openaiResponse, err := o.client.Responses.New(
+			ctx,
+			params,
+		)
+		// If there is an error we are going to see if we can retry the call
+		if err != nil {
+			retry, after, retryErr := o.shouldRetry(attempts, err)
+			if retryErr != nil {
+				return nil, retryErr
+			}
+			if retry {
+				logging.WarnPersist(fmt.Sprintf("Retrying due to rate limit... attempt %d of %d", attempts, maxRetries), logging.PersistTimeArg, time.Millisecond*time.Duration(after+100))
+				tracing.AddRetry(ctx, attempts, after)
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After(time.Duration(after) * time.Millisecond):
+					continue
+				}
+			}
+			return nil, retryErr
+		}
+
+		return o.providerResponse(*openaiResponse), nil
+	}
+}
+
+func (o *openaiResponsesClient) stream(ctx context.Context, messages []message.Message, tools []tools.BaseTool) <-chan ProviderEvent {
+	params := o.preparedParams(o.convertMessages(messages), o.convertTools(tools))
+
+	cfg := config.Get()
+	if cfg.Debug {
+		jsonData, _ := json.Marshal(params)
+		logging.Debug("Prepared messages", "messages", string(jsonData))
+	}
+
+	attempts := 0
+	eventChan := make(chan ProviderEvent)
+
+	go func() {
+		for {
+			attempts++
+			openaiStream := o.client.Responses.NewStreaming(
+				ctx,
+				params,
+			)
+
+			var completed *responses.Response
+			for openaiStream.Next() {
+				event := openaiStream.Current()
+				switch event.Type {
+				case "response.output_text.delta":
+					if event.Delta != "" {
+						eventChan <- ProviderEvent{
+							Type:    EventContentDelta,
+							Content: event.Delta,
+						}
+					}
+				case "response.reasoning_summary_text.delta":
+					if event.Delta != "" {
+						eventChan <- ProviderEvent{
+							Type:     EventThinkingDelta,
+							Thinking: event.Delta,
+						}
+					}
+				case "response.reasoning_summary_part.done":
+					// Keep the summary parts apart in the reasoning content
+					eventChan <- ProviderEvent{
+						Type:     EventThinkingDelta,
+						Thinking: "\n\n",
+					}
+				case "response.output_item.added":
+					if event.Item.Type == "function_call" {
+						eventChan <- ProviderEvent{
+							Type: EventToolUseStart,
+							ToolCall: &message.ToolCall{
+								ID:       event.Item.CallID,
+								Name:     event.Item.Name,
+								Finished: false,
+							},
+						}
+					}
+				case "response.output_item.done":
+					if event.Item.Type == "function_call" {
+						eventChan <- ProviderEvent{
+							Type: EventToolUseStop,
+							ToolCall: &message.ToolCall{
+								ID: event.Item.CallID,
+							},
+						}
+					}
+				case "response.completed", "response.incomplete":
+					response := event.Response
+					completed = &response
+				case "response.failed":
+					openaiStream.Close()
+					eventChan <- ProviderEvent{
+						Type:  EventError,
+						Error: fmt.Errorf("response failed: %s", event.Response.Error.Message),
+					}
+					close(eventChan)
+					return
+				case "error", "":
+					// The SDK wraps events it doesn't know, like the error event, as {"event": ..., "data": ...}
+					errMessage := event.Message
+					if event.Type == "" {
+						var wrapped struct {
+							Event string `json:"event"`
+							Data  struct {
+								Message string `json:"message"`
+							} `json:"data"`
+						}
+						if err := json.Unmarshal([]byte(event.RawJSON()), &wrapped); err != nil || wrapped.Event != "error" {
+							continue
+						}
+						errMessage = wrapped.Data.Message
+					}
+					openaiStream.Close()
+					eventChan <- ProviderEvent{
+						Type:  EventError,
+						Error: fmt.Errorf("response error: %s", errMessage),
+					}
+					close(eventChan)
+					return
+				}
+			}
+
+			err := openaiStream.Err()
+			if err == nil || errors.Is(err, io.EOF) {
+				if completed == nil {
+					eventChan <- ProviderEvent{Type: EventError, Error: errors.New("response stream ended before the response was completed")}
+					close(eventChan)
+					return
+				}
+				eventChan <- ProviderEvent{
+					Type:     EventComplete,
+					Response: o.providerResponse(*completed),
+				}
+				close(eventChan)
+				return
+			}
+
+			// If there is an error we are going to see if we can retry the call
+			retry, after, retryErr := o.shouldRetry(attempts, err)
+			if retryErr != nil {
+				eventChan <- ProviderEvent{Type: EventError, Error: retryErr}
+				close(eventChan)
+				return
+			}
+			if retry {
+				logging.WarnPersist(fmt.Sprintf("Retrying due to rate limit... attempt %d of %d", attempts, maxRetries), logging.PersistTimeArg, time.Millisecond*time.Duration(after+100))
+				tracing.AddRetry(ctx, attempts, after)
+				select {
+				case <-ctx.Done():
+					// context cancelled
+					if ctx.Err() != nil {
+						eventChan <- ProviderEvent{Type: EventError, Error: ctx.Err()}
+					}
+					close(eventChan)
+					return
+				case <-time.After(time.Duration(after) * time.Millisecond):
+					continue
+				}
+			}
+			eventChan <- ProviderEvent{Type: EventError, Error: retryErr}
+			close(eventChan)
+			return
+		}
+	}()
+
+	return eventChan
+}
+
+func (o *openaiResponsesClient) shouldRetry(attempts int, err error) (bool, int64, error) {
+	var apierr *openai.Error
+	if !errors.As(err, &apierr) {
+		return false, 0, err
+	}
+
+	if apierr.StatusCode != 429 && apierr.StatusCode != 500 {
+		return false, 0, err
+	}
+
+	if attempts > maxRetries {
+		return false, 0, fmt.Errorf("maximum retry attempts reached for rate limit: %d retries", maxRetries)
+	}
+
+	retryMs := 0
+	retryAfterValues := apierr.Response.Header.Values("Retry-After")
+
+	backoffMs := 2000 * (1 << (attempts - 1))
+	jitterMs := int(float64(backoffMs) * 0.2)
+	retryMs = backoffMs + jitterMs
+	if len(retryAfterValues) > 0 {
+		if _, err := fmt.Sscanf(retryAfterValues[0], "%d", &retryMs); err == nil {
+			retryMs = retryMs * 1000
+		}
+	}
+	return true, int64(retryMs), nil
+}
+
+func (o *openaiResponsesClient) providerResponse(response responses.Response) *ProviderResponse {
+	var content strings.Builder
+	var toolCalls []message.ToolCall
+	var reasoningStates []message.ReasoningState
+
+	for _, item := range response.Output {
+		switch item.Type {
+		case "message":
+			for _, part := range item.Content {
+				if part.Type == "output_text" {
+					content.WriteString(part.Text)
+				}
+			}
+		case "function_call":
+			toolCalls = append(toolCalls, message.ToolCall{
+				ID:       item.CallID,
+				Name:     item.Name,
+				Input:    item.Arguments,
+				Type:     "function",
+				Finished: true,
+			})
+		case "reasoning":
+			// The SDK doesn't know about encrypted content yet, so read it from the raw item
+			var raw struct {
+				EncryptedContent string `json:"encrypted_content"`
+			}
+			if err := json.Unmarshal([]byte(item.RawJSON()), &raw); err != nil {
+				logging.Warn("Failed to read reasoning item", "error", err)
+			}
+			state := message.ReasoningState{
+				ID:               item.ID,
+				EncryptedContent: raw.EncryptedContent,
+			}
+			for _, summary := range item.Summary {
+				state.Summary = append(state.Summary, summary.Text)
+			}
+			reasoningStates = append(reasoningStates, state)
+		}
+	}
+
+	return &ProviderResponse{
+		Content:         content.String(),
+		ToolCalls:       toolCalls,
+		Usage:           o.usage(response),
+		FinishReason:    o.finishReason(response, toolCalls),
+		ReasoningStates: reasoningStates,
+	}
+}
+
+func (o *openaiResponsesClient) usage(response responses.Response) TokenUsage {
+	cachedTokens := response.Usage.InputTokensDetails.CachedTokens
+	inputTokens := response.Usage.InputTokens - cachedTokens
+
+	return TokenUsage{
+		InputTokens:         inputTokens,
+		OutputTokens:        response.Usage.OutputTokens,
+		CacheCreationTokens: 0, // OpenAI doesn't provide this directly
+		CacheReadTokens:     cachedTokens,
+	}
+}
@@ -0,0 +1,262 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openai/openai-go/responses"
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/llm/models"
+	"github.com/opencode-ai/opencode/internal/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIResponsesConvertMessages(t *testing.T) {
+	client := &openaiResponsesClient{}
+
+	tests := []struct {
+		name    string
+		message message.Message
+		want    string
+	}{
+		{
+			name: "user text and image",
+			message: message.Message{Role: message.User, Parts: []message.ContentPart{
+				message.TextContent{Text: "what is this?"},
+				message.BinaryContent{MIMEType: "image/png", Data: []byte("png")},
+			}},
+			want: `[{"role": "user", "content": [
+				{"type": "input_text", "text": "what is this?"},
+				{"type": "input_image", "detail": "auto", "image_url": "data:image/png;base64,cG5n"}
+			]}]`,
+		},
+		{
+			name: "assistant reasoning, text and tool calls",
+			message: message.Message{Role: message.Assistant, Parts: []message.ContentPart{
+				message.ReasoningState{ID: "rs_1", EncryptedContent: "opaque", Summary: []string{"Looking at the file"}},
+				message.TextContent{Text: "Let me check."},
+				message.ToolCall{ID: "call_1", Name: "view", Input: `{"file_path":"main.go"}`, Finished: true},
+			}},
+			want: `[
+				{"type": "reasoning", "id": "rs_1", "encrypted_content": "opaque", "summary": [{"type": "summary_text", "text": "Looking at the file"}]},
+				{"role": "assistant", "content": "Let me check."},
+				{"type": "function_call", "call_id": "call_1", "name": "view", "arguments": "{\"file_path\":\"main.go\"}"}
+			]`,
+		},
+		{
+			name: "assistant tool call without text",
+			message: message.Message{Role: message.Assistant, Parts: []message.ContentPart{
+				message.ToolCall{ID: "call_1", Name: "ls", Input: `{}`, Finished: true},
+			}},
+			want: `[{"type": "function_call", "call_id": "call_1", "name": "ls", "arguments": "{}"}]`,
+		},
+		{
+			name: "tool results",
+			message: message.Message{Role: message.Tool, Parts: []message.ContentPart{
+				message.ToolResult{ToolCallID: "call_1", Content: "package main"},
+				message.ToolResult{ToolCallID: "call_2", Content: "not found", IsError: true},
+			}},
+			want: `[
+				{"type": "function_call_output", "call_id": "call_1", "output": "package main"},
+				{"type": "function_call_output", "call_id": "call_2", "output": "not found"}
+			]`,
+		},
+		{
+			name: "tool result with an image",
+			message: message.Message{Role: message.Tool, Parts: []message.ContentPart{
+				message.ToolResult{ToolCallID: "call_1", Content: "Image read", Images: []message.BinaryContent{{MIMEType: "image/jpeg", Data: []byte("jpg")}}},
+			}},
+			want: `[
+				{"type": "function_call_output", "call_id": "call_1", "output": "Image read"},
+				{"role": "user", "content": [
+					{"type": "input_text", "text": "Images returned by the tool calls above:"},
+					{"type": "input_image", "detail": "auto", "image_url": "data:image/jpeg;base64,anBn"}
+				]}
+			]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(client.convertMessages([]message.Message{tt.message}))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestOpenAIResponsesProviderResponse(t *testing.T) {
+	client := &openaiResponsesClient{}
+
+	tests := []struct {
+		name     string
+		response string
+		want     *ProviderResponse
+	}{
+		{
+			name: "text",
+			response: `{"status": "completed", "output": [
+				{"type": "message", "content": [{"type": "output_text", "text": "Hello"}, {"type": "output_text", "text": " world"}]}
+			], "usage": {"input_tokens": 100, "output_tokens": 20, "input_tokens_details": {"cached_tokens": 40}}}`,
+			want: &ProviderResponse{
+				Content:      "Hello world",
+				Usage:        TokenUsage{InputTokens: 60, OutputTokens: 20, CacheReadTokens: 40},
+				FinishReason: message.FinishReasonEndTurn,
+			},
+		},
+		{
+			name: "reasoning and tool call",
+			response: `{"status": "completed", "output": [
+				{"type": "reasoning", "id": "rs_1", "encrypted_content": "opaque", "summary": [{"type": "summary_text", "text": "Checking"}]},
+				{"type": "function_call", "call_id": "call_1", "name": "view", "arguments": "{}"}
+			]}`,
+			want: &ProviderResponse{
+				ToolCalls:       []message.ToolCall{{ID: "call_1", Name: "view", Input: "{}", Type: "function", Finished: true}},
+				FinishReason:    message.FinishReasonToolUse,
+				ReasoningStates: []message.ReasoningState{{ID: "rs_1", EncryptedContent: "opaque", Summary: []string{"Checking"}}},
+			},
+		},
+		{
+			name:     "max output tokens",
+			response: `{"status": "incomplete", "incomplete_details": {"reason": "max_output_tokens"}}`,
+			want:     &ProviderResponse{FinishReason: message.FinishReasonMaxTokens},
+		},
+		{
+			name:     "content filter",
+			response: `{"status": "incomplete", "incomplete_details": {"reason": "content_filter"}}`,
+			want:     &ProviderResponse{FinishReason: message.FinishReasonUnknown},
+		},
+		{
+			name:     "failed",
+			response: `{"status": "failed"}`,
+			want:     &ProviderResponse{FinishReason: message.FinishReasonError},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response responses.Response
+			require.NoError(t, json.Unmarshal([]byte(tt.response), &response))
+			assert.Equal(t, tt.want, client.providerResponse(response))
+		})
+	}
+}
+
+func TestOpenAIResponsesStream(t *testing.T) {
+	_, err := config.Load(t.TempDir(), false)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		events []string
+		want   []ProviderEvent
+		err    string
+	}{
+		{
+			name: "text and reasoning",
+			events: []string{
+				`{"type": "response.reasoning_summary_text.delta", "delta": "Thinking"}`,
+				`{"type": "response.reasoning_summary_part.done"}`,
+				`{"type": "response.output_text.delta", "delta": "Hi"}`,
+				`{"type": "response.output_text.delta", "delta": ""}`,
+				`{"type": "response.completed", "response": {"status": "completed", "output": [
+					{"type": "message", "content": [{"type": "output_text", "text": "Hi"}]}
+				]}}`,
+			},
+			want: []ProviderEvent{
+				{Type: EventThinkingDelta, Thinking: "Thinking"},
+				{Type: EventThinkingDelta, Thinking: "\n\n"},
+				{Type: EventContentDelta, Content: "Hi"},
+				{Type: EventComplete, Response: &ProviderResponse{Content: "Hi", FinishReason: message.FinishReasonEndTurn}},
+			},
+		},
+		{
+			name: "tool call",
+			events: []string{
+				`{"type": "response.output_item.added", "item": {"type": "function_call", "call_id": "call_1", "name": "ls"}}`,
+				`{"type": "response.output_item.added", "item": {"type": "message"}}`,
+				`{"type": "response.output_item.done", "item": {"type": "function_call", "call_id": "call_1", "name": "ls", "arguments": "{}"}}`,
+				`{"type": "response.completed", "response": {"status": "completed", "output": [
+					{"type": "function_call", "call_id": "call_1", "name": "ls", "arguments": "{}"}
+				]}}`,
+			},
+			want: []ProviderEvent{
+				{Type: EventToolUseStart, ToolCall: &message.ToolCall{ID: "call_1", Name: "ls"}},
+				{Type: EventToolUseStop, ToolCall: &message.ToolCall{ID: "call_1"}},
+				{Type: EventComplete, Response: &ProviderResponse{
+					ToolCalls:    []message.ToolCall{{ID: "call_1", Name: "ls", Input: "{}", Type: "function", Finished: true}},
+					FinishReason: message.FinishReasonToolUse,
+				}},
+			},
+		},
+		{
+			name: "incomplete",
+			events: []string{
+				`{"type": "response.output_text.delta", "delta": "Hi"}`,
+				`{"type": "response.incomplete", "response": {"status": "incomplete", "incomplete_details": {"reason": "max_output_tokens"}}}`,
+			},
+			want: []ProviderEvent{
+				{Type: EventContentDelta, Content: "Hi"},
+				{Type: EventComplete, Response: &ProviderResponse{FinishReason: message.FinishReasonMaxTokens}},
+			},
+		},
+		{
+			name:   "failed",
+			events: []string{`{"type": "response.failed", "response": {"status": "failed", "error": {"code": "server_error", "message": "overloaded"}}}`},
+			err:    "response failed: overloaded",
+		},
+		{
+			name:   "error",
+			events: []string{`{"type": "error", "message": "bad request"}`},
+			err:    "response error: bad request",
+		},
+		{
+			name:   "cut off",
+			events: []string{`{"type": "response.output_text.delta", "delta": "Hi"}`},
+			want:   []ProviderEvent{{Type: EventContentDelta, Content: "Hi"}},
+			err:    "response stream ended before the response was completed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				for _, event := range tt.events {
+					var typed struct {
+						Type string `json:"type"`
+					}
+					require.NoError(t, json.Unmarshal([]byte(event), &typed))
+					compact, err := json.Marshal(json.RawMessage(event))
+					require.NoError(t, err)
+					fmt.Fprintf(w, "event: %s\ndata: %s\n\n", typed.Type, compact)
+				}
+			}))
+			defer server.Close()
+
+			client := newOpenAIResponsesClient(providerClientOptions{
+				apiKey:        "test",
+				model:         models.Model{APIModel: "gpt-test"},
+				openaiOptions: []OpenAIOption{WithOpenAIBaseURL(server.URL)},
+			})
+
+			var got []ProviderEvent
+			var gotErr error
+			for event := range client.stream(context.Background(), nil, nil) {
+				if event.Type == EventError {
+					gotErr = event.Error
+					continue
+				}
+				got = append(got, event)
+			}
+			assert.Equal(t, tt.want, got)
+			if tt.err != "" {
+				assert.EqualError(t, gotErr, tt.err)
+			} else {
+				assert.NoError(t, gotErr)
+			}
+		})
+	}
+}
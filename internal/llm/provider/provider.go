@@ -2,12 +2,20 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"sync"
 
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/openai/openai-go"
 	"github.com/opencode-ai/opencode/internal/llm/models"
+	"github.com/opencode-ai/opencode/internal/llm/tokens"
 	"github.com/opencode-ai/opencode/internal/llm/tools"
+	"github.com/opencode-ai/opencode/internal/logging"
 	"github.com/opencode-ai/opencode/internal/message"
+	"google.golang.org/genai"
 )
 
 type EventType string
@@ -39,6 +47,9 @@ type ProviderResponse struct {
 	ToolCalls    []message.ToolCall
 	Usage        TokenUsage
 	FinishReason message.FinishReason
+
+	// Reasoning state the provider needs back on the next turn
+	ReasoningStates []message.ReasoningState
 }
 
 type ProviderEvent struct {
@@ -56,10 +67,17 @@ type Provider interface {
 	StreamResponse(ctx context.Context, messages []message.Message, tools []tools.BaseTool) <-chan ProviderEvent
 
 	Model() models.Model
+
+	// MaxTokens is the most the model may generate for a response
+	MaxTokens() int64
+
+	// EstimateTokens estimates the input tokens of a request before it is sent
+	EstimateTokens(messages []message.Message, tools []tools.BaseTool) int64
 }
 
 type providerClientOptions struct {
 	apiKey        string
+	refreshAPIKey func() (string, error)
 	model         models.Model
 	maxTokens     int64
 	systemMessage string
@@ -79,8 +97,18 @@ type ProviderClient interface {
 }
 
 type baseProvider[C ProviderClient] struct {
-	options providerClientOptions
-	client  C
+	options   providerClientOptions
+	client    C
+	newClient func(providerClientOptions) C
+	mu        sync.RWMutex
+}
+
+func newBaseProvider[C ProviderClient](options providerClientOptions, newClient func(providerClientOptions) C) *baseProvider[C] {
+	return &baseProvider[C]{
+		options:   options,
+		client:    newClient(options),
+		newClient: newClient,
+	}
 }
 
 func NewProvider(providerName models.ModelProvider, opts ...ProviderClientOption) (Provider, error) {
@@ -90,48 +118,27 @@ func NewProvider(providerName models.ModelProvider, opts ...ProviderClientOption
 	}
 	switch providerName {
 	case models.ProviderCopilot:
-		return &baseProvider[CopilotClient]{
-			options: clientOptions,
-			client:  newCopilotClient(clientOptions),
-		}, nil
+		return newBaseProvider(clientOptions, newCopilotClient), nil
 	case models.ProviderAnthropic:
-		return &baseProvider[AnthropicClient]{
-			options: clientOptions,
-			client:  newAnthropicClient(clientOptions),
-		}, nil
+		return newBaseProvider(clientOptions, newAnthropicClient), nil
 	case models.ProviderOpenAI:
-		return &baseProvider[OpenAIClient]{
-			options: clientOptions,
-			client:  newOpenAIClient(clientOptions),
-		}, nil
+		if clientOptions.model.UseResponsesAPI {
+			return newBaseProvider(clientOptions, newOpenAIResponsesClient), nil
+		}
+		return newBaseProvider(clientOptions, newOpenAIClient), nil
 	case models.ProviderGemini:
-		return &baseProvider[GeminiClient]{
-			options: clientOptions,
-			client:  newGeminiClient(clientOptions),
-		}, nil
+		return newBaseProvider(clientOptions, newGeminiClient), nil
 	case models.ProviderBedrock:
-		return &baseProvider[BedrockClient]{
-			options: clientOptions,
-			client:  newBedrockClient(clientOptions),
-		}, nil
+		return newBaseProvider(clientOptions, newBedrockClient), nil
 	case models.ProviderGROQ:
 		clientOptions.openaiOptions = append(clientOptions.openaiOptions,
 			WithOpenAIBaseURL("https://api.groq.com/openai/v1"),
 		)
-		return &baseProvider[OpenAIClient]{
-			options: clientOptions,
-			client:  newOpenAIClient(clientOptions),
-		}, nil
+		return newBaseProvider(clientOptions, newOpenAIClient), nil
 	case models.ProviderAzure:
-		return &baseProvider[AzureClient]{
-			options: clientOptions,
-			client:  newAzureClient(clientOptions),
-		}, nil
+		return newBaseProvider(clientOptions, newAzureClient), nil
 	case models.ProviderVertexAI:
-		return &baseProvider[VertexAIClient]{
-			options: clientOptions,
-			client:  newVertexAIClient(clientOptions),
-		}, nil
+		return newBaseProvider(clientOptions, newVertexAIClient), nil
 	case models.ProviderOpenRouter:
 		clientOptions.openaiOptions = append(clientOptions.openaiOptions,
 			WithOpenAIBaseURL("https://openrouter.ai/api/v1"),
@@ -140,26 +147,17 @@ func NewProvider(providerName models.ModelProvider, opts ...ProviderClientOption
 				"X-Title":      "OpenCode",
 			}),
 		)
-		return &baseProvider[OpenAIClient]{
-			options: clientOptions,
-			client:  newOpenAIClient(clientOptions),
-		}, nil
+		return newBaseProvider(clientOptions, newOpenAIClient), nil
 	case models.ProviderXAI:
 		clientOptions.openaiOptions = append(clientOptions.openaiOptions,
 			WithOpenAIBaseURL("https://api.x.ai/v1"),
 		)
-		return &baseProvider[OpenAIClient]{
-			options: clientOptions,
-			client:  newOpenAIClient(clientOptions),
-		}, nil
+		return newBaseProvider(clientOptions, newOpenAIClient), nil
 	case models.ProviderLocal:
 		clientOptions.openaiOptions = append(clientOptions.openaiOptions,
 			WithOpenAIBaseURL(os.Getenv("LOCAL_ENDPOINT")),
 		)
-		return &baseProvider[OpenAIClient]{
-			options: clientOptions,
-			client:  newOpenAIClient(clientOptions),
-		}, nil
+		return newBaseProvider(clientOptions, newOpenAIClient), nil
 	case models.ProviderMock:
 		// TODO: implement mock client for test
 		panic("not implemented")
@@ -178,18 +176,97 @@ func (p *baseProvider[C]) cleanMessages(messages []message.Message) (cleaned []m
 	return
 }
 
+func (p *baseProvider[C]) getClient() C {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.client
+}
+
+// refreshClient reloads a rejected API key and recreates the client with it.
+// It reports whether there is a new key worth retrying with.
+func (p *baseProvider[C]) refreshClient() bool {
+	if p.options.refreshAPIKey == nil {
+		return false
+	}
+	apiKey, err := p.options.refreshAPIKey()
+	if err != nil {
+		logging.Debug("API key was not refreshed", "provider", p.options.model.Provider, "error", err)
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.options.apiKey = apiKey
+	p.client = p.newClient(p.options)
+	return true
+}
+
+// isUnauthorized reports whether the provider rejected the API key.
+func isUnauthorized(err error) bool {
+	var anthropicErr *anthropic.Error
+	if errors.As(err, &anthropicErr) {
+		return anthropicErr.StatusCode == http.StatusUnauthorized
+	}
+	var openaiErr *openai.Error
+	if errors.As(err, &openaiErr) {
+		return openaiErr.StatusCode == http.StatusUnauthorized
+	}
+	var genaiErr genai.APIError
+	if errors.As(err, &genaiErr) {
+		return genaiErr.Code == http.StatusUnauthorized
+	}
+	return false
+}
+
 func (p *baseProvider[C]) SendMessages(ctx context.Context, messages []message.Message, tools []tools.BaseTool) (*ProviderResponse, error) {
 	messages = p.cleanMessages(messages)
-	return p.client.send(ctx, messages, tools)
+	response, err := p.getClient().send(ctx, messages, tools)
+	if err != nil && isUnauthorized(err) && p.refreshClient() {
+		return p.getClient().send(ctx, messages, tools)
+	}
+	return response, err
 }
 
 func (p *baseProvider[C]) Model() models.Model {
 	return p.options.model
 }
 
+func (p *baseProvider[C]) MaxTokens() int64 {
+	return p.options.maxTokens
+}
+
+func (p *baseProvider[C]) EstimateTokens(messages []message.Message, tools []tools.BaseTool) int64 {
+	return tokens.Estimate(p.options.model, p.options.systemMessage, p.cleanMessages(messages), tools)
+}
+
 func (p *baseProvider[C]) StreamResponse(ctx context.Context, messages []message.Message, tools []tools.BaseTool) <-chan ProviderEvent {
 	messages = p.cleanMessages(messages)
-	return p.client.stream(ctx, messages, tools)
+	if p.options.refreshAPIKey == nil {
+		return p.getClient().stream(ctx, messages, tools)
+	}
+
+	eventChan := make(chan ProviderEvent)
+	go func() {
+		defer close(eventChan)
+		events := p.getClient().stream(ctx, messages, tools)
+		refreshed := false
+		for {
+			event, ok := <-events
+			if !ok {
+				return
+			}
+			// A rejected key fails before anything streamed, so the request
+			// can start over with the new key
+			if event.Type == EventError && !refreshed && isUnauthorized(event.Error) {
+				refreshed = true
+				if p.refreshClient() {
+					events = p.getClient().stream(ctx, messages, tools)
+					continue
+				}
+			}
+			eventChan <- event
+		}
+	}()
+	return eventChan
 }
 
 func WithAPIKey(apiKey string) ProviderClientOption {
@@ -198,6 +275,14 @@ func WithAPIKey(apiKey string) ProviderClientOption {
 	}
 }
 
+// WithAPIKeyRefresh sets how to load the API key again after the provider
+// rejected it, e.g. because the key was rotated.
+func WithAPIKeyRefresh(refresh func() (string, error)) ProviderClientOption {
+	return func(options *providerClientOptions) {
+		options.refreshAPIKey = refresh
+	}
+}
+
 func WithModel(model models.Model) ProviderClientOption {
 	return func(options *providerClientOptions) {
 		options.model = model
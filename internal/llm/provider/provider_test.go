@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/opencode-ai/opencode/internal/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertToolResultImages(t *testing.T) {
+	call := message.Message{Role: message.Assistant, Parts: []message.ContentPart{
+		message.ToolCall{ID: "call_1", Name: "view", Input: `{"file_path":"a.png"}`, Finished: true},
+	}}
+	withImage := message.Message{Role: message.Tool, Parts: []message.ContentPart{
+		message.ToolResult{ToolCallID: "call_1", Content: "Image a.png", Images: []message.BinaryContent{{MIMEType: "image/png", Data: []byte("png")}}},
+	}}
+	// What models without vision get: the text content only
+	textOnly := message.Message{Role: message.Tool, Parts: []message.ContentPart{
+		message.ToolResult{ToolCallID: "call_1", Content: "Image a.png"},
+	}}
+
+	// Each converts the tool message, leaving out what comes before it
+	converters := map[string]func(message.Message) any{
+		"anthropic": func(msg message.Message) any {
+			return (&anthropicClient{}).convertMessages([]message.Message{msg})
+		},
+		"openai": func(msg message.Message) any {
+			return (&openaiClient{}).convertMessages([]message.Message{msg})[1:]
+		},
+		"copilot": func(msg message.Message) any {
+			return (&copilotClient{}).convertMessages([]message.Message{msg})[1:]
+		},
+		"gemini": func(msg message.Message) any {
+			// Function responses are named after the call
+			return (&geminiClient{}).convertMessages([]message.Message{call, msg})[1:]
+		},
+	}
+
+	tests := []struct {
+		provider string
+		message  message.Message
+		want     string
+	}{
+		{
+			provider: "anthropic",
+			message:  withImage,
+			want: `[{"role": "user", "content": [{"type": "tool_result", "tool_use_id": "call_1", "is_error": false, "content": [
+				{"type": "text", "text": "Image a.png"},
+				{"type": "image", "source": {"type": "base64", "media_type": "image/png", "data": "cG5n"}}
+			]}]}]`,
+		},
+		{
+			provider: "anthropic",
+			message:  textOnly,
+			want: `[{"role": "user", "content": [{"type": "tool_result", "tool_use_id": "call_1", "is_error": false, "content": [
+				{"type": "text", "text": "Image a.png"}
+			]}]}]`,
+		},
+		{
+			provider: "openai",
+			message:  withImage,
+			want: `[
+				{"role": "tool", "tool_call_id": "call_1", "content": "Image a.png"},
+				{"role": "user", "content": [
+					{"type": "text", "text": "Images returned by the tool calls above:"},
+					{"type": "image_url", "image_url": {"url": "data:image/png;base64,cG5n"}}
+				]}
+			]`,
+		},
+		{
+			provider: "openai",
+			message:  textOnly,
+			want:     `[{"role": "tool", "tool_call_id": "call_1", "content": "Image a.png"}]`,
+		},
+		{
+			provider: "copilot",
+			message:  withImage,
+			want: `[
+				{"role": "tool", "tool_call_id": "call_1", "content": "Image a.png"},
+				{"role": "user", "content": [
+					{"type": "text", "text": "Images returned by the tool calls above:"},
+					{"type": "image_url", "image_url": {"url": "data:image/png;base64,cG5n"}}
+				]}
+			]`,
+		},
+		{
+			provider: "copilot",
+			message:  textOnly,
+			want:     `[{"role": "tool", "tool_call_id": "call_1", "content": "Image a.png"}]`,
+		},
+		{
+			provider: "gemini",
+			message:  withImage,
+			want: `[
+				{"role": "function", "parts": [{"functionResponse": {"name": "view", "response": {"result": "Image a.png"}}}]},
+				{"role": "user", "parts": [
+					{"text": "Images returned by the tool calls above:"},
+					{"inlineData": {"mimeType": "image/png", "data": "cG5n"}}
+				]}
+			]`,
+		},
+		{
+			provider: "gemini",
+			message:  textOnly,
+			want:     `[{"role": "function", "parts": [{"functionResponse": {"name": "view", "response": {"result": "Image a.png"}}}]}]`,
+		},
+	}
+	for _, tt := range tests {
+		name := tt.provider + "/text only"
+		if len(tt.message.ToolResults()[0].Images) > 0 {
+			name = tt.provider + "/with image"
+		}
+		t.Run(name, func(t *testing.T) {
+			got, err := json.Marshal(converters[tt.provider](tt.message))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
@@ -2,8 +2,14 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"os"
 
+	"cloud.google.com/go/auth"
+	"cloud.google.com/go/auth/credentials"
+	"cloud.google.com/go/auth/httptransport"
+	"github.com/opencode-ai/opencode/internal/httpclient"
 	"github.com/opencode-ai/opencode/internal/logging"
 	"google.golang.org/genai"
 )
@@ -16,10 +22,18 @@ func newVertexAIClient(opts providerClientOptions) VertexAIClient {
 		o(&geminiOpts)
 	}
 
-	client, err := genai.NewClient(context.Background(), &genai.ClientConfig{
-		Project:  os.Getenv("VERTEXAI_PROJECT"),
-		Location: os.Getenv("VERTEXAI_LOCATION"),
-		Backend:  genai.BackendVertexAI,
+	ctx := context.Background()
+	creds, client, err := newVertexAIHTTPClient(ctx)
+	if err != nil {
+		logging.Error("Failed to create VertexAI client", "error", err)
+		return nil
+	}
+	genaiClient, err := genai.NewClient(ctx, &genai.ClientConfig{
+		Project:     os.Getenv("VERTEXAI_PROJECT"),
+		Location:    os.Getenv("VERTEXAI_LOCATION"),
+		Backend:     genai.BackendVertexAI,
+		Credentials: creds,
+		HTTPClient:  client,
 	})
 	if err != nil {
 		logging.Error("Failed to create VertexAI client", "error", err)
@@ -29,6 +43,34 @@ func newVertexAIClient(opts providerClientOptions) VertexAIClient {
 	return &geminiClient{
 		providerOptions: opts,
 		options:         geminiOpts,
-		client:          client,
+		client:          genaiClient,
+	}
+}
+
+// newVertexAIHTTPClient builds the authenticated client genai would build
+// itself, but on the shared transport, so the network config applies to the
+// requests and to fetching tokens.
+func newVertexAIHTTPClient(ctx context.Context) (*auth.Credentials, *http.Client, error) {
+	creds, err := credentials.DetectDefault(&credentials.DetectOptions{
+		Scopes: []string{"https://www.googleapis.com/auth/cloud-platform"},
+		Client: httpclient.New(0),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find default credentials: %w", err)
+	}
+	quotaProjectID, err := creds.QuotaProjectID(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get quota project ID: %w", err)
+	}
+	client, err := httptransport.NewClient(&httptransport.Options{
+		Credentials: creds,
+		Headers: http.Header{
+			"X-Goog-User-Project": []string{quotaProjectID},
+		},
+		BaseRoundTripper: httpclient.Transport(),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create HTTP client: %w", err)
 	}
+	return creds, client, nil
 }
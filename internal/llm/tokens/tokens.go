@@ -0,0 +1,220 @@
+// Package tokens estimates how many tokens a request will take before it is
+// sent to the provider. OpenAI models are counted with their tiktoken
+// encoding, the others with a characters-per-token heuristic that is
+// calibrated against the usage the provider reports back.
+package tokens
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/opencode-ai/opencode/internal/llm/models"
+	"github.com/opencode-ai/opencode/internal/llm/tools"
+	"github.com/opencode-ai/opencode/internal/logging"
+	"github.com/opencode-ai/opencode/internal/message"
+	"github.com/pkoukk/tiktoken-go"
+	tiktoken_loader "github.com/pkoukk/tiktoken-go-loader"
+)
+
+const (
+	// Rough cost of an image, providers scale images down to about this much
+	ImageTokens = 1600
+	// Role markers and separators around every message
+	messageOverhead = 4
+	// Name and separators around every tool definition
+	toolOverhead = 8
+
+	defaultCharsPerToken   = 4.0
+	anthropicCharsPerToken = 3.5
+
+	// Weight of the latest observation when calibrating
+	calibrationWeight = 0.3
+	minCalibration    = 0.5
+	maxCalibration    = 2.0
+
+	// Texts shorter than this are cheap to count and not worth caching
+	minCachedLength = 1024
+	maxCacheEntries = 4096
+)
+
+func init() {
+	tiktoken.SetBpeLoader(tiktoken_loader.NewOfflineLoader())
+}
+
+var (
+	encodingsMu sync.Mutex
+	encodings   = map[string]*tiktoken.Tiktoken{}
+
+	cacheMu sync.Mutex
+	cache   = map[uint64]int64{}
+
+	calibrationMu sync.Mutex
+	calibration   = map[models.ModelID]float64{}
+)
+
+// encodingName returns the tiktoken encoding of the model, or an empty string
+// when there is no local tokenizer for it.
+func encodingName(model models.Model) string {
+	apiModel := model.APIModel
+	// OpenRouter style names, e.g. openai/gpt-4.1
+	if i := strings.LastIndex(apiModel, "/"); i >= 0 {
+		apiModel = apiModel[i+1:]
+	}
+	for _, prefix := range []string{"gpt-4o", "gpt-4.1", "gpt-4.5", "gpt-5", "o1", "o3", "o4"} {
+		if strings.HasPrefix(apiModel, prefix) {
+			return tiktoken.MODEL_O200K_BASE
+		}
+	}
+	for _, prefix := range []string{"gpt-4", "gpt-3.5"} {
+		if strings.HasPrefix(apiModel, prefix) {
+			return tiktoken.MODEL_CL100K_BASE
+		}
+	}
+	return ""
+}
+
+func encoding(name string) *tiktoken.Tiktoken {
+	encodingsMu.Lock()
+	defer encodingsMu.Unlock()
+	if enc, ok := encodings[name]; ok {
+		return enc
+	}
+	enc, err := tiktoken.GetEncoding(name)
+	if err != nil {
+		logging.Warn("failed to load tokenizer, falling back to estimates", "encoding", name, "error", err)
+	}
+	// Remember failures too so we don't retry on every call
+	encodings[name] = enc
+	return enc
+}
+
+func charsPerToken(model models.Model) float64 {
+	switch model.Provider {
+	case models.ProviderAnthropic, models.ProviderBedrock:
+		return anthropicCharsPerToken
+	}
+	if strings.Contains(model.APIModel, "claude") {
+		return anthropicCharsPerToken
+	}
+	return defaultCharsPerToken
+}
+
+// Count returns the number of tokens in the text for the model.
+func Count(model models.Model, text string) int64 {
+	if text == "" {
+		return 0
+	}
+	name := encodingName(model)
+	if name == "" {
+		return int64(math.Ceil(float64(len(text)) / charsPerToken(model)))
+	}
+	enc := encoding(name)
+	if enc == nil {
+		return int64(math.Ceil(float64(len(text)) / defaultCharsPerToken))
+	}
+	if len(text) < minCachedLength {
+		return int64(len(enc.EncodeOrdinary(text)))
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	h.Write([]byte(text))
+	key := h.Sum64()
+	cacheMu.Lock()
+	count, ok := cache[key]
+	cacheMu.Unlock()
+	if ok {
+		return count
+	}
+	count = int64(len(enc.EncodeOrdinary(text)))
+	cacheMu.Lock()
+	if len(cache) >= maxCacheEntries {
+		clear(cache)
+	}
+	cache[key] = count
+	cacheMu.Unlock()
+	return count
+}
+
+// Message returns the estimated number of tokens of a single message.
+func Message(model models.Model, msg message.Message) int64 {
+	total := int64(messageOverhead)
+	for _, part := range msg.Parts {
+		switch p := part.(type) {
+		case message.TextContent:
+			total += Count(model, p.Text)
+		case message.ReasoningContent:
+			total += Count(model, p.Thinking)
+		case message.ToolCall:
+			total += Count(model, p.Name) + Count(model, p.Input)
+		case message.ToolResult:
+			total += Count(model, p.Content) + int64(len(p.Images))*ImageTokens
+		case message.BinaryContent:
+			if strings.HasPrefix(p.MIMEType, "image/") {
+				total += ImageTokens
+			} else {
+				total += Count(model, string(p.Data))
+			}
+		case message.ImageURLContent:
+			total += ImageTokens
+		}
+	}
+	return total
+}
+
+// Tools returns the estimated number of tokens of the tool definitions.
+func Tools(model models.Model, baseTools []tools.BaseTool) int64 {
+	var total int64
+	for _, tool := range baseTools {
+		info := tool.Info()
+		params, err := json.Marshal(info.Parameters)
+		if err != nil {
+			continue
+		}
+		total += toolOverhead +
+			Count(model, info.Name) +
+			Count(model, info.Description) +
+			Count(model, string(params)) +
+			Count(model, strings.Join(info.Required, ","))
+	}
+	return total
+}
+
+// Estimate returns the estimated input tokens of a request with the system
+// message, history and tools, adjusted by what the provider reported for
+// earlier requests to the same model.
+func Estimate(model models.Model, systemMessage string, messages []message.Message, baseTools []tools.BaseTool) int64 {
+	total := Count(model, systemMessage) + Tools(model, baseTools)
+	for _, msg := range messages {
+		total += Message(model, msg)
+	}
+
+	calibrationMu.Lock()
+	factor, ok := calibration[model.ID]
+	calibrationMu.Unlock()
+	if !ok {
+		return total
+	}
+	return int64(math.Ceil(float64(total) * factor))
+}
+
+// Calibrate adjusts future estimates for the model by comparing an estimate
+// with the input tokens the provider actually counted for the same request.
+func Calibrate(model models.Model, estimated, actual int64) {
+	if estimated <= 0 || actual <= 0 {
+		return
+	}
+	calibrationMu.Lock()
+	defer calibrationMu.Unlock()
+	factor, ok := calibration[model.ID]
+	if !ok {
+		factor = 1
+	}
+	// The estimate already had the old factor applied
+	observed := factor * float64(actual) / float64(estimated)
+	factor = factor*(1-calibrationWeight) + observed*calibrationWeight
+	calibration[model.ID] = min(max(factor, minCalibration), maxCalibration)
+}
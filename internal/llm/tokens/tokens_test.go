@@ -0,0 +1,71 @@
+package tokens
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/opencode-ai/opencode/internal/llm/models"
+	"github.com/opencode-ai/opencode/internal/message"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCount(t *testing.T) {
+	t.Run("uses tiktoken for OpenAI models", func(t *testing.T) {
+		model := models.Model{ID: "test.gpt", Provider: models.ProviderOpenAI, APIModel: "gpt-4o"}
+		assert.Equal(t, int64(2), Count(model, "hello world"))
+	})
+
+	t.Run("strips provider prefixes from model names", func(t *testing.T) {
+		model := models.Model{ID: "test.router", Provider: models.ProviderOpenRouter, APIModel: "openai/gpt-4.1"}
+		assert.Equal(t, int64(2), Count(model, "hello world"))
+	})
+
+	t.Run("falls back to characters per token", func(t *testing.T) {
+		claude := models.Model{ID: "test.claude", Provider: models.ProviderAnthropic, APIModel: "claude-sonnet-4"}
+		assert.Equal(t, int64(10), Count(claude, strings.Repeat("a", 35)))
+
+		gemini := models.Model{ID: "test.gemini", Provider: models.ProviderGemini, APIModel: "gemini-2.5-pro"}
+		assert.Equal(t, int64(10), Count(gemini, strings.Repeat("a", 40)))
+	})
+
+	t.Run("empty text has no tokens", func(t *testing.T) {
+		assert.Equal(t, int64(0), Count(models.Model{}, ""))
+	})
+}
+
+func TestEstimate(t *testing.T) {
+	model := models.Model{ID: "test.estimate", Provider: models.ProviderGemini}
+	msgs := []message.Message{
+		{Role: message.User, Parts: []message.ContentPart{
+			message.TextContent{Text: strings.Repeat("a", 400)},
+			message.BinaryContent{MIMEType: "image/png", Data: []byte("png")},
+		}},
+		{Role: message.Assistant, Parts: []message.ContentPart{
+			message.ToolCall{Name: "view", Input: strings.Repeat("b", 40)},
+		}},
+	}
+	// 100 for the text, one image, 1 for the name, 10 for the input and the
+	// per message overhead
+	expected := int64(100 + ImageTokens + 1 + 10 + 2*messageOverhead)
+	assert.Equal(t, expected, Estimate(model, "", msgs, nil))
+	assert.Equal(t, expected+25, Estimate(model, strings.Repeat("c", 100), msgs, nil))
+}
+
+func TestCalibrate(t *testing.T) {
+	model := models.Model{ID: "test.calibrate", Provider: models.ProviderGemini}
+	msgs := []message.Message{
+		{Role: message.User, Parts: []message.ContentPart{message.TextContent{Text: strings.Repeat("a", 3984)}}},
+	}
+	estimate := Estimate(model, "", msgs, nil)
+	assert.Equal(t, int64(1000), estimate)
+
+	// The provider counted twice as many tokens, estimates move towards that
+	for range 20 {
+		Calibrate(model, Estimate(model, "", msgs, nil), 2000)
+	}
+	assert.InDelta(t, 2000, Estimate(model, "", msgs, nil), 20)
+
+	// Bogus usage is ignored
+	Calibrate(model, 1000, 0)
+	assert.InDelta(t, 2000, Estimate(model, "", msgs, nil), 20)
+}
@@ -0,0 +1,130 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/question"
+)
+
+type AskUserParams struct {
+	Question string   `json:"question"`
+	Options  []string `json:"options,omitempty"`
+}
+
+type askUserTool struct {
+	questions question.Service
+}
+
+const (
+	AskUserToolName     = "ask_user"
+	maxAskUserOptions   = 9
+	defaultNoUserAnswer = "Nobody can answer questions in this session. Make the choice you think is best and mention it in your final answer."
+	askUserDescription  = `Asks the user a question and waits for the answer, without ending your turn.
+
+WHEN TO USE THIS TOOL:
+- Use when the request is ambiguous and the choice matters, like which of two conflicting behaviors to keep
+- Use when you need information only the user has, like the name of a deployment target or a credential location
+- Use before a step that is hard to undo when the request doesn't clearly ask for it
+
+HOW TO USE:
+- Ask one short, specific question
+- Optionally provide options for a multiple-choice question, up to 9. The user can still type their own answer
+- The answer is returned as the result of the tool
+
+LIMITATIONS:
+- In non-interactive mode nobody can answer, the tool returns a default answer instead
+- The user can dismiss the question, then continue with what you know or end your turn
+
+TIPS:
+- Don't ask about things you can find out yourself by reading the code or running a command
+- Don't ask for confirmation of routine steps, only ask when the answer changes what you do
+- Put the option you recommend first and say why in the question`
+)
+
+func NewAskUserTool(questions question.Service) BaseTool {
+	return &askUserTool{
+		questions: questions,
+	}
+}
+
+func askUserConfig() config.AskUserConfig {
+	cfg := config.Get()
+	if cfg == nil {
+		return config.AskUserConfig{}
+	}
+	return cfg.AskUser
+}
+
+func (a *askUserTool) Info() ToolInfo {
+	return ToolInfo{
+		Name:        AskUserToolName,
+		Description: askUserDescription,
+		Parameters: map[string]any{
+			"question": map[string]any{
+				"type":        "string",
+				"description": "The question to ask the user",
+			},
+			"options": map[string]any{
+				"type":        "array",
+				"description": "Optional answers to choose from, up to 9",
+				"items": map[string]any{
+					"type": "string",
+				},
+			},
+		},
+		Required: []string{"question"},
+	}
+}
+
+func (a *askUserTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error) {
+	var params AskUserParams
+	if err := json.Unmarshal([]byte(call.Input), &params); err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("error parsing parameters: %s", err)), nil
+	}
+	params.Question = strings.TrimSpace(params.Question)
+	if params.Question == "" {
+		return NewTextErrorResponse("question is required"), nil
+	}
+	var options []string
+	for _, option := range params.Options {
+		if option = strings.TrimSpace(option); option != "" {
+			options = append(options, option)
+		}
+	}
+	if len(options) > maxAskUserOptions {
+		return NewTextErrorResponse(fmt.Sprintf("too many options, give at most %d", maxAskUserOptions)), nil
+	}
+
+	sessionID, _ := GetContextValues(ctx)
+	if sessionID == "" {
+		return ToolResponse{}, fmt.Errorf("session ID is required for asking the user")
+	}
+
+	answer, err := a.questions.Ask(ctx, question.CreateQuestionRequest{
+		SessionID: sessionID,
+		Question:  params.Question,
+		Options:   options,
+	})
+	switch {
+	case errors.Is(err, question.ErrNoUser):
+		defaultAnswer := askUserConfig().DefaultAnswer
+		if defaultAnswer == "" {
+			defaultAnswer = defaultNoUserAnswer
+		}
+		return NewTextErrorResponse("The user can't answer in non-interactive mode. Default answer: " + defaultAnswer), nil
+	case errors.Is(err, question.ErrDismissed):
+		return NewTextErrorResponse("The user dismissed the question without answering. Don't ask it again, continue with what you know or end your turn."), nil
+	case err != nil:
+		return ToolResponse{}, err
+	}
+
+	if strings.TrimSpace(answer) == "" {
+		return NewTextResponse("The user answered with an empty reply"), nil
+	}
+	return NewTextResponse("The user answered: " + answer), nil
+}
@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/opencode-ai/opencode/internal/pubsub"
+	"github.com/opencode-ai/opencode/internal/question"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAskUserTool(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), SessionIDContextKey, "session"))
+	defer cancel()
+	questions := question.NewService()
+	events := questions.Subscribe(ctx)
+	tool := NewAskUserTool(questions)
+
+	// ask runs the tool and hands the question it asks to respond
+	ask := func(ctx context.Context, input string, respond func(question.Question)) ToolResponse {
+		t.Helper()
+		done := make(chan ToolResponse, 1)
+		go func() {
+			response, err := tool.Run(ctx, ToolCall{Name: AskUserToolName, Input: input})
+			assert.NoError(t, err)
+			done <- response
+		}()
+		select {
+		case event := <-events:
+			require.Equal(t, pubsub.CreatedEvent, event.Type)
+			respond(event.Payload)
+		case <-time.After(5 * time.Second):
+			t.Fatal("the tool did not ask the question")
+		}
+		return <-done
+	}
+
+	response := ask(ctx, `{"question": "Which database?", "options": ["sqlite", " ", "postgres"]}`, func(q question.Question) {
+		assert.Equal(t, "Which database?", q.Question)
+		assert.Equal(t, []string{"sqlite", "postgres"}, q.Options)
+		questions.Answer(q, "postgres")
+	})
+	assert.False(t, response.IsError)
+	assert.Equal(t, "The user answered: postgres", response.Content)
+
+	response = ask(ctx, `{"question": "Which port?"}`, func(q question.Question) {
+		questions.Dismiss(q)
+	})
+	assert.True(t, response.IsError)
+	assert.Contains(t, response.Content, "dismissed the question")
+
+	askCtx, cancelAsk := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		_, err := tool.Run(askCtx, ToolCall{Name: AskUserToolName, Input: `{"question": "Which port?"}`})
+		done <- err
+	}()
+	asked := <-events
+	cancelAsk()
+	assert.ErrorIs(t, <-done, context.Canceled)
+	withdrawn := <-events
+	assert.Equal(t, pubsub.DeletedEvent, withdrawn.Type)
+	assert.Equal(t, asked.Payload.ID, withdrawn.Payload.ID)
+
+	questions.NoUserSession("session")
+	response, err := tool.Run(ctx, ToolCall{Name: AskUserToolName, Input: `{"question": "Which port?"}`})
+	require.NoError(t, err)
+	assert.True(t, response.IsError)
+	assert.Equal(t, "The user can't answer in non-interactive mode. Default answer: "+defaultNoUserAnswer, response.Content)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -13,13 +14,26 @@ import (
 )
 
 type BashParams struct {
-	Command string `json:"command"`
-	Timeout int    `json:"timeout"`
+	Command    string `json:"command"`
+	Timeout    int    `json:"timeout"`
+	Background bool   `json:"background"`
+	Name       string `json:"name"`
 }
 
 type BashPermissionsParams struct {
-	Command string `json:"command"`
-	Timeout int    `json:"timeout"`
+	Command    string        `json:"command"`
+	Timeout    int           `json:"timeout"`
+	Background bool          `json:"background"`
+	Commands   []BashCommand `json:"commands,omitempty"`
+}
+
+// BashCommand is one of the commands a command line runs, as shown in the
+// permission dialog.
+type BashCommand struct {
+	Command  string `json:"command"`
+	ReadOnly bool   `json:"read_only"`
+	// The file written by a redirection
+	WritesTo string `json:"writes_to,omitempty"`
 }
 
 type BashResponseMetadata struct {
@@ -28,6 +42,7 @@ type BashResponseMetadata struct {
 }
 type bashTool struct {
 	permissions permission.Service
+	processes   shell.BackgroundService
 }
 
 const (
@@ -36,6 +51,19 @@ const (
 	DefaultTimeout  = 1 * 60 * 1000  // 1 minutes in milliseconds
 	MaxTimeout      = 10 * 60 * 1000 // 10 minutes in milliseconds
 	MaxOutputLength = 30000
+
+	// How long a background command is watched for early output or exit
+	backgroundStartWait = 2 * time.Second
+
+	bashSandboxDescription = `# Sandbox
+
+Commands run in a sandbox:
+- Only the working directory, the temporary directory and the paths configured by the user are writable, writes anywhere else fail
+- .git, .opencode.json and the .opencode directory are read-only, so git commands that write to the repository fail
+- The temporary directory is private to the command and empty when it starts
+- Network access may be blocked
+- Each command runs in a new sandboxed shell that inherits the current directory and exported variables, but 'cd' and variables set in a command don't carry over to the next one
+- When a command fails because of the sandbox, the result says so. Don't try to work around it, explain the problem to the user instead`
 )
 
 var bannedCommands = []string{
@@ -54,8 +82,70 @@ var safeReadOnlyCommands = []string{
 	"go version", "go help", "go list", "go env", "go doc", "go vet", "go fmt", "go mod", "go test", "go build", "go run", "go install", "go clean",
 }
 
+// deniedCommands are the built-in banned commands and the ones denied in
+// the config.
+func deniedCommands() []string {
+	denied := slices.Clone(bannedCommands)
+	if cfg := config.Get(); cfg != nil {
+		denied = append(denied, cfg.Shell.DenyCommands...)
+	}
+	return denied
+}
+
+func allowedCommands() []string {
+	allowed := slices.Clone(safeReadOnlyCommands)
+	if cfg := config.Get(); cfg != nil {
+		allowed = append(allowed, cfg.Shell.AllowCommands...)
+	}
+	return allowed
+}
+
+// matchesCommand reports whether a command starts with the words of entry,
+// e.g. "git status" matches "git status --short" but not "git stash".
+func matchesCommand(part shell.CommandPart, entry string) bool {
+	words := strings.Fields(entry)
+	if len(words) == 0 || len(part.Args) < len(words) {
+		return false
+	}
+	if !strings.EqualFold(part.Name(), words[0]) {
+		return false
+	}
+	return slices.Equal(part.Args[1:len(words)], words[1:])
+}
+
+// checkBashCommand parses a command line and checks each command it runs
+// against the deny and allow lists. Denied commands are an error, and a
+// command is only read-only when all its parts are allowed and it doesn't
+// write files.
+func checkBashCommand(command string) ([]BashCommand, error) {
+	parts, err := shell.ParseCommand(command)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the command: %w", err)
+	}
+	denied := deniedCommands()
+	allowed := allowedCommands()
+
+	commands := make([]BashCommand, 0, len(parts))
+	for _, part := range parts {
+		if part.WritesTo != "" {
+			commands = append(commands, BashCommand{Command: part.Text, WritesTo: part.WritesTo})
+			continue
+		}
+		for _, entry := range denied {
+			if matchesCommand(part, entry) {
+				return nil, fmt.Errorf("command '%s' is not allowed", part.Text)
+			}
+		}
+		readOnly := slices.ContainsFunc(allowed, func(entry string) bool {
+			return matchesCommand(part, entry)
+		})
+		commands = append(commands, BashCommand{Command: part.Text, ReadOnly: readOnly})
+	}
+	return commands, nil
+}
+
 func bashDescription() string {
-	bannedCommandsStr := strings.Join(bannedCommands, ", ")
+	bannedCommandsStr := strings.Join(deniedCommands(), ", ")
 	return fmt.Sprintf(`Executes a given bash command in a persistent shell session with optional timeout, ensuring proper handling and security measures.
 
 Before executing the command, please follow these steps:
@@ -73,7 +163,7 @@ Before executing the command, please follow these steps:
  - Capture the output of the command.
 
 4. Output Processing:
- - If the output exceeds %d characters, output will be truncated before being returned to you.
+ - If the output exceeds %d characters, only its start and end are returned to you. The full output is saved, read the rest with the read_output tool.
  - Prepare the output for display to the user.
 
 5. Return Result:
@@ -84,9 +174,11 @@ Usage notes:
 - The command argument is required.
 - You can specify an optional timeout in milliseconds (up to 600000ms / 10 minutes). If not specified, commands will timeout after 30 minutes.
 - VERY IMPORTANT: You MUST avoid using search commands like 'find' and 'grep'. Instead use Grep, Glob, or Agent tools to search. You MUST avoid read tools like 'cat', 'head', 'tail', and 'ls', and use FileRead and LS tools to read files.
+- Run go test, jest and pytest with the run_tests tool, which lists the failing tests instead of returning output that may be truncated.
 - When issuing multiple commands, use the ';' or '&&' operator to separate them. DO NOT use newlines (newlines are ok in quoted strings).
 - IMPORTANT: All commands share the same shell session. Shell state (environment variables, virtual environments, current directory, etc.) persist between commands. For example, if you set an environment variable as part of a command, the environment variable will persist for subsequent commands.
 - Try to maintain your current working directory throughout the session by using absolute paths and avoiding usage of 'cd'. You may use 'cd' if the User explicitly requests it.
+- Set background to true for commands that keep running, like dev servers, watchers or long builds, and give them a name. The tool returns right away with the first output, and you can then run other commands, e.g. curl the server. Use the process tool with the name to read new output, check if it is still running or stop it. Don't use '&' or nohup for this.
 <good-example>
 pytest /foo/bar/tests
 </good-example>
@@ -96,59 +188,7 @@ cd /foo/bar && pytest tests
 
 # Committing changes with git
 
-When the user asks you to create a new git commit, follow these steps carefully:
-
-1. Start with a single message that contains exactly three tool_use blocks that do the following (it is VERY IMPORTANT that you send these tool_use blocks in a single message, otherwise it will feel slow to the user!):
- - Run a git status command to see all untracked files.
- - Run a git diff command to see both staged and unstaged changes that will be committed.
- - Run a git log command to see recent commit messages, so that you can follow this repository's commit message style.
-
-2. Use the git context at the start of this conversation to determine which files are relevant to your commit. Add relevant untracked files to the staging area. Do not commit files that were already modified at the start of this conversation, if they are not relevant to your commit.
-
-3. Analyze all staged changes (both previously staged and newly added) and draft a commit message. Wrap your analysis process in <commit_analysis> tags:
-
-<commit_analysis>
-- List the files that have been changed or added
-- Summarize the nature of the changes (eg. new feature, enhancement to an existing feature, bug fix, refactoring, test, docs, etc.)
-- Brainstorm the purpose or motivation behind these changes
-- Do not use tools to explore code, beyond what is available in the git context
-- Assess the impact of these changes on the overall project
-- Check for any sensitive information that shouldn't be committed
-- Draft a concise (1-2 sentences) commit message that focuses on the "why" rather than the "what"
-- Ensure your language is clear, concise, and to the point
-- Ensure the message accurately reflects the changes and their purpose (i.e. "add" means a wholly new feature, "update" means an enhancement to an existing feature, "fix" means a bug fix, etc.)
-- Ensure the message is not generic (avoid words like "Update" or "Fix" without context)
-- Review the draft message to ensure it accurately reflects the changes and their purpose
-</commit_analysis>
-
-4. Create the commit with a message ending with:
-🤖 Generated with opencode
-Co-Authored-By: opencode <noreply@opencode.ai>
-
-- In order to ensure good formatting, ALWAYS pass the commit message via a HEREDOC, a la this example:
-<example>
-git commit -m "$(cat <<'EOF'
- Commit message here.
-
- 🤖 Generated with opencode
- Co-Authored-By: opencode <noreply@opencode.ai>
- EOF
- )"
-</example>
-
-5. If the commit fails due to pre-commit hook changes, retry the commit ONCE to include these automated changes. If it fails again, it usually means a pre-commit hook is preventing the commit. If the commit succeeds but you notice that files were modified by the pre-commit hook, you MUST amend your commit to include them.
-
-6. Finally, run git status to make sure the commit succeeded.
-
-Important notes:
-- When possible, combine the "git add" and "git commit" commands into a single "git commit -am" command, to speed things up
-- However, be careful not to stage files (e.g. with 'git add .') for commits that aren't part of the change, they may have untracked files they want to keep around, but not commit.
-- NEVER update the git config
-- DO NOT push to the remote repository
-- IMPORTANT: Never use git commands with the -i flag (like git rebase -i or git add -i) since they require interactive input which is not supported.
-- If there are no changes to commit (i.e., no untracked files and no modifications), do not create an empty commit
-- Ensure your commit message is meaningful and concise. It should explain the purpose of the changes, not just describe them.
-- Return an empty response - the user will see the git output directly
+Use the git tool for status, diffs, history, staging and commits instead of running git here. Its description explains how to create a commit.
 
 # Creating pull requests
 Use the gh command via the Bash tool for ALL GitHub-related tasks including working with issues, pull requests, checks, and releases. If given a Github URL use the gh command to get the information needed.
@@ -156,14 +196,13 @@ Use the gh command via the Bash tool for ALL GitHub-related tasks including work
 IMPORTANT: When the user asks you to create a pull request, follow these steps carefully:
 
 1. Understand the current state of the branch. Remember to send a single message that contains multiple tool_use blocks (it is VERY IMPORTANT that you do this in a single message, otherwise it will feel slow to the user!):
- - Run a git status command to see all untracked files.
- - Run a git diff command to see both staged and unstaged changes that will be committed.
- - Check if the current branch tracks a remote branch and is up to date with the remote, so you know if you need to push to the remote
- - Run a git log command and 'git diff main...HEAD' to understand the full commit history for the current branch (from the time it diverged from the 'main' branch.)
+ - Use the git tool's status operation to see all untracked files and whether the branch is ahead of its remote, so you know if you need to push to the remote
+ - Use the git tool's diff operation to see both staged and unstaged changes that will be committed.
+ - Use the git tool's log operation and its diff operation with ref "main...HEAD" to understand the full commit history for the current branch (from the time it diverged from the 'main' branch.)
 
 2. Create new branch if needed
 
-3. Commit changes if needed
+3. Commit changes with the git tool if needed
 
 4. Push to remote with -u flag if needed
 
@@ -203,16 +242,21 @@ Important:
 - Never update git config`, bannedCommandsStr, MaxOutputLength)
 }
 
-func NewBashTool(permission permission.Service) BaseTool {
+func NewBashTool(permission permission.Service, processes shell.BackgroundService) BaseTool {
 	return &bashTool{
 		permissions: permission,
+		processes:   processes,
 	}
 }
 
 func (b *bashTool) Info() ToolInfo {
+	description := bashDescription()
+	if shell.SandboxEnabled() {
+		description += "\n\n" + bashSandboxDescription
+	}
 	return ToolInfo{
 		Name:        BashToolName,
-		Description: bashDescription(),
+		Description: description,
 		Parameters: map[string]any{
 			"command": map[string]any{
 				"type":        "string",
@@ -222,11 +266,28 @@ func (b *bashTool) Info() ToolInfo {
 				"type":        "number",
 				"description": "Optional timeout in milliseconds (max 600000)",
 			},
+			"background": map[string]any{
+				"type":        "boolean",
+				"description": "Run the command in the background and return right away",
+			},
+			"name": map[string]any{
+				"type":        "string",
+				"description": "The name of the background process, used with the process tool (defaults to the name of the command it runs, like npm in \"cd web && npm run dev\")",
+			},
 		},
 		Required: []string{"command"},
 	}
 }
 
+// allReadOnly reports whether the parsed commands can run without a
+// permission prompt. A command that parses to nothing, like a comment, is
+// not known to be safe.
+func allReadOnly(commands []BashCommand) bool {
+	return len(commands) > 0 && !slices.ContainsFunc(commands, func(command BashCommand) bool {
+		return !command.ReadOnly
+	})
+}
+
 func (b *bashTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error) {
 	var params BashParams
 	if err := json.Unmarshal([]byte(call.Input), &params); err != nil {
@@ -239,34 +300,24 @@ func (b *bashTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error)
 		params.Timeout = DefaultTimeout
 	}
 
-	if params.Command == "" {
+	if strings.TrimSpace(params.Command) == "" {
 		return NewTextErrorResponse("missing command"), nil
 	}
 
-	baseCmd := strings.Fields(params.Command)[0]
-	for _, banned := range bannedCommands {
-		if strings.EqualFold(baseCmd, banned) {
-			return NewTextErrorResponse(fmt.Sprintf("command '%s' is not allowed", baseCmd)), nil
-		}
-	}
-
-	isSafeReadOnly := false
-	cmdLower := strings.ToLower(params.Command)
-
-	for _, safe := range safeReadOnlyCommands {
-		if strings.HasPrefix(cmdLower, strings.ToLower(safe)) {
-			if len(cmdLower) == len(safe) || cmdLower[len(safe)] == ' ' || cmdLower[len(safe)] == '-' {
-				isSafeReadOnly = true
-				break
-			}
-		}
+	commands, err := checkBashCommand(params.Command)
+	if err != nil {
+		return NewTextErrorResponse(err.Error()), nil
 	}
+	isSafeReadOnly := allReadOnly(commands)
 
 	sessionID, messageID := GetContextValues(ctx)
 	if sessionID == "" || messageID == "" {
 		return ToolResponse{}, fmt.Errorf("session ID and message ID are required for creating a new file")
 	}
-	if !isSafeReadOnly {
+	if err := shell.CheckSandbox(); err != nil {
+		return NewTextErrorResponse(err.Error()), nil
+	}
+	if !isSafeReadOnly && !shell.SandboxAutoApprove() {
 		p := b.permissions.Request(
 			permission.CreatePermissionRequest{
 				SessionID:   sessionID,
@@ -275,7 +326,9 @@ func (b *bashTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error)
 				Action:      "execute",
 				Description: fmt.Sprintf("Execute command: %s", params.Command),
 				Params: BashPermissionsParams{
-					Command: params.Command,
+					Command:    params.Command,
+					Background: params.Background,
+					Commands:   commands,
 				},
 			},
 		)
@@ -283,15 +336,19 @@ func (b *bashTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error)
 			return ToolResponse{}, permission.ErrorPermissionDenied
 		}
 	}
+	if params.Background {
+		return b.runInBackground(ctx, sessionID, params)
+	}
+
 	startTime := time.Now()
-	shell := shell.GetPersistentShell(config.WorkingDirectory())
-	stdout, stderr, exitCode, interrupted, err := shell.Exec(ctx, params.Command, params.Timeout)
+	persistentShell := shell.GetPersistentShell(config.WorkingDirectory())
+	stdout, stderr, exitCode, interrupted, err := persistentShell.Exec(ctx, params.Command, params.Timeout)
 	if err != nil {
 		return ToolResponse{}, fmt.Errorf("error executing command: %w", err)
 	}
 
-	stdout = truncateOutput(stdout)
-	stderr = truncateOutput(stderr)
+	stdout = SpillOutput(ctx, BashToolName, stdout)
+	stderr = SpillOutput(ctx, BashToolName+"_stderr", stderr)
 
 	errorMessage := stderr
 	if interrupted {
@@ -306,6 +363,11 @@ func (b *bashTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error)
 		errorMessage += fmt.Sprintf("Exit code %d", exitCode)
 	}
 
+	violation := ""
+	if exitCode != 0 {
+		violation = shell.SandboxViolation(stderr)
+	}
+
 	hasBothOutputs := stdout != "" && stderr != ""
 
 	if hasBothOutputs {
@@ -320,12 +382,61 @@ func (b *bashTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error)
 		StartTime: startTime.UnixMilli(),
 		EndTime:   time.Now().UnixMilli(),
 	}
+	if violation != "" {
+		return WithResponseMetadata(NewTextErrorResponse(stdout+"\n\n"+violation), metadata), nil
+	}
 	if stdout == "" {
 		return WithResponseMetadata(NewTextResponse("no output"), metadata), nil
 	}
 	return WithResponseMetadata(NewTextResponse(stdout), metadata), nil
 }
 
+// runInBackground starts the command as a background process of the session
+// and returns its output from the first moments, so commands that fail right
+// away are noticed.
+func (b *bashTool) runInBackground(ctx context.Context, sessionID string, params BashParams) (ToolResponse, error) {
+	if b.processes == nil {
+		return NewTextErrorResponse("background processes are not available"), nil
+	}
+	name := strings.TrimSpace(params.Name)
+	if name == "" {
+		name = shell.MainCommand(params.Command)
+	}
+	if name == "" {
+		return NewTextErrorResponse("name is required for this background command"), nil
+	}
+
+	startTime := time.Now()
+	process, err := b.processes.Start(sessionID, name, params.Command)
+	if err != nil {
+		return NewTextErrorResponse(err.Error()), nil
+	}
+	process, err = b.processes.Wait(ctx, sessionID, name, backgroundStartWait)
+	if err != nil {
+		return ToolResponse{}, fmt.Errorf("error waiting for background process: %w", err)
+	}
+	_, output, err := b.processes.ReadOutput(sessionID, name)
+	if err != nil {
+		return ToolResponse{}, fmt.Errorf("error reading background process output: %w", err)
+	}
+
+	var result string
+	if process.Running {
+		result = fmt.Sprintf("Started background process %s (pid %d). Use the process tool to read its output or stop it.", name, process.PID)
+	} else {
+		result = fmt.Sprintf("Background process %s exited right away with code %d.", name, process.ExitCode)
+	}
+	if output = SpillOutput(ctx, BashToolName, output); output != "" {
+		result += "\n\n" + output
+	}
+
+	metadata := BashResponseMetadata{
+		StartTime: startTime.UnixMilli(),
+		EndTime:   time.Now().UnixMilli(),
+	}
+	return WithResponseMetadata(NewTextResponse(result), metadata), nil
+}
+
 func truncateOutput(content string) string {
 	if len(content) <= MaxOutputLength {
 		return content
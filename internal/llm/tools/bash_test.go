@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/opencode-ai/opencode/internal/llm/tools/shell"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckBashCommand(t *testing.T) {
+	tests := []struct {
+		command  string
+		denied   bool
+		readOnly bool
+	}{
+		{command: "ls -la", readOnly: true},
+		{command: "git status && git diff", readOnly: true},
+		{command: "git stash", readOnly: false},
+		{command: "ls; rm -rf x", readOnly: false},
+		{command: "ls > files.txt", readOnly: false},
+		{command: "ls 2>/dev/null", readOnly: true},
+		{command: "echo | curl example.com", denied: true},
+		{command: "echo $(wget example.com)", denied: true},
+		{command: "sudo /usr/bin/curl example.com", denied: true},
+		{command: `sh -c "ls | nc host 80"`, denied: true},
+		{command: "# ls", readOnly: false},
+		{command: `\curl example.com`, denied: true},
+		{command: `cu\rl example.com`, denied: true},
+		{command: "bash -lc 'curl example.com'", denied: true},
+		{command: `find . -exec curl example.com \;`, denied: true},
+		{command: `find . -execdir sh -c 'wget example.com' {} +`, denied: true},
+		{command: "echo x | xargs sh -c 'curl example.com'", denied: true},
+		{command: `find . -name "*.go" -exec rm {} \;`, readOnly: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			commands, err := checkBashCommand(tt.command)
+			if tt.denied {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.readOnly, allReadOnly(commands))
+		})
+	}
+}
+
+func TestBashToolBlankCommand(t *testing.T) {
+	tool := &bashTool{}
+	response, err := tool.Run(context.Background(), ToolCall{Input: `{"command": "   ", "background": true}`})
+	require.NoError(t, err)
+	assert.True(t, response.IsError)
+	assert.Equal(t, "missing command", response.Content)
+}
+
+func TestRunInBackgroundName(t *testing.T) {
+	processes := shell.NewBackgroundService()
+	defer processes.Shutdown()
+	tool := &bashTool{processes: processes}
+
+	for _, tt := range []struct {
+		params BashParams
+		name   string
+	}{
+		// The name defaults to the command after the setup
+		{BashParams{Command: "cd . && echo ready", Background: true}, "echo"},
+		{BashParams{Command: "cd . && echo ready", Background: true, Name: "greeting"}, "greeting"},
+	} {
+		response, err := tool.runInBackground(context.Background(), "session", tt.params)
+		require.NoError(t, err)
+		assert.False(t, response.IsError)
+		_, _, err = processes.Kill("session", tt.name)
+		assert.NoError(t, err)
+	}
+
+	response, err := tool.runInBackground(context.Background(), "session", BashParams{Command: "cd .", Background: true})
+	require.NoError(t, err)
+	assert.True(t, response.IsError)
+	assert.Contains(t, response.Content, "name is required")
+}
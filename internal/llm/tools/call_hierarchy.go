@@ -0,0 +1,179 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/opencode-ai/opencode/internal/lsp"
+	"github.com/opencode-ai/opencode/internal/lsp/protocol"
+)
+
+type CallHierarchyParams struct {
+	NavigationParams
+	Direction string `json:"direction"`
+}
+
+type callHierarchyTool struct {
+	lspClients map[string]*lsp.Client
+}
+
+const (
+	CallHierarchyToolName    = "call_hierarchy"
+	callHierarchyDescription = `Shows the callers of a function, or the functions it calls, using the language servers.
+
+WHEN TO USE THIS TOOL:
+- Use to find which functions call a function before changing its behavior
+- Use to see what a function depends on without reading all of it
+
+HOW TO USE:
+- Pass location as file:line:column of the function name, or
+- Pass symbol with its name (e.g. OpenFile or Client.OpenFile) to search the workspace
+- Set direction to "incoming" (callers, the default), "outgoing" (callees) or "both"
+
+FEATURES:
+- Incoming calls list each call site as file:line:column with the calling function
+- Outgoing calls list each call site with where the called function is defined
+
+LIMITATIONS:
+- Only works with language servers that support call hierarchy
+- Only shows one level, call the tool again on a result to go further
+- Calls through interfaces or function values may be missing`
+)
+
+func NewCallHierarchyTool(lspClients map[string]*lsp.Client) BaseTool {
+	return &callHierarchyTool{
+		lspClients,
+	}
+}
+
+func (c *callHierarchyTool) Info() ToolInfo {
+	parameters := map[string]any{
+		"direction": map[string]any{
+			"type":        "string",
+			"description": "Which calls to show: incoming (callers), outgoing (callees) or both. Defaults to incoming",
+			"enum":        []string{"incoming", "outgoing", "both"},
+		},
+	}
+	for name, parameter := range navigationParameters {
+		parameters[name] = parameter
+	}
+	return ToolInfo{
+		Name:        CallHierarchyToolName,
+		Description: callHierarchyDescription,
+		Parameters:  parameters,
+		Required:    []string{},
+	}
+}
+
+func (c *callHierarchyTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error) {
+	var params CallHierarchyParams
+	if err := json.Unmarshal([]byte(call.Input), &params); err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("error parsing parameters: %s", err)), nil
+	}
+	if len(c.lspClients) == 0 {
+		return NewTextErrorResponse("no LSP clients available"), nil
+	}
+	switch params.Direction {
+	case "":
+		params.Direction = "incoming"
+	case "incoming", "outgoing", "both":
+	default:
+		return NewTextErrorResponse(fmt.Sprintf("invalid direction %q, expected incoming, outgoing or both", params.Direction)), nil
+	}
+
+	target, others, err := resolveNavigationTarget(ctx, c.lspClients, params.NavigationParams)
+	if err != nil {
+		return NewTextErrorResponse(err.Error()), nil
+	}
+
+	// The items have to go back to the server that prepared them
+	var client *lsp.Client
+	items, err := queryClients(c.lspClients, func(candidate *lsp.Client) ([]protocol.CallHierarchyItem, error) {
+		client = candidate
+		return candidate.PrepareCallHierarchy(ctx, protocol.CallHierarchyPrepareParams{
+			TextDocumentPositionParams: target.textDocumentPosition(),
+		})
+	})
+	if err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("error preparing call hierarchy: %s", err)), nil
+	}
+	lines := sourceLines{}
+	if len(items) == 0 {
+		return NewTextResponse(fmt.Sprintf("No function found at %s", lines.format(target.path, target.position))), nil
+	}
+
+	var sections []string
+	for _, item := range items {
+		header := fmt.Sprintf("%s at %s", describeCallItem(item), lines.format(item.URI.Path(), item.SelectionRange.Start))
+		if params.Direction != "outgoing" {
+			calls, err := client.IncomingCalls(ctx, protocol.CallHierarchyIncomingCallsParams{Item: item})
+			if err != nil {
+				return NewTextErrorResponse(fmt.Sprintf("error getting incoming calls: %s", err)), nil
+			}
+			sections = append(sections, formatIncomingCalls(header, calls, lines))
+		}
+		if params.Direction != "incoming" {
+			calls, err := client.OutgoingCalls(ctx, protocol.CallHierarchyOutgoingCallsParams{Item: item})
+			if err != nil {
+				return NewTextErrorResponse(fmt.Sprintf("error getting outgoing calls: %s", err)), nil
+			}
+			sections = append(sections, formatOutgoingCalls(header, item, calls, lines))
+		}
+	}
+	return NewTextResponse(strings.Join(sections, "\n\n") + otherMatchesNote(target, others)), nil
+}
+
+func formatIncomingCalls(header string, calls []protocol.CallHierarchyIncomingCall, lines sourceLines) string {
+	if len(calls) == 0 {
+		return "No callers of " + header
+	}
+	output := []string{"Callers of " + header}
+	count := 0
+	for _, call := range calls {
+		// The ranges are in the file of the caller
+		for _, rng := range call.FromRanges {
+			count++
+			if count > maxNavigationResults {
+				continue
+			}
+			output = append(output, fmt.Sprintf("  %s (in %s)", lines.format(call.From.URI.Path(), rng.Start), call.From.Name))
+		}
+	}
+	if count > maxNavigationResults {
+		output = append(output, fmt.Sprintf("  ... and %d more", count-maxNavigationResults))
+	}
+	return strings.Join(output, "\n")
+}
+
+func formatOutgoingCalls(header string, item protocol.CallHierarchyItem, calls []protocol.CallHierarchyOutgoingCall, lines sourceLines) string {
+	if len(calls) == 0 {
+		return "No calls from " + header
+	}
+	output := []string{"Calls from " + header}
+	count := 0
+	for _, call := range calls {
+		definition := fmt.Sprintf("%s:%d", relativePath(call.To.URI.Path()), call.To.SelectionRange.Start.Line+1)
+		// The ranges are in the file of the item the calls are made from
+		for _, rng := range call.FromRanges {
+			count++
+			if count > maxNavigationResults {
+				continue
+			}
+			output = append(output, fmt.Sprintf("  %s -> %s (%s)", lines.format(item.URI.Path(), rng.Start), call.To.Name, definition))
+		}
+	}
+	if count > maxNavigationResults {
+		output = append(output, fmt.Sprintf("  ... and %d more", count-maxNavigationResults))
+	}
+	return strings.Join(output, "\n")
+}
+
+func describeCallItem(item protocol.CallHierarchyItem) string {
+	description := describeSymbol(symbolMatch{name: item.Name, kind: item.Kind})
+	if item.Detail != "" {
+		description += " (" + item.Detail + ")"
+	}
+	return description
+}
@@ -0,0 +1,427 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/history"
+	"github.com/opencode-ai/opencode/internal/logging"
+	"github.com/opencode-ai/opencode/internal/lsp"
+	"github.com/opencode-ai/opencode/internal/lsp/protocol"
+	"github.com/opencode-ai/opencode/internal/lsp/util"
+	"github.com/opencode-ai/opencode/internal/permission"
+)
+
+type CodeActionParams struct {
+	Location string `json:"location"`
+	EndLine  int    `json:"end_line"`
+	Kind     string `json:"kind"`
+	Action   string `json:"action"`
+}
+
+type CodeActionCommandPermissionsParams struct {
+	Title     string            `json:"title"`
+	Command   string            `json:"command"`
+	Arguments []json.RawMessage `json:"arguments,omitempty"`
+}
+
+type codeActionTool struct {
+	workspaceEditApplier
+}
+
+// availableCodeAction is a code action or a bare command offered by a server.
+type availableCodeAction struct {
+	client *lsp.Client
+	action protocol.CodeAction
+}
+
+const (
+	CodeActionToolName    = "code_action"
+	maxCommandResultLen   = 2000
+	codeActionDescription = `Lists and applies the code actions a language server offers for a line or range, such as quick fixes for diagnostics, organizing imports or refactorings.
+
+WHEN TO USE THIS TOOL:
+- Use when diagnostics report something the server can fix, like a missing import, an unused variable or a missing method
+- Use for source actions like organizing imports, or refactorings like extracting a function
+
+HOW TO USE:
+- First call it with location (file:line or file:line:column) to list the available actions, optionally with end_line for a range
+- Optionally set kind to only get some actions, e.g. quickfix, refactor, source.organizeImports
+- Then call it again with the same location and action set to the number or the title of the action to apply
+
+FEATURES:
+- Diagnostics on the selected lines are sent along, so quick fixes for them are included
+- Edits are shown for approval and recorded in the file history like other edits
+- Actions that run a server command ask for permission before the command runs
+
+LIMITATIONS:
+- Only works for languages with a configured language server
+- The actions offered depend on the language server
+
+TIPS:
+- Check the diagnostics in the result to make sure the fix worked`
+)
+
+func NewCodeActionTool(lspClients map[string]*lsp.Client, permissions permission.Service, files history.Service) BaseTool {
+	return &codeActionTool{
+		workspaceEditApplier{
+			lspClients:  lspClients,
+			permissions: permissions,
+			files:       files,
+		},
+	}
+}
+
+func (c *codeActionTool) Info() ToolInfo {
+	return ToolInfo{
+		Name:        CodeActionToolName,
+		Description: codeActionDescription,
+		Parameters: map[string]any{
+			"location": map[string]any{
+				"type":        "string",
+				"description": "The line to get actions for as file:line, or a position as file:line:column (1-based)",
+			},
+			"end_line": map[string]any{
+				"type":        "number",
+				"description": "The last line of the range to get actions for (optional)",
+			},
+			"kind": map[string]any{
+				"type":        "string",
+				"description": "Only return actions of this kind, e.g. quickfix, refactor, source.organizeImports (optional)",
+			},
+			"action": map[string]any{
+				"type":        "string",
+				"description": "The number or the title of the action to apply, as listed by a previous call. Leave empty to list the actions",
+			},
+		},
+		Required: []string{"location"},
+	}
+}
+
+func (c *codeActionTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error) {
+	var params CodeActionParams
+	if err := json.Unmarshal([]byte(call.Input), &params); err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("error parsing parameters: %s", err)), nil
+	}
+	if len(c.lspClients) == 0 {
+		return NewTextErrorResponse("no LSP clients available"), nil
+	}
+
+	path, rng, err := codeActionRange(params)
+	if err != nil {
+		return NewTextErrorResponse(err.Error()), nil
+	}
+	notifyLspOpenFile(ctx, path, c.lspClients)
+
+	actions, err := c.codeActions(ctx, path, rng, params.Kind)
+	if err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("error getting code actions: %s", err)), nil
+	}
+	if len(actions) == 0 {
+		return NewTextResponse("No code actions available"), nil
+	}
+
+	if params.Action == "" {
+		return NewTextResponse(formatCodeActions(actions)), nil
+	}
+
+	selected, ok := selectCodeAction(actions, params.Action)
+	if !ok {
+		return NewTextErrorResponse(fmt.Sprintf("no action %q, the available actions are:\n%s", params.Action, formatCodeActions(actions))), nil
+	}
+	return c.applyCodeAction(ctx, path, selected)
+}
+
+// codeActionRange is the whole of the selected lines, or the position when
+// a column is given.
+func codeActionRange(params CodeActionParams) (string, protocol.Range, error) {
+	if params.Location == "" {
+		return "", protocol.Range{}, errors.New("location is required")
+	}
+	path, line, column, err := parseLocation(params.Location)
+	if err != nil {
+		return "", protocol.Range{}, err
+	}
+	lines, err := readSourceLines(path)
+	if err != nil {
+		return "", protocol.Range{}, err
+	}
+	endLine := line
+	if params.EndLine > 0 {
+		endLine = params.EndLine
+	}
+	if endLine < line {
+		return "", protocol.Range{}, fmt.Errorf("end_line %d is before line %d", endLine, line)
+	}
+	if endLine > len(lines) {
+		return "", protocol.Range{}, fmt.Errorf("%s has only %d lines", relativePath(path), len(lines))
+	}
+
+	start := protocol.Position{Line: uint32(line - 1)}
+	if column > 0 {
+		start.Character = utf16Column(lines[line-1], column-1)
+	}
+	end := start
+	if column == 0 || params.EndLine > 0 {
+		text := lines[endLine-1]
+		end = protocol.Position{Line: uint32(endLine - 1), Character: utf16Column(text, len([]rune(text)))}
+	}
+	return path, protocol.Range{Start: start, End: end}, nil
+}
+
+func (c *codeActionTool) codeActions(ctx context.Context, path string, rng protocol.Range, kind string) ([]availableCodeAction, error) {
+	uri := fileURI(path)
+	var only []protocol.CodeActionKind
+	if kind != "" {
+		only = []protocol.CodeActionKind{protocol.CodeActionKind(kind)}
+	}
+
+	// Quick fixes need the diagnostics, which come in after the file is opened
+	for _, client := range c.lspClients {
+		if len(client.GetFileDiagnostics(uri)) == 0 {
+			waitForLspDiagnostics(ctx, path, c.lspClients)
+			break
+		}
+	}
+
+	var actions []availableCodeAction
+	var errs []error
+	for _, name := range sortedClientNames(c.lspClients) {
+		client := c.lspClients[name]
+		diagnostics := []protocol.Diagnostic{}
+		for _, diagnostic := range client.GetFileDiagnostics(uri) {
+			if rangesIntersect(diagnostic.Range, rng) {
+				diagnostics = append(diagnostics, diagnostic)
+			}
+		}
+
+		result, err := client.CodeAction(ctx, protocol.CodeActionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+			Range:        rng,
+			Context: protocol.CodeActionContext{
+				Diagnostics: diagnostics,
+				Only:        only,
+			},
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		for _, item := range result {
+			switch value := item.Value.(type) {
+			case protocol.CodeAction:
+				if value.Disabled != nil {
+					continue
+				}
+				actions = append(actions, availableCodeAction{client: client, action: value})
+			case protocol.Command:
+				command := value
+				actions = append(actions, availableCodeAction{
+					client: client,
+					action: protocol.CodeAction{Title: value.Title, Command: &command},
+				})
+			}
+		}
+	}
+	if len(actions) == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return actions, nil
+}
+
+func formatCodeActions(actions []availableCodeAction) string {
+	lines := []string{"Available code actions, call the tool again with action set to the number or title to apply one:"}
+	for i, available := range actions {
+		action := available.action
+		line := fmt.Sprintf("%d. %s", i+1, action.Title)
+		if action.Kind != "" {
+			line += fmt.Sprintf(" [%s]", action.Kind)
+		}
+		if action.IsPreferred {
+			line += " (preferred)"
+		}
+		lines = append(lines, line)
+		for _, diagnostic := range action.Diagnostics {
+			lines = append(lines, fmt.Sprintf("   fixes line %d: %s", diagnostic.Range.Start.Line+1, diagnostic.Message))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func selectCodeAction(actions []availableCodeAction, choice string) (availableCodeAction, bool) {
+	choice = strings.TrimSpace(choice)
+	if n, err := strconv.Atoi(strings.TrimSuffix(choice, ".")); err == nil {
+		if n >= 1 && n <= len(actions) {
+			return actions[n-1], true
+		}
+		return availableCodeAction{}, false
+	}
+	for _, available := range actions {
+		if strings.EqualFold(available.action.Title, choice) {
+			return available, true
+		}
+	}
+	return availableCodeAction{}, false
+}
+
+func (c *codeActionTool) applyCodeAction(ctx context.Context, path string, selected availableCodeAction) (ToolResponse, error) {
+	action := selected.action
+	client := selected.client
+
+	// Servers can leave the edit out of the list and fill it in on resolve
+	if action.Edit == nil && (action.Data != nil || action.Command == nil) {
+		resolved, err := client.ResolveCodeAction(ctx, action)
+		if err != nil {
+			logging.Debug("Error resolving code action", "title", action.Title, "error", err)
+		} else {
+			action = resolved
+		}
+	}
+	if action.Edit == nil && action.Command == nil {
+		return NewTextErrorResponse(fmt.Sprintf("the action %q has no edit or command to apply", action.Title)), nil
+	}
+
+	var metadata WorkspaceEditResponseMetadata
+	if action.Edit != nil {
+		changes, err := util.PreviewWorkspaceEdit(*action.Edit)
+		if err != nil {
+			return NewTextErrorResponse(fmt.Sprintf("error preparing the edit: %s", err)), nil
+		}
+		if len(changes) > 0 {
+			applied, err := c.apply(ctx, CodeActionToolName, fmt.Sprintf("Apply code action: %s", action.Title), changes)
+			if err != nil {
+				return ToolResponse{}, err
+			}
+			metadata = mergeEditMetadata(metadata, applied)
+		}
+	}
+
+	var commandResult any
+	if action.Command != nil {
+		applied, result, err := c.executeCommand(ctx, client, *action.Command)
+		if err != nil {
+			if errors.Is(err, permission.ErrorPermissionDenied) {
+				return ToolResponse{}, err
+			}
+			return NewTextErrorResponse(fmt.Sprintf("error running the command of %q: %s", action.Title, err)), nil
+		}
+		metadata = mergeEditMetadata(metadata, applied)
+		commandResult = result
+	}
+
+	output := fmt.Sprintf("Applied %q.", action.Title)
+	if len(metadata.Files) > 0 {
+		output += " " + summarizeFileDiffs(metadata)
+	} else {
+		output += " No files were changed."
+	}
+	if commandResult != nil {
+		if data, err := json.Marshal(commandResult); err == nil && string(data) != "null" {
+			text := string(data)
+			if len(text) > maxCommandResultLen {
+				text = text[:maxCommandResultLen] + "..."
+			}
+			output += "\nCommand result: " + text
+		}
+	}
+
+	waitForLspDiagnostics(ctx, path, c.lspClients)
+	if diagnostics := getDiagnostics(path, c.lspClients); diagnostics != "" {
+		output += "\n" + diagnostics
+	}
+	return WithResponseMetadata(NewTextResponse(output), metadata), nil
+}
+
+// executeCommand runs a server command after asking for permission. Edits the
+// server sends while the command runs go through the same permission and
+// history tracking as code action edits.
+func (c *codeActionTool) executeCommand(ctx context.Context, client *lsp.Client, command protocol.Command) (WorkspaceEditResponseMetadata, any, error) {
+	sessionID, _ := GetContextValues(ctx)
+	p := c.permissions.Request(
+		permission.CreatePermissionRequest{
+			SessionID:   sessionID,
+			Path:        config.WorkingDirectory(),
+			ToolName:    CodeActionToolName,
+			Action:      "execute",
+			Description: fmt.Sprintf("Run the language server command `%s` for %q", command.Command, command.Title),
+			Params: CodeActionCommandPermissionsParams{
+				Title:     command.Title,
+				Command:   command.Command,
+				Arguments: command.Arguments,
+			},
+		},
+	)
+	if !p {
+		return WorkspaceEditResponseMetadata{}, nil, permission.ErrorPermissionDenied
+	}
+
+	// The handler runs apart from the tool, while the server waits for it
+	var mu sync.Mutex
+	var metadata WorkspaceEditResponseMetadata
+	var applyErr error
+	handler := func(raw json.RawMessage) (any, error) {
+		var params protocol.ApplyWorkspaceEditParams
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return nil, err
+		}
+		changes, err := util.PreviewWorkspaceEdit(params.Edit)
+		if err == nil && len(changes) > 0 {
+			description := "Apply the edit of a language server command"
+			if params.Label != "" {
+				description = fmt.Sprintf("Apply the edit of a language server command: %s", params.Label)
+			}
+			var applied WorkspaceEditResponseMetadata
+			applied, err = c.apply(ctx, CodeActionToolName, description, changes)
+			mu.Lock()
+			metadata = mergeEditMetadata(metadata, applied)
+			mu.Unlock()
+		}
+		if err != nil {
+			mu.Lock()
+			applyErr = err
+			mu.Unlock()
+			return protocol.ApplyWorkspaceEditResult{Applied: false, FailureReason: err.Error()}, nil
+		}
+		return protocol.ApplyWorkspaceEditResult{Applied: true}, nil
+	}
+
+	var result any
+	err := client.WithApplyEditHandler(handler, func() error {
+		var err error
+		result, err = client.ExecuteCommand(ctx, protocol.ExecuteCommandParams{
+			Command:   command.Command,
+			Arguments: command.Arguments,
+		})
+		return err
+	})
+	mu.Lock()
+	defer mu.Unlock()
+	if errors.Is(applyErr, permission.ErrorPermissionDenied) {
+		return metadata, nil, applyErr
+	}
+	if err != nil {
+		return metadata, nil, err
+	}
+	return metadata, result, nil
+}
+
+func mergeEditMetadata(a, b WorkspaceEditResponseMetadata) WorkspaceEditResponseMetadata {
+	return WorkspaceEditResponseMetadata{
+		Files:     append(a.Files, b.Files...),
+		Additions: a.Additions + b.Additions,
+		Removals:  a.Removals + b.Removals,
+	}
+}
+
+func rangesIntersect(a, b protocol.Range) bool {
+	before := func(x, y protocol.Position) bool {
+		return x.Line < y.Line || (x.Line == y.Line && x.Character < y.Character)
+	}
+	return !before(a.End, b.Start) && !before(b.End, a.Start)
+}
@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencode-ai/opencode/internal/lsp/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeActionRange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	require.NoError(t, os.WriteFile(path, []byte("package main\n\nfunc main() {\n\tx := \"é\"\n}\n"), 0o644))
+
+	tests := []struct {
+		params CodeActionParams
+		want   protocol.Range
+		err    string
+	}{
+		{
+			// The whole line
+			params: CodeActionParams{Location: path + ":4"},
+			want:   protocol.Range{Start: protocol.Position{Line: 3}, End: protocol.Position{Line: 3, Character: 9}},
+		},
+		{
+			// Only the position
+			params: CodeActionParams{Location: path + ":4:2"},
+			want:   protocol.Range{Start: protocol.Position{Line: 3, Character: 1}, End: protocol.Position{Line: 3, Character: 1}},
+		},
+		{
+			// From the position to the end of end_line
+			params: CodeActionParams{Location: path + ":3:6", EndLine: 5},
+			want:   protocol.Range{Start: protocol.Position{Line: 2, Character: 5}, End: protocol.Position{Line: 4, Character: 1}},
+		},
+		{params: CodeActionParams{}, err: "location is required"},
+		{params: CodeActionParams{Location: path + ":4", EndLine: 2}, err: "end_line 2 is before line 4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.params.Location, func(t *testing.T) {
+			gotPath, got, err := codeActionRange(tt.params)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, path, gotPath)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSelectCodeAction(t *testing.T) {
+	actions := []availableCodeAction{
+		{action: protocol.CodeAction{Title: "Organize imports", Kind: "source.organizeImports"}},
+		{action: protocol.CodeAction{
+			Title:       "Remove unused variable",
+			Kind:        "quickfix",
+			IsPreferred: true,
+			Diagnostics: []protocol.Diagnostic{{Range: protocol.Range{Start: protocol.Position{Line: 3}}, Message: "x declared and not used"}},
+		}},
+	}
+
+	for choice, want := range map[string]string{
+		"1":                       "Organize imports",
+		"2.":                      "Remove unused variable",
+		" remove unused variable": "Remove unused variable",
+	} {
+		selected, ok := selectCodeAction(actions, choice)
+		require.True(t, ok, choice)
+		assert.Equal(t, want, selected.action.Title)
+	}
+	for _, choice := range []string{"0", "3", "Extract function"} {
+		_, ok := selectCodeAction(actions, choice)
+		assert.False(t, ok, choice)
+	}
+
+	assert.Equal(t, `Available code actions, call the tool again with action set to the number or title to apply one:
+1. Organize imports [source.organizeImports]
+2. Remove unused variable [quickfix] (preferred)
+   fixes line 4: x declared and not used`, formatCodeActions(actions))
+}
+
+func TestRangesIntersect(t *testing.T) {
+	at := func(line, character uint32) protocol.Position {
+		return protocol.Position{Line: line, Character: character}
+	}
+	selection := protocol.Range{Start: at(3, 0), End: at(3, 10)}
+
+	assert.True(t, rangesIntersect(protocol.Range{Start: at(3, 4), End: at(3, 5)}, selection))
+	assert.True(t, rangesIntersect(protocol.Range{Start: at(1, 0), End: at(3, 0)}, selection))
+	assert.True(t, rangesIntersect(protocol.Range{Start: at(3, 10), End: at(4, 0)}, selection))
+	assert.False(t, rangesIntersect(protocol.Range{Start: at(2, 0), End: at(2, 8)}, selection))
+	assert.False(t, rangesIntersect(protocol.Range{Start: at(3, 11), End: at(3, 12)}, selection))
+}
+
+func TestMergeEditMetadata(t *testing.T) {
+	merged := mergeEditMetadata(
+		WorkspaceEditResponseMetadata{Files: []FileDiff{{}}, Additions: 2, Removals: 1},
+		WorkspaceEditResponseMetadata{Files: []FileDiff{{}, {}}, Additions: 3},
+	)
+	assert.Len(t, merged.Files, 3)
+	assert.Equal(t, 5, merged.Additions)
+	assert.Equal(t, 1, merged.Removals)
+}
+
+func TestCodeActionWithoutLSP(t *testing.T) {
+	tool := NewCodeActionTool(nil, nil, nil)
+	response, err := tool.Run(context.Background(), ToolCall{Input: `{"location": "main.go:1"}`})
+	require.NoError(t, err)
+	assert.True(t, response.IsError)
+	assert.Equal(t, "no LSP clients available", response.Content)
+}
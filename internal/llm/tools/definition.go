@@ -0,0 +1,123 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/opencode-ai/opencode/internal/lsp"
+	"github.com/opencode-ai/opencode/internal/lsp/protocol"
+)
+
+type definitionTool struct {
+	lspClients map[string]*lsp.Client
+}
+
+const (
+	DefinitionToolName    = "definition"
+	definitionDescription = `Finds where a symbol is defined using the language servers.
+
+WHEN TO USE THIS TOOL:
+- Use when you need to find the declaration of a function, type, variable or field
+- Prefer it over grepping for an identifier, it follows imports and ignores unrelated matches
+
+HOW TO USE:
+- Pass location as file:line:column of a use of the symbol, or
+- Pass symbol with its name (e.g. OpenFile or Client.OpenFile) to search the workspace
+- Pass both location (file:line) and symbol to look the name up on that line
+
+FEATURES:
+- Returns file:line:column with the source line of each definition
+- Lists every symbol with the name when searching by name
+
+LIMITATIONS:
+- Only works for languages with a configured language server
+- Results depend on the language server, some don't index files that were never opened
+
+TIPS:
+- Use the view tool with the returned line as offset to read the definition
+- Use the references tool to find where the symbol is used`
+)
+
+func NewDefinitionTool(lspClients map[string]*lsp.Client) BaseTool {
+	return &definitionTool{
+		lspClients,
+	}
+}
+
+func (d *definitionTool) Info() ToolInfo {
+	return ToolInfo{
+		Name:        DefinitionToolName,
+		Description: definitionDescription,
+		Parameters:  navigationParameters,
+		Required:    []string{},
+	}
+}
+
+func (d *definitionTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error) {
+	var params NavigationParams
+	if err := json.Unmarshal([]byte(call.Input), &params); err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("error parsing parameters: %s", err)), nil
+	}
+	if len(d.lspClients) == 0 {
+		return NewTextErrorResponse("no LSP clients available"), nil
+	}
+
+	// The workspace search already returns the definitions
+	if params.Location == "" && params.Symbol != "" {
+		matches, err := findSymbols(ctx, d.lspClients, params.Symbol)
+		if err != nil {
+			return NewTextErrorResponse(err.Error()), nil
+		}
+		if len(matches) == 0 {
+			return NewTextErrorResponse(fmt.Sprintf("no symbol named %s found in the workspace, use the symbols tool to search for similar names", params.Symbol)), nil
+		}
+		locations := make([]protocol.Location, len(matches))
+		for i, match := range matches {
+			locations[i] = protocol.Location{URI: fileURI(match.path), Range: match.rng}
+		}
+		return NewTextResponse(formatLocations(locations)), nil
+	}
+
+	target, _, err := resolveNavigationTarget(ctx, d.lspClients, params)
+	if err != nil {
+		return NewTextErrorResponse(err.Error()), nil
+	}
+
+	locations, err := queryClients(d.lspClients, func(client *lsp.Client) ([]protocol.Location, error) {
+		result, err := client.Definition(ctx, protocol.DefinitionParams{
+			TextDocumentPositionParams: target.textDocumentPosition(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return definitionLocations(result), nil
+	})
+	if err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("error finding definition: %s", err)), nil
+	}
+	if len(locations) == 0 {
+		lines := sourceLines{}
+		return NewTextResponse(fmt.Sprintf("No definition found at %s", lines.format(target.path, target.position))), nil
+	}
+	return NewTextResponse(formatLocations(locations)), nil
+}
+
+func definitionLocations(result protocol.Or_Result_textDocument_definition) []protocol.Location {
+	switch value := result.Value.(type) {
+	case protocol.Definition:
+		switch definition := value.Value.(type) {
+		case protocol.Location:
+			return []protocol.Location{definition}
+		case []protocol.Location:
+			return definition
+		}
+	case []protocol.DefinitionLink:
+		locations := make([]protocol.Location, len(value))
+		for i, link := range value {
+			locations[i] = protocol.Location{URI: link.TargetURI, Range: link.TargetSelectionRange}
+		}
+		return locations
+	}
+	return nil
+}
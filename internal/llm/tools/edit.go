@@ -72,6 +72,7 @@ CRITICAL REQUIREMENTS FOR USING THIS TOOL:
 2. SINGLE INSTANCE: This tool can only change ONE instance at a time. If you need to change multiple instances:
    - Make separate calls to this tool for each instance
    - Each call must uniquely identify its specific instance using extensive context
+   - Or use the multi_edit tool to make all the changes to one file in a single call
 
 3. VERIFICATION: Before using this tool:
    - Check how many instances of the target text exist in the file
@@ -223,6 +224,12 @@ func (e *editTool) createNewFile(ctx context.Context, filePath, content string)
 		return ToolResponse{}, fmt.Errorf("failed to write file: %w", err)
 	}
 
+	formatted := formatOnWrite(ctx, filePath, "", content, e.lspClients)
+	if formatted.note != "" {
+		content = formatted.content
+		diff, additions, removals = formatted.diff, formatted.additions, formatted.removals
+	}
+
 	// File can't be in the history so we create a new file history
 	_, err = e.files.Create(ctx, sessionID, filePath, "")
 	if err != nil {
@@ -241,7 +248,7 @@ func (e *editTool) createNewFile(ctx context.Context, filePath, content string)
 	recordFileRead(filePath)
 
 	return WithResponseMetadata(
-		NewTextResponse("File created: "+filePath),
+		NewTextResponse(formatted.withNote("File created: "+filePath)),
 		EditResponseMetadata{
 			Diff:      diff,
 			Additions: additions,
@@ -334,6 +341,12 @@ func (e *editTool) deleteContent(ctx context.Context, filePath, oldString string
 		return ToolResponse{}, fmt.Errorf("failed to write file: %w", err)
 	}
 
+	formatted := formatOnWrite(ctx, filePath, oldContent, newContent, e.lspClients)
+	if formatted.note != "" {
+		newContent = formatted.content
+		diff, additions, removals = formatted.diff, formatted.additions, formatted.removals
+	}
+
 	// Check if file exists in history
 	file, err := e.files.GetByPathAndSession(ctx, filePath, sessionID)
 	if err != nil {
@@ -351,7 +364,7 @@ func (e *editTool) deleteContent(ctx context.Context, filePath, oldString string
 		}
 	}
 	// Store the new version
-	_, err = e.files.CreateVersion(ctx, sessionID, filePath, "")
+	_, err = e.files.CreateVersion(ctx, sessionID, filePath, newContent)
 	if err != nil {
 		logging.Debug("Error creating file history version", "error", err)
 	}
@@ -360,7 +373,7 @@ func (e *editTool) deleteContent(ctx context.Context, filePath, oldString string
 	recordFileRead(filePath)
 
 	return WithResponseMetadata(
-		NewTextResponse("Content deleted from file: "+filePath),
+		NewTextResponse(formatted.withNote("Content deleted from file: "+filePath)),
 		EditResponseMetadata{
 			Diff:      diff,
 			Additions: additions,
@@ -454,6 +467,12 @@ func (e *editTool) replaceContent(ctx context.Context, filePath, oldString, newS
 		return ToolResponse{}, fmt.Errorf("failed to write file: %w", err)
 	}
 
+	formatted := formatOnWrite(ctx, filePath, oldContent, newContent, e.lspClients)
+	if formatted.note != "" {
+		newContent = formatted.content
+		diff, additions, removals = formatted.diff, formatted.additions, formatted.removals
+	}
+
 	// Check if file exists in history
 	file, err := e.files.GetByPathAndSession(ctx, filePath, sessionID)
 	if err != nil {
@@ -480,7 +499,7 @@ func (e *editTool) replaceContent(ctx context.Context, filePath, oldString, newS
 	recordFileRead(filePath)
 
 	return WithResponseMetadata(
-		NewTextResponse("Content replaced in file: "+filePath),
+		NewTextResponse(formatted.withNote("Content replaced in file: "+filePath)),
 		EditResponseMetadata{
 			Diff:      diff,
 			Additions: additions,
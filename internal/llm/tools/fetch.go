@@ -3,15 +3,22 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
 	"strings"
 	"time"
 
 	md "github.com/JohannesKaufmann/html-to-markdown"
 	"github.com/PuerkitoBio/goquery"
 	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/httpclient"
+	"github.com/opencode-ai/opencode/internal/logging"
 	"github.com/opencode-ai/opencode/internal/permission"
 )
 
@@ -27,6 +34,9 @@ type FetchPermissionsParams struct {
 	Timeout int    `json:"timeout,omitempty"`
 }
 
+// Responses are cut at this size when no limit is configured
+const defaultFetchMaxSize = 5 * 1024 * 1024
+
 type fetchTool struct {
 	client      *http.Client
 	permissions permission.Service
@@ -51,9 +61,11 @@ FEATURES:
 - Automatically handles HTTP redirects
 - Sets reasonable timeouts to prevent hanging
 - Validates input parameters before making requests
+- Responses may be cached, unchanged pages are not downloaded again
 
 LIMITATIONS:
-- Maximum response size is 5MB
+- Responses are cut at 5MB, or refused above a configured limit
+- Some domains or content types may be blocked by the configuration
 - Only supports HTTP and HTTPS protocols
 - Cannot handle authentication or cookies
 - Some websites may block automated requests
@@ -67,9 +79,7 @@ TIPS:
 
 func NewFetchTool(permissions permission.Service) BaseTool {
 	return &fetchTool{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		client:      httpclient.New(httpclient.Timeout(30 * time.Second)),
 		permissions: permissions,
 	}
 }
@@ -115,36 +125,72 @@ func (t *fetchTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error
 	if !strings.HasPrefix(params.URL, "http://") && !strings.HasPrefix(params.URL, "https://") {
 		return NewTextErrorResponse("URL must start with http:// or https://"), nil
 	}
+	parsedURL, err := url.Parse(params.URL)
+	if err != nil || parsedURL.Hostname() == "" {
+		return NewTextErrorResponse("Invalid URL: " + params.URL), nil
+	}
+
+	fetchCfg := fetchConfig()
+	if err := checkFetchDomain(parsedURL.Hostname(), fetchCfg, false); err != nil {
+		return NewTextErrorResponse(err.Error()), nil
+	}
 
 	sessionID, messageID := GetContextValues(ctx)
 	if sessionID == "" || messageID == "" {
 		return ToolResponse{}, fmt.Errorf("session ID and message ID are required for creating a new file")
 	}
 
-	p := t.permissions.Request(
-		permission.CreatePermissionRequest{
-			SessionID:   sessionID,
-			Path:        config.WorkingDirectory(),
-			ToolName:    FetchToolName,
-			Action:      "fetch",
-			Description: fmt.Sprintf("Fetch content from URL: %s", params.URL),
-			Params:      FetchPermissionsParams(params),
-		},
-	)
+	// These domains are fetched without asking, as long as redirects stay on
+	// them. They don't restrict what can be fetched once the user approves.
+	autoApproved := domainMatches(parsedURL.Hostname(), fetchCfg.AutoApproveDomains)
+	if !autoApproved {
+		p := t.permissions.Request(
+			permission.CreatePermissionRequest{
+				SessionID:   sessionID,
+				Path:        config.WorkingDirectory(),
+				ToolName:    FetchToolName,
+				Action:      "fetch",
+				Description: fmt.Sprintf("Fetch content from URL: %s", params.URL),
+				Params:      FetchPermissionsParams(params),
+			},
+		)
+
+		if !p {
+			return ToolResponse{}, permission.ErrorPermissionDenied
+		}
+	}
+
+	return t.fetch(ctx, params, format, fetchCfg, newFetchCache(fetchCfg), autoApproved)
+}
 
-	if !p {
-		return ToolResponse{}, permission.ErrorPermissionDenied
+// fetch gets the URL, from the cache when it is enabled and the page is cached
+// and unchanged.
+func (t *fetchTool) fetch(ctx context.Context, params FetchParams, format string, fetchCfg config.FetchConfig, cache *fetchCache, autoApproved bool) (ToolResponse, error) {
+	cached, isCached := fetchCacheEntry{}, false
+	if cache != nil {
+		cached, isCached = cache.get(params.URL, format)
+		if isCached && cache.fresh(cached) {
+			return NewTextResponse(cached.Content), nil
+		}
 	}
 
-	client := t.client
+	timeout := t.client.Timeout
 	if params.Timeout > 0 {
 		maxTimeout := 120 // 2 minutes
 		if params.Timeout > maxTimeout {
 			params.Timeout = maxTimeout
 		}
-		client = &http.Client{
-			Timeout: time.Duration(params.Timeout) * time.Second,
-		}
+		timeout = time.Duration(params.Timeout) * time.Second
+	}
+	client := &http.Client{
+		Transport: t.client.Transport,
+		Timeout:   timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return checkFetchDomain(req.URL.Hostname(), fetchCfg, autoApproved)
+		},
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "GET", params.URL, nil)
@@ -153,54 +199,192 @@ func (t *fetchTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error
 	}
 
 	req.Header.Set("User-Agent", "opencode/1.0")
+	if isCached {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
+		var policyErr fetchPolicyError
+		if errors.As(err, &policyErr) {
+			return NewTextErrorResponse(policyErr.Error()), nil
+		}
 		return ToolResponse{}, fmt.Errorf("failed to fetch URL: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && isCached {
+		cached.FetchedAt = time.Now().Unix()
+		if err := cache.put(cached); err != nil {
+			logging.Debug("Error updating fetch cache", "url", params.URL, "error", err)
+		}
+		return NewTextResponse(cached.Content), nil
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return NewTextErrorResponse(fmt.Sprintf("Request failed with status code: %d", resp.StatusCode)), nil
 	}
 
-	maxSize := int64(5 * 1024 * 1024) // 5MB
-	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSize))
+	contentType := resp.Header.Get("Content-Type")
+	if !contentTypeAllowed(contentType, fetchCfg.ContentTypes) {
+		return NewTextErrorResponse(fmt.Sprintf("Content type %q is not allowed by the fetch configuration, allowed types: %s", contentType, strings.Join(fetchCfg.ContentTypes, ", "))), nil
+	}
+
+	// A configured limit refuses larger responses, without one they are cut
+	maxSize, truncate := fetchCfg.MaxSize, fetchCfg.MaxSize <= 0
+	if truncate {
+		maxSize = defaultFetchMaxSize
+	}
+	if !truncate && resp.ContentLength > maxSize {
+		return NewTextErrorResponse(fmt.Sprintf("Response is %d bytes, larger than the limit of %d bytes", resp.ContentLength, maxSize)), nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
 	if err != nil {
 		return NewTextErrorResponse("Failed to read response body: " + err.Error()), nil
 	}
+	truncated := int64(len(body)) > maxSize
+	if truncated && !truncate {
+		return NewTextErrorResponse(fmt.Sprintf("Response is larger than the limit of %d bytes", maxSize)), nil
+	}
+	if truncated {
+		body = body[:maxSize]
+	}
 
-	content := string(body)
-	contentType := resp.Header.Get("Content-Type")
+	content, err := formatFetchedContent(string(body), contentType, format)
+	if err != nil {
+		return NewTextErrorResponse(err.Error()), nil
+	}
+	if truncated {
+		content += fmt.Sprintf("\n\n(Response cut at %d bytes)", maxSize)
+	}
 
+	if cache != nil && !strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+		entry := fetchCacheEntry{
+			URL:          params.URL,
+			Format:       format,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Content:      content,
+			FetchedAt:    time.Now().Unix(),
+		}
+		if err := cache.put(entry); err != nil {
+			logging.Debug("Error writing fetch cache", "url", params.URL, "error", err)
+		}
+	}
+	return NewTextResponse(content), nil
+}
+
+// formatFetchedContent converts a response body to the requested format.
+func formatFetchedContent(content, contentType, format string) (string, error) {
 	switch format {
 	case "text":
 		if strings.Contains(contentType, "text/html") {
 			text, err := extractTextFromHTML(content)
 			if err != nil {
-				return NewTextErrorResponse("Failed to extract text from HTML: " + err.Error()), nil
+				return "", fmt.Errorf("Failed to extract text from HTML: %w", err)
 			}
-			return NewTextResponse(text), nil
+			return text, nil
 		}
-		return NewTextResponse(content), nil
+		return content, nil
 
 	case "markdown":
 		if strings.Contains(contentType, "text/html") {
 			markdown, err := convertHTMLToMarkdown(content)
 			if err != nil {
-				return NewTextErrorResponse("Failed to convert HTML to Markdown: " + err.Error()), nil
+				return "", fmt.Errorf("Failed to convert HTML to Markdown: %w", err)
 			}
-			return NewTextResponse(markdown), nil
+			return markdown, nil
 		}
 
-		return NewTextResponse("```\n" + content + "\n```"), nil
-
-	case "html":
-		return NewTextResponse(content), nil
+		return "```\n" + content + "\n```", nil
 
 	default:
-		return NewTextResponse(content), nil
+		return content, nil
+	}
+}
+
+// fetchPolicyError is a URL refused by the fetch configuration.
+type fetchPolicyError struct {
+	msg string
+}
+
+func (e fetchPolicyError) Error() string {
+	return e.msg
+}
+
+func fetchConfig() config.FetchConfig {
+	cfg := config.Get()
+	if cfg == nil {
+		return config.FetchConfig{}
+	}
+	return cfg.Fetch
+}
+
+// newFetchCache returns the configured cache, or nil when it is disabled.
+func newFetchCache(fetchCfg config.FetchConfig) *fetchCache {
+	cfg := config.Get()
+	if cfg == nil || !fetchCfg.Cache.Enabled {
+		return nil
+	}
+	ttl := defaultFetchCacheTTL
+	if fetchCfg.Cache.TTL > 0 {
+		ttl = time.Duration(fetchCfg.Cache.TTL) * time.Second
+	}
+	return &fetchCache{dir: filepath.Join(cfg.Data.Directory, "fetch"), ttl: ttl}
+}
+
+// checkFetchDomain refuses denied domains and, when autoApprovedOnly is set,
+// domains that aren't auto-approved.
+func checkFetchDomain(host string, fetchCfg config.FetchConfig, autoApprovedOnly bool) error {
+	if domainMatches(host, fetchCfg.DenyDomains) {
+		return fetchPolicyError{fmt.Sprintf("Fetching from %s is denied by the fetch configuration", host)}
+	}
+	if autoApprovedOnly && !domainMatches(host, fetchCfg.AutoApproveDomains) {
+		return fetchPolicyError{fmt.Sprintf("Redirected to %s, which is not an auto-approved domain. Fetch that URL directly to ask the user for permission", host)}
+	}
+	return nil
+}
+
+// domainMatches reports whether host is one of the domains or a subdomain of
+// one. Domains can also be patterns like "*.github.io".
+func domainMatches(host string, domains []string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "."))
+		if strings.ContainsAny(domain, "*?[") {
+			if matched, _ := path.Match(domain, host); matched {
+				return true
+			}
+			continue
+		}
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// contentTypeAllowed matches the media type of a response against patterns
+// like "text/*". Every type is allowed when there are no patterns.
+func contentTypeAllowed(contentType string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(strings.ToLower(strings.TrimSpace(pattern)), mediaType); matched {
+			return true
+		}
 	}
+	return false
 }
 
 func extractTextFromHTML(html string) (string, error) {
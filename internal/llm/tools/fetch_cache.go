@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// Responses are used without revalidating them for this long by default
+	defaultFetchCacheTTL = 15 * time.Minute
+	// The least recently written entries are removed above this many
+	maxFetchCacheEntries = 500
+)
+
+// fetchCacheEntry is a fetched response converted to the requested format.
+// The URL and time are kept so the cache shows what was fetched and when.
+type fetchCacheEntry struct {
+	URL          string `json:"url"`
+	Format       string `json:"format"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Content      string `json:"content"`
+	FetchedAt    int64  `json:"fetchedAt"`
+}
+
+// fetchCache stores responses as JSON files keyed by URL and format.
+type fetchCache struct {
+	dir        string
+	ttl        time.Duration
+	maxEntries int // maxFetchCacheEntries when not set
+}
+
+func (c fetchCache) path(url, format string) string {
+	sum := sha256.Sum256([]byte(format + " " + url))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c fetchCache) get(url, format string) (fetchCacheEntry, bool) {
+	data, err := os.ReadFile(c.path(url, format))
+	if err != nil {
+		return fetchCacheEntry{}, false
+	}
+	var entry fetchCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != url || entry.Format != format {
+		return fetchCacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (c fetchCache) put(entry fetchCacheEntry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.path(entry.URL, entry.Format), data, 0o644); err != nil {
+		return err
+	}
+	return c.evict()
+}
+
+// evict removes the least recently written entries above the entry limit.
+// Revalidated entries are written again, so pages in use stay cached.
+func (c fetchCache) evict() error {
+	maxEntries := c.maxEntries
+	if maxEntries <= 0 {
+		maxEntries = maxFetchCacheEntries
+	}
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	type cacheFile struct {
+		path    string
+		modTime time.Time
+	}
+	var files []cacheFile
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() || !strings.HasSuffix(dirEntry.Name(), ".json") {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, cacheFile{filepath.Join(c.dir, dirEntry.Name()), info.ModTime()})
+	}
+	if len(files) <= maxEntries {
+		return nil
+	}
+	slices.SortFunc(files, func(a, b cacheFile) int {
+		return a.modTime.Compare(b.modTime)
+	})
+	for _, file := range files[:len(files)-maxEntries] {
+		if err := os.Remove(file.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// fresh reports whether an entry can be used without asking the server.
+func (c fetchCache) fresh(entry fetchCacheEntry) bool {
+	return time.Since(time.Unix(entry.FetchedAt, 0)) < c.ttl
+}
@@ -0,0 +1,179 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDomainMatches(t *testing.T) {
+	domains := []string{"go.dev", "*.github.io", ".example.com"}
+
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"go.dev", true},
+		{"pkg.go.dev", true},
+		{"GO.DEV", true},
+		{"notgo.dev", false},
+		{"user.github.io", true},
+		{"github.io", false},
+		{"docs.example.com", true},
+		{"example.org", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			assert.Equal(t, tt.want, domainMatches(tt.host, domains))
+		})
+	}
+}
+
+func TestCheckFetchDomain(t *testing.T) {
+	cfg := config.FetchConfig{
+		AutoApproveDomains: []string{"go.dev"},
+		DenyDomains:        []string{"evil.go.dev"},
+	}
+
+	assert.NoError(t, checkFetchDomain("pkg.go.dev", cfg, true))
+	assert.NoError(t, checkFetchDomain("example.com", cfg, false))
+	assert.Error(t, checkFetchDomain("example.com", cfg, true))
+	assert.Error(t, checkFetchDomain("evil.go.dev", cfg, false))
+}
+
+func TestContentTypeAllowed(t *testing.T) {
+	patterns := []string{"text/*", "application/json"}
+
+	assert.True(t, contentTypeAllowed("text/html; charset=utf-8", patterns))
+	assert.True(t, contentTypeAllowed("application/json", patterns))
+	assert.False(t, contentTypeAllowed("application/octet-stream", patterns))
+	assert.False(t, contentTypeAllowed("", patterns))
+	assert.True(t, contentTypeAllowed("image/png", nil))
+}
+
+func TestFetchCache(t *testing.T) {
+	cache := fetchCache{dir: t.TempDir(), ttl: time.Minute}
+
+	_, ok := cache.get("https://go.dev/doc", "markdown")
+	assert.False(t, ok)
+
+	entry := fetchCacheEntry{
+		URL:       "https://go.dev/doc",
+		Format:    "markdown",
+		ETag:      `"v1"`,
+		Content:   "# Docs",
+		FetchedAt: time.Now().Unix(),
+	}
+	require.NoError(t, cache.put(entry))
+
+	got, ok := cache.get("https://go.dev/doc", "markdown")
+	require.True(t, ok)
+	assert.Equal(t, entry, got)
+	assert.True(t, cache.fresh(got))
+
+	// Entries are kept per format
+	_, ok = cache.get("https://go.dev/doc", "text")
+	assert.False(t, ok)
+
+	got.FetchedAt = time.Now().Add(-2 * time.Minute).Unix()
+	assert.False(t, cache.fresh(got))
+}
+
+func TestFetchCacheEvict(t *testing.T) {
+	cache := fetchCache{dir: t.TempDir(), ttl: time.Minute, maxEntries: 2}
+
+	for i, url := range []string{"https://go.dev/a", "https://go.dev/b", "https://go.dev/c"} {
+		require.NoError(t, cache.put(fetchCacheEntry{URL: url, Format: "text", Content: url}))
+		// The oldest entry is written first
+		written := time.Now().Add(time.Duration(i-3) * time.Hour)
+		require.NoError(t, os.Chtimes(cache.path(url, "text"), written, written))
+	}
+
+	_, ok := cache.get("https://go.dev/a", "text")
+	assert.False(t, ok)
+	_, ok = cache.get("https://go.dev/b", "text")
+	assert.True(t, ok)
+	_, ok = cache.get("https://go.dev/c", "text")
+	assert.True(t, ok)
+}
+
+func runFetch(t *testing.T, url string, fetchCfg config.FetchConfig, cache *fetchCache) ToolResponse {
+	t.Helper()
+	tool := NewFetchTool(nil).(*fetchTool)
+	response, err := tool.fetch(context.Background(), FetchParams{URL: url, Format: "text"}, "text", fetchCfg, cache, false)
+	require.NoError(t, err)
+	return response
+}
+
+func TestFetchRevalidatesCachedResponses(t *testing.T) {
+	var statuses []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			statuses = append(statuses, http.StatusNotModified)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		statuses = append(statuses, http.StatusOK)
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	// Entries are never fresh without a TTL, so every fetch revalidates
+	cache := &fetchCache{dir: t.TempDir()}
+	for range 2 {
+		response := runFetch(t, server.URL, config.FetchConfig{}, cache)
+		assert.False(t, response.IsError)
+		assert.Equal(t, "hello", response.Content)
+	}
+	assert.Equal(t, []int{http.StatusOK, http.StatusNotModified}, statuses)
+
+	// Fresh entries are used without asking the server
+	cache.ttl = time.Minute
+	response := runFetch(t, server.URL, config.FetchConfig{}, cache)
+	assert.Equal(t, "hello", response.Content)
+	assert.Len(t, statuses, 2)
+}
+
+func TestFetchContentTypes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write([]byte{0, 1, 2})
+	}))
+	defer server.Close()
+
+	response := runFetch(t, server.URL, config.FetchConfig{ContentTypes: []string{"text/*"}}, nil)
+	assert.True(t, response.IsError)
+	assert.Contains(t, response.Content, `"application/octet-stream" is not allowed`)
+
+	response = runFetch(t, server.URL, config.FetchConfig{}, nil)
+	assert.False(t, response.IsError)
+}
+
+func TestFetchMaxSize(t *testing.T) {
+	size := defaultFetchMaxSize + 10
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(strings.Repeat("a", size)))
+	}))
+	defer server.Close()
+
+	// Without a limit, responses are cut
+	response := runFetch(t, server.URL, config.FetchConfig{}, nil)
+	assert.False(t, response.IsError)
+	assert.True(t, strings.HasPrefix(response.Content, strings.Repeat("a", defaultFetchMaxSize)+"\n\n(Response cut at"))
+
+	// A configured limit refuses them
+	response = runFetch(t, server.URL, config.FetchConfig{MaxSize: int64(size - 1)}, nil)
+	assert.True(t, response.IsError)
+	assert.Contains(t, response.Content, "larger than the limit")
+}
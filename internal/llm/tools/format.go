@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/diff"
+	"github.com/opencode-ai/opencode/internal/logging"
+	"github.com/opencode-ai/opencode/internal/lsp"
+	"github.com/opencode-ai/opencode/internal/lsp/protocol"
+	"github.com/opencode-ai/opencode/internal/lsp/util"
+)
+
+// Longer formatting diffs are left out of the tool result
+const maxFormatDiffLines = 40
+
+// formatResult is a file after format-on-write. The note and the diff from
+// the old content are only set when formatting changed the file.
+type formatResult struct {
+	content   string
+	note      string
+	diff      string
+	additions int
+	removals  int
+}
+
+// formatOnWrite formats a file the agent just wrote with the language server
+// configured to format its extension, and writes the result back. Formatting
+// errors are logged and leave the file as written.
+func formatOnWrite(ctx context.Context, filePath, oldContent, content string, lsps map[string]*lsp.Client) formatResult {
+	result := formatResult{content: content}
+	cfg := config.Get()
+	if cfg == nil || len(lsps) == 0 {
+		return result
+	}
+	ext := strings.ToLower(filepath.Ext(filePath))
+	for _, name := range sortedClientNames(lsps) {
+		if !slices.Contains(cfg.LSP[name].FormatOnWrite, ext) {
+			continue
+		}
+		formatted, err := formatWithClient(ctx, lsps[name], filePath, content)
+		if err != nil {
+			logging.Debug("Error formatting file", "file", filePath, "server", name, "error", err)
+			return result
+		}
+		if formatted == content {
+			return result
+		}
+		if err := os.WriteFile(filePath, []byte(formatted), 0o644); err != nil {
+			logging.Debug("Error writing formatted file", "file", filePath, "error", err)
+			return result
+		}
+		if err := lsps[name].NotifyChange(ctx, filePath); err != nil {
+			logging.Debug("Error notifying LSP of file change", "file", filePath, "error", err)
+		}
+
+		result.content = formatted
+		result.note = formatNote(name, filePath, content, formatted)
+		result.diff, result.additions, result.removals = diff.GenerateDiff(oldContent, formatted, filePath)
+		return result
+	}
+	return result
+}
+
+func formatWithClient(ctx context.Context, client *lsp.Client, filePath, content string) (string, error) {
+	// The server must see the content that was just written
+	if client.IsFileOpen(filePath) {
+		if err := client.NotifyChange(ctx, filePath); err != nil {
+			return "", err
+		}
+	} else if err := client.OpenFile(ctx, filePath); err != nil {
+		return "", err
+	}
+
+	edits, err := client.Formatting(ctx, protocol.DocumentFormattingParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: fileURI(filePath)},
+		Options: protocol.FormattingOptions{
+			TabSize:                4,
+			InsertSpaces:           !strings.Contains(content, "\n\t"),
+			TrimTrailingWhitespace: true,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(edits) == 0 {
+		return content, nil
+	}
+	return util.ApplyTextEditsToContent(content, edits)
+}
+
+// formatNote tells the model how formatting changed what it wrote, so its
+// idea of the file stays accurate for later edits.
+func formatNote(server, filePath, written, formatted string) string {
+	formatDiff, additions, removals := diff.GenerateDiff(written, formatted, filePath)
+	note := fmt.Sprintf("The file was formatted by the %s language server after writing (+%d -%d lines).", server, additions, removals)
+	lines := strings.Split(strings.TrimRight(formatDiff, "\n"), "\n")
+	if len(lines) > maxFormatDiffLines {
+		return note + " View the file before editing it again."
+	}
+	return note + " Changes made by the formatter:\n" + strings.Join(lines, "\n")
+}
+
+// withNote adds the formatting note, if any, to a tool result.
+func (r formatResult) withNote(text string) string {
+	if r.note == "" {
+		return text
+	}
+	return text + "\n" + r.note
+}
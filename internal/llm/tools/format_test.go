@@ -0,0 +1,134 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/lsp"
+	"github.com/opencode-ai/opencode/internal/lsp/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFormatServer is not a real test: it is run as a language server by
+// startFormatServer and answers formatting requests the way FORMAT_SERVER
+// says.
+func TestFormatServer(t *testing.T) {
+	mode := os.Getenv("FORMAT_SERVER")
+	if mode == "" {
+		return
+	}
+	if _, err := config.Load(t.TempDir(), false); err != nil {
+		os.Exit(1)
+	}
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		msg, err := lsp.ReadMessage(reader)
+		if err != nil {
+			os.Exit(0)
+		}
+		if msg.Method != "textDocument/formatting" {
+			continue
+		}
+		response := &lsp.Message{JSONRPC: "2.0", ID: msg.ID}
+		switch mode {
+		case "error":
+			response.Error = &lsp.ResponseError{Code: -32603, Message: "syntax error"}
+		case "unchanged":
+			response.Result = json.RawMessage(`[]`)
+		case "blank line":
+			edits, _ := json.Marshal([]protocol.TextEdit{{
+				Range:   protocol.Range{Start: protocol.Position{Line: 1}, End: protocol.Position{Line: 1}},
+				NewText: "\n",
+			}})
+			response.Result = edits
+		}
+		if err := lsp.WriteMessage(os.Stdout, response); err != nil {
+			os.Exit(1)
+		}
+	}
+}
+
+func startFormatServer(t *testing.T, mode string) map[string]*lsp.Client {
+	t.Helper()
+	t.Setenv("FORMAT_SERVER", mode)
+	client, err := lsp.NewClient(context.Background(), os.Args[0], "-test.run=^TestFormatServer$")
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return map[string]*lsp.Client{"fake": client}
+}
+
+func TestFormatOnWrite(t *testing.T) {
+	cfg, err := config.Load(t.TempDir(), false)
+	require.NoError(t, err)
+	previous := cfg.LSP
+	cfg.LSP = map[string]config.LSPConfig{"fake": {FormatOnWrite: []string{".go"}}}
+	t.Cleanup(func() { cfg.LSP = previous })
+
+	// A server that stops answering fails the test instead of hanging it
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	const written = "package main\nfunc main() {}\n"
+	const formatted = "package main\n\nfunc main() {}\n"
+	writeFile := func(t *testing.T, name string) string {
+		path := filepath.Join(t.TempDir(), name)
+		require.NoError(t, os.WriteFile(path, []byte(written), 0o644))
+		return path
+	}
+
+	t.Run("no LSP", func(t *testing.T) {
+		path := writeFile(t, "main.go")
+		result := formatOnWrite(ctx, path, "", written, nil)
+		assert.Equal(t, formatResult{content: written}, result)
+	})
+
+	t.Run("extension not formatted", func(t *testing.T) {
+		path := writeFile(t, "main.txt")
+		result := formatOnWrite(ctx, path, "", written, startFormatServer(t, "blank line"))
+		assert.Equal(t, formatResult{content: written}, result)
+		assertFileContent(t, path, written)
+	})
+
+	t.Run("formatter error", func(t *testing.T) {
+		path := writeFile(t, "main.go")
+		result := formatOnWrite(ctx, path, "", written, startFormatServer(t, "error"))
+		assert.Equal(t, formatResult{content: written}, result)
+		assertFileContent(t, path, written)
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		path := writeFile(t, "main.go")
+		result := formatOnWrite(ctx, path, "", written, startFormatServer(t, "unchanged"))
+		assert.Equal(t, formatResult{content: written}, result)
+		assertFileContent(t, path, written)
+	})
+
+	t.Run("changed content", func(t *testing.T) {
+		path := writeFile(t, "main.go")
+		result := formatOnWrite(ctx, path, "package main\n", written, startFormatServer(t, "blank line"))
+		assert.Equal(t, formatted, result.content)
+		assertFileContent(t, path, formatted)
+		assert.True(t, strings.HasPrefix(result.note, "The file was formatted by the fake language server after writing (+1 -0 lines). Changes made by the formatter:\n"), result.note)
+		assert.Contains(t, result.note, " package main\n+\n func main() {}")
+		// The diff and counts are from the content before the write
+		assert.Equal(t, 2, result.additions)
+		assert.Equal(t, 0, result.removals)
+		assert.Contains(t, result.diff, "+func main() {}")
+		assert.Equal(t, "Edited.\n"+result.note, result.withNote("Edited."))
+	})
+}
+
+func assertFileContent(t *testing.T, path, want string) {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, want, string(content))
+}
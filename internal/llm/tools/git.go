@@ -0,0 +1,503 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/permission"
+)
+
+type GitParams struct {
+	Operation string   `json:"operation"`
+	Action    string   `json:"action"`
+	Paths     []string `json:"paths"`
+	Ref       string   `json:"ref"`
+	Staged    bool     `json:"staged"`
+	Stat      bool     `json:"stat"`
+	Limit     int      `json:"limit"`
+	Message   string   `json:"message"`
+	Name      string   `json:"name"`
+	StartLine int      `json:"start_line"`
+	EndLine   int      `json:"end_line"`
+}
+
+type GitPermissionsParams struct {
+	Command string `json:"command"`
+	Changes string `json:"changes,omitempty"`
+}
+
+type gitTool struct {
+	permissions permission.Service
+}
+
+const (
+	GitToolName     = "git"
+	DefaultLogLimit = 20
+	maxLogLimit     = 200
+	gitTimeout      = 60 * time.Second
+	gitDescription  = `Runs git operations on the repository in the working directory and returns their results in a compact form.
+
+WHEN TO USE THIS TOOL:
+- Use instead of running git through the bash tool, for inspecting history and changes and for making commits
+- Use to see what changed before committing or reviewing work
+
+HOW TO USE:
+- operation "status": the branch and the changed files, one per line as "XY path" (git status --porcelain)
+- operation "diff": the unstaged changes, or the staged ones with staged set to true, or the changes against ref; limit it with paths
+- operation "log": one commit per line as "hash<TAB>date<TAB>author<TAB>subject", newest first, from ref (default HEAD) and for paths, up to limit commits (default 20)
+- operation "show": the message and changes of the commit in ref (default HEAD), or a file at a commit with ref like "HEAD~2:path/to/file"
+- operation "blame": one line per source line as "hash date author line: text", for the file in paths, optionally from start_line to end_line
+- operation "add": stages paths
+- operation "commit": commits the staged changes with message, or only paths when given
+- operation "branch": action "list" (default), "create" (name, optionally from ref), "switch" (name) or "delete" (name)
+- operation "stash": action "list" (default), "show" (ref), "push" (optional message and paths), "pop", "apply" or "drop" (optional ref)
+- Set stat to true with diff or show to get "added<TAB>removed<TAB>path" lines instead of the patch
+
+FEATURES:
+- status, diff, log, show, blame, branch list, stash list and stash show run without asking the user
+- The other operations ask the user for permission first
+
+LIMITATIONS:
+- Remote operations like fetch, pull and push are not available, use the bash tool for those when the user asks
+- Interactive commands like rebase -i are not available
+- Long results are saved in full, read the rest with the read_output tool
+
+COMMITTING CHANGES:
+When the user asks you to create a new git commit:
+1. Run status, diff and log in a single message to see the changes and the commit message style of the repository
+2. Add only the files that are part of the change. Don't add unrelated files, the user may have untracked files they want to keep out of the commit
+3. Draft a concise (1-2 sentences) commit message that focuses on the "why" rather than the "what", and check the changes for sensitive information that shouldn't be committed
+4. Commit with a message ending with:
+🤖 Generated with opencode
+Co-Authored-By: opencode <noreply@opencode.ai>
+5. If the commit fails because a pre-commit hook changed files, add them and commit again once
+6. Run status to make sure the commit succeeded
+
+TIPS:
+- Never update the git config and never push unless the user asks
+- If there are no changes to commit, don't create an empty commit
+- Use stat to see which files a large diff touches before reading it`
+)
+
+// gitReadOnlyActions are the actions of operations that don't change the
+// repository, keyed by operation.
+var gitReadOnlyActions = map[string][]string{
+	"branch": {"list"},
+	"stash":  {"list", "show"},
+}
+
+func NewGitTool(permissions permission.Service) BaseTool {
+	return &gitTool{
+		permissions: permissions,
+	}
+}
+
+func (g *gitTool) Info() ToolInfo {
+	return ToolInfo{
+		Name:        GitToolName,
+		Description: gitDescription,
+		Parameters: map[string]any{
+			"operation": map[string]any{
+				"type":        "string",
+				"description": "The git operation to run",
+				"enum":        []string{"status", "diff", "log", "show", "blame", "add", "commit", "branch", "stash"},
+			},
+			"action": map[string]any{
+				"type":        "string",
+				"description": "For branch: list, create, switch or delete. For stash: list, show, push, pop, apply or drop",
+			},
+			"paths": map[string]any{
+				"type":        "array",
+				"description": "The paths to limit diff, log, add, commit and stash push to, or the file to blame",
+				"items": map[string]any{
+					"type": "string",
+				},
+			},
+			"ref": map[string]any{
+				"type":        "string",
+				"description": "A commit, branch, tag or stash, like HEAD~1, main or stash@{0}",
+			},
+			"staged": map[string]any{
+				"type":        "boolean",
+				"description": "Show the staged changes instead of the unstaged ones (for diff)",
+			},
+			"stat": map[string]any{
+				"type":        "boolean",
+				"description": "Only show the number of added and removed lines per file (for diff and show)",
+			},
+			"limit": map[string]any{
+				"type":        "number",
+				"description": "The maximum number of commits to show (for log, default 20)",
+			},
+			"message": map[string]any{
+				"type":        "string",
+				"description": "The commit or stash message",
+			},
+			"name": map[string]any{
+				"type":        "string",
+				"description": "The branch name (for branch create, switch and delete)",
+			},
+			"start_line": map[string]any{
+				"type":        "number",
+				"description": "The first line to blame (optional)",
+			},
+			"end_line": map[string]any{
+				"type":        "number",
+				"description": "The last line to blame (optional)",
+			},
+		},
+		Required: []string{"operation"},
+	}
+}
+
+func (g *gitTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error) {
+	var params GitParams
+	if err := json.Unmarshal([]byte(call.Input), &params); err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("error parsing parameters: %s", err)), nil
+	}
+
+	args, err := gitArgs(params)
+	if err != nil {
+		return NewTextErrorResponse(err.Error()), nil
+	}
+
+	dir := config.WorkingDirectory()
+	if !isReadOnlyGit(params) {
+		sessionID, messageID := GetContextValues(ctx)
+		if sessionID == "" || messageID == "" {
+			return ToolResponse{}, fmt.Errorf("session ID and message ID are required for changing the repository")
+		}
+		permissionParams := GitPermissionsParams{
+			Command: "git " + shellQuoteArgs(args),
+		}
+		if params.Operation == "commit" {
+			permissionParams.Changes = commitChanges(ctx, dir, params)
+		}
+		p := g.permissions.Request(
+			permission.CreatePermissionRequest{
+				SessionID:   sessionID,
+				Path:        dir,
+				ToolName:    GitToolName,
+				Action:      params.Operation,
+				Description: fmt.Sprintf("Run %s", permissionParams.Command),
+				Params:      permissionParams,
+			},
+		)
+		if !p {
+			return ToolResponse{}, permission.ErrorPermissionDenied
+		}
+	}
+
+	output, err := runGit(ctx, dir, args...)
+	if err != nil {
+		return NewTextErrorResponse(err.Error()), nil
+	}
+	return NewTextResponse(SpillOutput(ctx, GitToolName, formatGitOutput(params, output))), nil
+}
+
+// commitChanges lists what a commit is about to record: the staged changes,
+// or with paths the working tree content of those paths, staged or not. It
+// doesn't fail the request when they can't be listed.
+func commitChanges(ctx context.Context, dir string, params GitParams) string {
+	args := []string{"diff", "--no-textconv", "--numstat", "--cached"}
+	if len(params.Paths) > 0 {
+		args = append([]string{"diff", "--no-textconv", "--numstat", "HEAD", "--"}, params.Paths...)
+	}
+	changes, _ := runGit(ctx, dir, args...)
+	return strings.TrimSpace(changes)
+}
+
+func isReadOnlyGit(params GitParams) bool {
+	switch params.Operation {
+	case "status", "diff", "log", "show", "blame":
+		return true
+	}
+	for _, action := range gitReadOnlyActions[params.Operation] {
+		if gitAction(params) == action {
+			return true
+		}
+	}
+	return false
+}
+
+// gitAction returns the action of branch and stash, which default to list.
+func gitAction(params GitParams) string {
+	if params.Action == "" {
+		return "list"
+	}
+	return params.Action
+}
+
+// gitArgs builds the git arguments for an operation. Refs and names are
+// checked so they can't be read as options, paths always follow "--".
+func gitArgs(params GitParams) ([]string, error) {
+	for _, value := range []string{params.Ref, params.Name} {
+		if strings.HasPrefix(value, "-") {
+			return nil, fmt.Errorf("invalid ref or name %q", value)
+		}
+	}
+	paths := append([]string{"--"}, params.Paths...)
+
+	switch params.Operation {
+	case "status":
+		return []string{"status", "--porcelain=v1", "--branch", "--untracked-files=all"}, nil
+	case "diff":
+		args := []string{"diff", "--no-color", "--no-ext-diff", "--no-textconv"}
+		if params.Stat {
+			args = append(args, "--numstat")
+		}
+		if params.Staged {
+			args = append(args, "--cached")
+		}
+		if params.Ref != "" {
+			args = append(args, params.Ref)
+		}
+		return append(args, paths...), nil
+	case "log":
+		limit := params.Limit
+		if limit <= 0 {
+			limit = DefaultLogLimit
+		}
+		args := []string{"log", "--format=%h%x09%ad%x09%an%x09%s", "--date=short", fmt.Sprintf("--max-count=%d", min(limit, maxLogLimit))}
+		if params.Ref != "" {
+			args = append(args, params.Ref)
+		}
+		return append(args, paths...), nil
+	case "show":
+		ref := params.Ref
+		if ref == "" {
+			ref = "HEAD"
+		}
+		if strings.Contains(ref, ":") {
+			// A file at a commit
+			return []string{"show", "--no-color", "--no-textconv", ref}, nil
+		}
+		args := []string{"show", "--no-color", "--no-ext-diff", "--no-textconv", "--date=short", "--format=commit %H%nauthor %an <%ae>%ndate %ad%n%n%B"}
+		if params.Stat {
+			args = append(args, "--numstat")
+		}
+		return append(args, ref), nil
+	case "blame":
+		if len(params.Paths) != 1 {
+			return nil, errors.New("blame needs exactly one file in paths")
+		}
+		args := []string{"blame", "--porcelain", "--no-textconv"}
+		if params.StartLine > 0 || params.EndLine > 0 {
+			start := max(params.StartLine, 1)
+			end := ""
+			if params.EndLine > 0 {
+				if params.EndLine < start {
+					return nil, errors.New("end_line must not be before start_line")
+				}
+				end = strconv.Itoa(params.EndLine)
+			}
+			args = append(args, fmt.Sprintf("-L%d,%s", start, end))
+		}
+		if params.Ref != "" {
+			args = append(args, params.Ref)
+		}
+		return append(args, paths...), nil
+	case "add":
+		if len(params.Paths) == 0 {
+			return nil, errors.New("paths are required")
+		}
+		return append([]string{"add"}, paths...), nil
+	case "commit":
+		if strings.TrimSpace(params.Message) == "" {
+			return nil, errors.New("message is required")
+		}
+		args := []string{"commit", "--message", params.Message}
+		if len(params.Paths) > 0 {
+			args = append(args, paths...)
+		}
+		return args, nil
+	case "branch":
+		action := gitAction(params)
+		if action != "list" && params.Name == "" {
+			return nil, fmt.Errorf("name is required to %s a branch", action)
+		}
+		switch action {
+		case "list":
+			return []string{"branch", "--list", "--format=%(HEAD)%09%(refname:short)%09%(objectname:short)%09%(upstream:short)%09%(upstream:track)"}, nil
+		case "create":
+			args := []string{"branch", params.Name}
+			if params.Ref != "" {
+				args = append(args, params.Ref)
+			}
+			return args, nil
+		case "switch":
+			return []string{"switch", params.Name}, nil
+		case "delete":
+			return []string{"branch", "--delete", params.Name}, nil
+		}
+		return nil, fmt.Errorf("unknown branch action %q, use list, create, switch or delete", params.Action)
+	case "stash":
+		switch action := gitAction(params); action {
+		case "list":
+			return []string{"stash", "list", "--format=%gd%x09%cd%x09%gs", "--date=short"}, nil
+		case "show":
+			args := []string{"stash", "show", "--no-color", "--no-ext-diff", "--no-textconv", "--patch"}
+			if params.Stat {
+				args = []string{"stash", "show", "--no-textconv", "--numstat"}
+			}
+			if params.Ref != "" {
+				args = append(args, params.Ref)
+			}
+			return args, nil
+		case "push":
+			args := []string{"stash", "push"}
+			if params.Message != "" {
+				args = append(args, "--message", params.Message)
+			}
+			if len(params.Paths) > 0 {
+				args = append(args, paths...)
+			}
+			return args, nil
+		case "pop", "apply", "drop":
+			args := []string{"stash", action}
+			if params.Ref != "" {
+				args = append(args, params.Ref)
+			}
+			return args, nil
+		}
+		return nil, fmt.Errorf("unknown stash action %q, use list, show, push, pop, apply or drop", params.Action)
+	case "":
+		return nil, errors.New("operation is required")
+	}
+	return nil, fmt.Errorf("unknown operation %q, use status, diff, log, show, blame, add, commit, branch or stash", params.Operation)
+}
+
+// runGit runs git in dir and returns its output. When git fails the error
+// holds what it printed.
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, gitTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", append([]string{"--no-pager", "-c", "core.quotepath=false", "-c", "core.fsmonitor=false"}, args...)...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_EDITOR=true", "LC_ALL=C")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("git %s timed out after %s", args[0], gitTimeout)
+		}
+		message := strings.TrimSpace(stderr.String() + "\n" + stdout.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return "", fmt.Errorf("git %s failed: %s", args[0], message)
+	}
+	return stdout.String() + stderr.String(), nil
+}
+
+// formatGitOutput turns the output of git into the result of an operation.
+func formatGitOutput(params GitParams, output string) string {
+	output = strings.TrimRight(output, "\n")
+	switch params.Operation {
+	case "status":
+		if !strings.Contains(output, "\n") {
+			return output + "\nnothing to commit, working tree clean"
+		}
+	case "diff":
+		if output == "" {
+			return "No changes"
+		}
+	case "log":
+		if output == "" {
+			return "No commits"
+		}
+	case "blame":
+		return formatBlame(output)
+	case "branch", "stash":
+		if output == "" {
+			switch {
+			case params.Operation == "branch" && gitAction(params) == "list":
+				return "No branches"
+			case params.Operation == "stash" && gitAction(params) == "list":
+				return "No stashes"
+			}
+			return fmt.Sprintf("git %s %s done", params.Operation, gitAction(params))
+		}
+	case "add":
+		if output == "" {
+			return fmt.Sprintf("Staged %s", strings.Join(params.Paths, ", "))
+		}
+	}
+	return output
+}
+
+type blameCommit struct {
+	author string
+	date   string
+}
+
+// formatBlame turns the output of git blame --porcelain into one line per
+// source line, like "1a2b3c4d 2024-05-01 Jane Doe 12: text".
+func formatBlame(output string) string {
+	commits := make(map[string]*blameCommit)
+	var lines []string
+	var hash, lineNum string
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if text, ok := strings.CutPrefix(line, "\t"); ok {
+			commit := commits[hash]
+			if commit == nil {
+				continue
+			}
+			short := hash
+			if len(short) > 8 {
+				short = short[:8]
+			}
+			lines = append(lines, fmt.Sprintf("%s %s %s %s: %s", short, commit.date, commit.author, lineNum, text))
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && len(fields[0]) == 40 {
+			hash, lineNum = fields[0], fields[2]
+			if commits[hash] == nil {
+				commits[hash] = &blameCommit{}
+			}
+			continue
+		}
+		commit := commits[hash]
+		if commit == nil {
+			continue
+		}
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "author":
+			commit.author = value
+		case "author-time":
+			if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+				commit.date = time.Unix(seconds, 0).UTC().Format(time.DateOnly)
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// shellQuoteArgs joins args for showing them as a command line.
+func shellQuoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n'\"$`\\*?;&|<>(){}[]#~") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
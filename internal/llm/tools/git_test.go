@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitArgs(t *testing.T) {
+	args, err := gitArgs(GitParams{Operation: "diff", Staged: true, Paths: []string{"main.go"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"diff", "--no-color", "--no-ext-diff", "--no-textconv", "--cached", "--", "main.go"}, args)
+
+	args, err = gitArgs(GitParams{Operation: "blame", Paths: []string{"main.go"}, StartLine: 3, EndLine: 5})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"blame", "--porcelain", "--no-textconv", "-L3,5", "--", "main.go"}, args)
+
+	_, err = gitArgs(GitParams{Operation: "show", Ref: "--output=/tmp/x"})
+	assert.Error(t, err)
+	_, err = gitArgs(GitParams{Operation: "commit"})
+	assert.Error(t, err)
+	_, err = gitArgs(GitParams{Operation: "rebase"})
+	assert.Error(t, err)
+}
+
+func TestIsReadOnlyGit(t *testing.T) {
+	assert.True(t, isReadOnlyGit(GitParams{Operation: "log"}))
+	assert.True(t, isReadOnlyGit(GitParams{Operation: "branch"}))
+	assert.True(t, isReadOnlyGit(GitParams{Operation: "stash", Action: "show"}))
+	assert.False(t, isReadOnlyGit(GitParams{Operation: "stash", Action: "pop"}))
+	assert.False(t, isReadOnlyGit(GitParams{Operation: "branch", Action: "create"}))
+	assert.False(t, isReadOnlyGit(GitParams{Operation: "commit"}))
+}
+
+func TestGitOperations(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	git := func(params GitParams) string {
+		t.Helper()
+		args, err := gitArgs(params)
+		require.NoError(t, err)
+		output, err := runGit(ctx, dir, args...)
+		require.NoError(t, err)
+		return formatGitOutput(params, output)
+	}
+	_, err := runGit(ctx, dir, "init", "--quiet", "--initial-branch=main")
+	require.NoError(t, err)
+	_, err = runGit(ctx, dir, "config", "user.name", "Jane Doe")
+	require.NoError(t, err)
+	_, err = runGit(ctx, dir, "config", "user.email", "jane@example.com")
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("first\nsecond\n"), 0o644))
+	assert.Equal(t, "## No commits yet on main\n?? notes.txt", git(GitParams{Operation: "status"}))
+
+	git(GitParams{Operation: "add", Paths: []string{"notes.txt"}})
+	assert.Equal(t, "2\t0\tnotes.txt", git(GitParams{Operation: "diff", Staged: true, Stat: true}))
+	git(GitParams{Operation: "commit", Message: "Add notes"})
+	assert.Equal(t, "## main\nnothing to commit, working tree clean", git(GitParams{Operation: "status"}))
+
+	log := git(GitParams{Operation: "log"})
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{7,}\t\d{4}-\d{2}-\d{2}\tJane Doe\tAdd notes$`), log)
+
+	blame := git(GitParams{Operation: "blame", Paths: []string{"notes.txt"}, StartLine: 2})
+	hash, _, _ := strings.Cut(log, "\t")
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8} \d{4}-\d{2}-\d{2} Jane Doe 2: second$`), blame)
+	assert.True(t, strings.HasPrefix(blame, hash))
+
+	assert.Equal(t, "No changes", git(GitParams{Operation: "diff"}))
+	assert.Equal(t, "No stashes", git(GitParams{Operation: "stash"}))
+	assert.Contains(t, git(GitParams{Operation: "branch"}), "*\tmain\t")
+
+	// Commands configured in the repository don't run for read-only
+	// operations
+	marker := filepath.Join(dir, "ran")
+	_, err = runGit(ctx, dir, "config", "core.fsmonitor", "touch "+marker+"; true")
+	require.NoError(t, err)
+	_, err = runGit(ctx, dir, "config", "diff.evil.textconv", "touch "+marker+"; cat")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitattributes"), []byte("*.txt diff=evil\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("first\nchanged\n"), 0o644))
+	git(GitParams{Operation: "status"})
+	assert.Contains(t, git(GitParams{Operation: "diff"}), "+changed")
+	git(GitParams{Operation: "show", Stat: true})
+	git(GitParams{Operation: "blame", Paths: []string{"notes.txt"}})
+
+	// A commit with paths records their unstaged changes too
+	assert.Equal(t, "", commitChanges(ctx, dir, GitParams{Operation: "commit"}))
+	assert.Equal(t, "1\t1\tnotes.txt", commitChanges(ctx, dir, GitParams{Operation: "commit", Paths: []string{"notes.txt"}}))
+	assert.NoFileExists(t, marker)
+}
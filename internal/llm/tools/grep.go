@@ -10,7 +10,6 @@ import (
 	"path/filepath"
 	"regexp"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
@@ -19,29 +18,57 @@ import (
 )
 
 type GrepParams struct {
-	Pattern     string `json:"pattern"`
-	Path        string `json:"path"`
-	Include     string `json:"include"`
-	LiteralText bool   `json:"literal_text"`
+	Pattern       string `json:"pattern"`
+	Path          string `json:"path"`
+	Include       string `json:"include"`
+	Type          string `json:"type"`
+	LiteralText   bool   `json:"literal_text"`
+	IgnoreCase    bool   `json:"ignore_case"`
+	Multiline     bool   `json:"multiline"`
+	BeforeContext int    `json:"before_context"`
+	AfterContext  int    `json:"after_context"`
+	OutputMode    string `json:"output_mode"`
+	Offset        int    `json:"offset"`
+	Limit         int    `json:"limit"`
 }
 
 type grepMatch struct {
-	path     string
-	modTime  time.Time
-	lineNum  int
+	path    string
+	modTime time.Time
+	lineNum int
+	// The matching line, or all the lines of a multiline match
 	lineText string
 }
 
+// grepOptions are the search settings shared by ripgrep and the regex
+// fallback.
+type grepOptions struct {
+	include    string
+	fileGlobs  []string
+	ignoreCase bool
+	multiline  bool
+}
+
 type GrepResponseMetadata struct {
 	NumberOfMatches int  `json:"number_of_matches"`
+	NumberOfFiles   int  `json:"number_of_files"`
 	Truncated       bool `json:"truncated"`
 }
 
 type grepTool struct{}
 
 const (
-	GrepToolName    = "grep"
-	grepDescription = `Fast content search tool that finds files containing specific text or patterns, returning matching file paths sorted by modification time (newest first).
+	GrepToolName = "grep"
+
+	grepOutputContent = "content"
+	grepOutputFiles   = "files"
+	grepOutputCount   = "count"
+
+	defaultGrepLimit = 100
+	maxGrepLimit     = 1000
+	maxGrepContext   = 20
+
+	grepDescription = `Fast content search tool that finds text or patterns in files, returning matching lines with optional context, matching file paths, or match counts. Files are sorted by modification time (newest first).
 
 WHEN TO USE THIS TOOL:
 - Use when you need to find files containing specific text or patterns
@@ -52,33 +79,70 @@ HOW TO USE:
 - Provide a regex pattern to search for within file contents
 - Set literal_text=true if you want to search for the exact text with special characters (recommended for non-regex users)
 - Optionally specify a starting directory (defaults to current working directory)
-- Optionally provide an include pattern to filter which files to search
-- Results are sorted with most recently modified files first
+- Optionally provide an include pattern or a file type to filter which files to search
+- Set before_context and after_context to see lines around each match, often enough to skip a view call
+- Set ignore_case=true for case-insensitive matching
+- Set multiline=true to match patterns across lines, '.' then also matches newlines
+- Choose output_mode: "content" (matching lines, the default), "files" (matching file paths only) or "count" (matches per file)
+- Results are paginated, use offset and limit to get more
 
 REGEX PATTERN SYNTAX (when literal_text=false):
 - Supports standard regular expression syntax
 - 'function' searches for the literal text "function"
 - 'log\..*Error' finds text starting with "log." and ending with "Error"
 - 'import\s+.*\s+from' finds import statements in JavaScript/TypeScript
+- 'func \w+\(\)\s*\{\s*\}' with multiline=true finds empty functions spanning lines
 
 COMMON INCLUDE PATTERN EXAMPLES:
 - '*.js' - Only search JavaScript files
 - '*.{ts,tsx}' - Only search TypeScript files
 - '*.go' - Only search Go files
 
+FILE TYPES:
+- ` + "`type`" + ` takes a language name instead of a pattern: c, cpp, cs, css, go, html, java, js, json, kotlin, lua, md, php, py, rb, rust, sh, sql, swift, toml, ts, yaml, zig
+
 LIMITATIONS:
-- Results are limited to 100 files (newest first)
+- Returns 100 results per call by default (matching lines in content mode, files otherwise), at most 1000
+- Context is limited to 20 lines before and after each match
 - Performance depends on the number of files being searched
 - Very large binary files may be skipped
 - Hidden files (starting with '.') are skipped
 
 TIPS:
+- Start with output_mode "files" or "count" to see where matches are, then narrow down with content
 - For faster, more targeted searches, first use Glob to find relevant files, then use Grep
 - When doing iterative exploration that may require multiple rounds of searching, consider using the Agent tool instead
-- Always check if results are truncated and refine your search pattern if needed
+- Always check if results are truncated and use offset to page through them, or refine your search pattern
 - Use literal_text=true when searching for exact text containing special characters like dots, parentheses, etc.`
 )
 
+// File types and the file names they match
+var grepFileTypes = map[string][]string{
+	"c":      {"*.c", "*.h"},
+	"cpp":    {"*.cpp", "*.cc", "*.cxx", "*.hpp", "*.hh", "*.hxx", "*.h"},
+	"cs":     {"*.cs"},
+	"css":    {"*.css", "*.scss", "*.sass", "*.less"},
+	"go":     {"*.go"},
+	"html":   {"*.html", "*.htm"},
+	"java":   {"*.java"},
+	"js":     {"*.js", "*.jsx", "*.mjs", "*.cjs"},
+	"json":   {"*.json"},
+	"kotlin": {"*.kt", "*.kts"},
+	"lua":    {"*.lua"},
+	"md":     {"*.md", "*.markdown"},
+	"php":    {"*.php"},
+	"py":     {"*.py", "*.pyi"},
+	"rb":     {"*.rb"},
+	"rust":   {"*.rs"},
+	"sh":     {"*.sh", "*.bash", "*.zsh"},
+	"sql":    {"*.sql"},
+	"swift":  {"*.swift"},
+	"toml":   {"*.toml"},
+	"ts":     {"*.ts", "*.tsx", "*.mts", "*.cts"},
+	"yaml":   {"*.yaml", "*.yml"},
+	"zig":    {"*.zig"},
+}
+
 func NewGrepTool() BaseTool {
 	return &grepTool{}
 }
@@ -100,10 +164,43 @@ func (g *grepTool) Info() ToolInfo {
 				"type":        "string",
 				"description": "File pattern to include in the search (e.g. \"*.js\", \"*.{ts,tsx}\")",
 			},
+			"type": map[string]any{
+				"type":        "string",
+				"description": "Only search files of this type (e.g. \"go\", \"py\", \"ts\")",
+			},
 			"literal_text": map[string]any{
 				"type":        "boolean",
 				"description": "If true, the pattern will be treated as literal text with special regex characters escaped. Default is false.",
 			},
+			"ignore_case": map[string]any{
+				"type":        "boolean",
+				"description": "If true, matching is case-insensitive. Default is false.",
+			},
+			"multiline": map[string]any{
+				"type":        "boolean",
+				"description": "If true, patterns can match across lines and '.' matches newlines. Default is false.",
+			},
+			"before_context": map[string]any{
+				"type":        "number",
+				"description": "Number of lines to show before each match in content mode (max 20)",
+			},
+			"after_context": map[string]any{
+				"type":        "number",
+				"description": "Number of lines to show after each match in content mode (max 20)",
+			},
+			"output_mode": map[string]any{
+				"type":        "string",
+				"description": "What to return: matching lines (content), matching file paths (files) or match counts per file (count). Default is content.",
+				"enum":        []string{grepOutputContent, grepOutputFiles, grepOutputCount},
+			},
+			"offset": map[string]any{
+				"type":        "number",
+				"description": "Number of results to skip, for paging through results",
+			},
+			"limit": map[string]any{
+				"type":        "number",
+				"description": "Maximum number of results to return (default 100, max 1000)",
+			},
 		},
 		Required: []string{"pattern"},
 	}
@@ -131,6 +228,27 @@ func (g *grepTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error)
 		return NewTextErrorResponse("pattern is required"), nil
 	}
 
+	outputMode := params.OutputMode
+	if outputMode == "" {
+		outputMode = grepOutputContent
+	}
+	if outputMode != grepOutputContent && outputMode != grepOutputFiles && outputMode != grepOutputCount {
+		return NewTextErrorResponse(fmt.Sprintf("unknown output_mode %q, use content, files or count", params.OutputMode)), nil
+	}
+
+	opts := grepOptions{
+		include:    params.Include,
+		ignoreCase: params.IgnoreCase,
+		multiline:  params.Multiline,
+	}
+	if params.Type != "" {
+		globs, ok := grepFileTypes[strings.ToLower(params.Type)]
+		if !ok {
+			return NewTextErrorResponse(fmt.Sprintf("unknown file type %q, use include with a file pattern instead", params.Type)), nil
+		}
+		opts.fileGlobs = globs
+	}
+
 	// If literal_text is true, escape the pattern
 	searchPattern := params.Pattern
 	if params.LiteralText {
@@ -142,35 +260,56 @@ func (g *grepTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error)
 		searchPath = config.WorkingDirectory()
 	}
 
-	matches, truncated, err := searchFiles(searchPattern, searchPath, params.Include, 100)
+	matches, err := searchFiles(searchPattern, searchPath, opts)
 	if err != nil {
 		return ToolResponse{}, fmt.Errorf("error searching files: %w", err)
 	}
+	files := matchedFiles(matches)
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultGrepLimit
+	}
+	limit = min(limit, maxGrepLimit)
+	offset := max(params.Offset, 0)
 
 	var output string
-	if len(matches) == 0 {
+	var total, shown int
+	switch {
+	case len(matches) == 0:
 		output = "No files found"
-	} else {
-		output = fmt.Sprintf("Found %d matches\n", len(matches))
-
-		currentFile := ""
-		for _, match := range matches {
-			if currentFile != match.path {
-				if currentFile != "" {
-					output += "\n"
-				}
-				currentFile = match.path
-				output += fmt.Sprintf("%s:\n", match.path)
-			}
-			if match.lineNum > 0 {
-				output += fmt.Sprintf("  Line %d: %s\n", match.lineNum, match.lineText)
+	case outputMode == grepOutputContent:
+		total = len(matches)
+		page := paginate(matches, offset, limit)
+		shown = len(page)
+		before := min(max(params.BeforeContext, 0), maxGrepContext)
+		after := min(max(params.AfterContext, 0), maxGrepContext)
+		output = fmt.Sprintf("Found %d matches in %d files\n", len(matches), len(files)) + formatGrepContent(page, before, after)
+	default:
+		total = len(files)
+		page := paginate(files, offset, limit)
+		shown = len(page)
+		lines := make([]string, 0, len(page))
+		for _, file := range page {
+			if outputMode == grepOutputCount {
+				lines = append(lines, fmt.Sprintf("%s: %d", file.path, file.count))
 			} else {
-				output += fmt.Sprintf("  %s\n", match.path)
+				lines = append(lines, file.path)
 			}
 		}
+		output = fmt.Sprintf("Found %d files with %d matches\n", len(files), len(matches)) + strings.Join(lines, "\n")
+	}
 
-		if truncated {
-			output += "\n(Results are truncated. Consider using a more specific path or pattern.)"
+	truncated := offset+shown < total
+	if len(matches) > 0 {
+		if shown == 0 {
+			output += fmt.Sprintf("\n(No results after offset %d.)", offset)
+		} else if offset > 0 || truncated {
+			output += fmt.Sprintf("\n\n(Showing results %d-%d of %d.", offset+1, offset+shown, total)
+			if truncated {
+				output += fmt.Sprintf(" Use offset=%d to see more, or a more specific path or pattern.", offset+shown)
+			}
+			output += ")"
 		}
 	}
 
@@ -178,44 +317,155 @@ func (g *grepTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error)
 		NewTextResponse(output),
 		GrepResponseMetadata{
 			NumberOfMatches: len(matches),
+			NumberOfFiles:   len(files),
 			Truncated:       truncated,
 		},
 	), nil
 }
 
-func searchFiles(pattern, rootPath, include string, limit int) ([]grepMatch, bool, error) {
-	matches, err := searchWithRipgrep(pattern, rootPath, include)
+func paginate[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	return items[offset:min(offset+limit, len(items))]
+}
+
+type grepFile struct {
+	path  string
+	count int
+}
+
+// matchedFiles returns the files of sorted matches with their match counts.
+func matchedFiles(matches []grepMatch) []grepFile {
+	var files []grepFile
+	for _, match := range matches {
+		if len(files) > 0 && files[len(files)-1].path == match.path {
+			files[len(files)-1].count++
+			continue
+		}
+		files = append(files, grepFile{path: match.path, count: 1})
+	}
+	return files
+}
+
+// formatGrepContent lists matches by file like ripgrep does, with "12:" in
+// front of matching lines, "11-" in front of context lines and "--" between
+// lines that aren't adjacent.
+func formatGrepContent(matches []grepMatch, before, after int) string {
+	var sb strings.Builder
+	for i := 0; i < len(matches); {
+		path := matches[i].path
+		j := i
+		for j < len(matches) && matches[j].path == path {
+			j++
+		}
+		fileMatches := matches[i:j]
+		i = j
+
+		matchLines := make(map[int]string)
+		for _, match := range fileMatches {
+			for k, line := range strings.Split(match.lineText, "\n") {
+				matchLines[match.lineNum+k] = line
+			}
+		}
+		var fileLines []string
+		if before > 0 || after > 0 {
+			if content, err := os.ReadFile(path); err == nil {
+				fileLines = strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+			}
+		}
+
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "%s:\n", path)
+		last := 0
+		for _, match := range fileMatches {
+			start := max(match.lineNum-before, 1)
+			end := match.lineNum + strings.Count(match.lineText, "\n") + after
+			if len(fileLines) > 0 {
+				end = min(end, len(fileLines))
+			}
+			if (before > 0 || after > 0) && last > 0 && start > last+1 {
+				sb.WriteString("  --\n")
+			}
+			for lineNum := max(start, last+1); lineNum <= end; lineNum++ {
+				if line, ok := matchLines[lineNum]; ok {
+					fmt.Fprintf(&sb, "  %d: %s\n", lineNum, line)
+				} else if lineNum <= len(fileLines) {
+					fmt.Fprintf(&sb, "  %d- %s\n", lineNum, fileLines[lineNum-1])
+				}
+			}
+			last = max(last, end)
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// searchFiles returns every match, with files sorted newest first and
+// matches in file order.
+func searchFiles(pattern, rootPath string, opts grepOptions) ([]grepMatch, error) {
+	matches, err := searchWithRipgrep(pattern, rootPath, opts)
 	if err != nil {
-		matches, err = searchFilesWithRegex(pattern, rootPath, include)
+		matches, err = searchFilesWithRegex(pattern, rootPath, opts)
 		if err != nil {
-			return nil, false, err
+			return nil, err
 		}
 	}
 
-	sort.Slice(matches, func(i, j int) bool {
-		return matches[i].modTime.After(matches[j].modTime)
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if !a.modTime.Equal(b.modTime) {
+			return a.modTime.After(b.modTime)
+		}
+		if a.path != b.path {
+			return a.path < b.path
+		}
+		return a.lineNum < b.lineNum
 	})
 
-	truncated := len(matches) > limit
-	if truncated {
-		matches = matches[:limit]
-	}
+	return matches, nil
+}
 
-	return matches, truncated, nil
+// ripgrepMessage is a line of the ripgrep --json output. Only match messages
+// are used.
+type ripgrepMessage struct {
+	Type string `json:"type"`
+	Data struct {
+		Path struct {
+			Text string `json:"text"`
+		} `json:"path"`
+		Lines struct {
+			Text string `json:"text"`
+		} `json:"lines"`
+		LineNumber int `json:"line_number"`
+	} `json:"data"`
 }
 
-func searchWithRipgrep(pattern, path, include string) ([]grepMatch, error) {
+func searchWithRipgrep(pattern, path string, opts grepOptions) ([]grepMatch, error) {
 	_, err := exec.LookPath("rg")
 	if err != nil {
 		return nil, fmt.Errorf("ripgrep not found: %w", err)
 	}
 
-	// Use -n to show line numbers and include the matched line
-	args := []string{"-H", "-n", pattern}
-	if include != "" {
-		args = append(args, "--glob", include)
+	args := []string{"--json"}
+	if opts.ignoreCase {
+		args = append(args, "--ignore-case")
 	}
-	args = append(args, path)
+	if opts.multiline {
+		args = append(args, "--multiline", "--multiline-dotall")
+	}
+	if opts.include != "" {
+		args = append(args, "--glob", opts.include)
+	}
+	if len(opts.fileGlobs) > 0 {
+		// A custom type, so it narrows the include glob instead of adding to it
+		for _, glob := range opts.fileGlobs {
+			args = append(args, "--type-add", "search:"+glob)
+		}
+		args = append(args, "--type", "search")
+	}
+	args = append(args, "-e", pattern, "--", path)
 
 	cmd := exec.Command("rg", args...)
 	output, err := cmd.Output()
@@ -226,54 +476,59 @@ func searchWithRipgrep(pattern, path, include string) ([]grepMatch, error) {
 		return nil, err
 	}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	matches := make([]grepMatch, 0, len(lines))
-
-	for _, line := range lines {
-		if line == "" {
+	var matches []grepMatch
+	modTimes := make(map[string]time.Time)
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(output)+1)
+	for scanner.Scan() {
+		var msg ripgrepMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil || msg.Type != "match" {
 			continue
 		}
-
-		// Parse ripgrep output format: file:line:content
-		parts := strings.SplitN(line, ":", 3)
-		if len(parts) < 3 {
+		// Paths and lines that aren't valid UTF-8 come as bytes and are skipped
+		filePath := msg.Data.Path.Text
+		if filePath == "" || msg.Data.Lines.Text == "" {
 			continue
 		}
 
-		filePath := parts[0]
-		lineNum, err := strconv.Atoi(parts[1])
-		if err != nil {
-			continue
-		}
-		lineText := parts[2]
-
-		fileInfo, err := os.Stat(filePath)
-		if err != nil {
-			continue // Skip files we can't access
+		modTime, ok := modTimes[filePath]
+		if !ok {
+			fileInfo, err := os.Stat(filePath)
+			if err != nil {
+				continue // Skip files we can't access
+			}
+			modTime = fileInfo.ModTime()
+			modTimes[filePath] = modTime
 		}
 
 		matches = append(matches, grepMatch{
 			path:     filePath,
-			modTime:  fileInfo.ModTime(),
-			lineNum:  lineNum,
-			lineText: lineText,
+			modTime:  modTime,
+			lineNum:  msg.Data.LineNumber,
+			lineText: strings.TrimRight(msg.Data.Lines.Text, "\r\n"),
 		})
 	}
 
 	return matches, nil
 }
 
-func searchFilesWithRegex(pattern, rootPath, include string) ([]grepMatch, error) {
+func searchFilesWithRegex(pattern, rootPath string, opts grepOptions) ([]grepMatch, error) {
 	matches := []grepMatch{}
 
+	if opts.multiline {
+		pattern = "(?ms)" + pattern
+	}
+	if opts.ignoreCase {
+		pattern = "(?i)" + pattern
+	}
 	regex, err := regexp.Compile(pattern)
 	if err != nil {
 		return nil, fmt.Errorf("invalid regex pattern: %w", err)
 	}
 
 	var includePattern *regexp.Regexp
-	if include != "" {
-		regexPattern := globToRegex(include)
+	if opts.include != "" {
+		regexPattern := globToRegex(opts.include)
 		includePattern, err = regexp.Compile(regexPattern)
 		if err != nil {
 			return nil, fmt.Errorf("invalid include pattern: %w", err)
@@ -289,7 +544,9 @@ func searchFilesWithRegex(pattern, rootPath, include string) ([]grepMatch, error
 			return nil // Skip directories
 		}
 
-		if fileutil.SkipHidden(path) {
+		// Only the part below the root is checked, so searching inside an
+		// ignored directory like tmp still works
+		if relPath, err := filepath.Rel(rootPath, path); err == nil && fileutil.SkipHidden(relPath) {
 			return nil
 		}
 
@@ -297,22 +554,24 @@ func searchFilesWithRegex(pattern, rootPath, include string) ([]grepMatch, error
 			return nil
 		}
 
-		match, lineNum, lineText, err := fileContainsPattern(path, regex)
+		if len(opts.fileGlobs) > 0 && !matchesAnyGlob(filepath.Base(path), opts.fileGlobs) {
+			return nil
+		}
+
+		var fileMatches []grepMatch
+		if opts.multiline {
+			fileMatches, err = fileMultilineMatches(path, regex)
+		} else {
+			fileMatches, err = fileLineMatches(path, regex)
+		}
 		if err != nil {
 			return nil // Skip files we can't read
 		}
 
-		if match {
-			matches = append(matches, grepMatch{
-				path:     path,
-				modTime:  info.ModTime(),
-				lineNum:  lineNum,
-				lineText: lineText,
-			})
-
-			if len(matches) >= 200 {
-				return filepath.SkipAll
-			}
+		for _, match := range fileMatches {
+			match.path = path
+			match.modTime = info.ModTime()
+			matches = append(matches, match)
 		}
 
 		return nil
@@ -324,24 +583,77 @@ func searchFilesWithRegex(pattern, rootPath, include string) ([]grepMatch, error
 	return matches, nil
 }
 
-func fileContainsPattern(filePath string, pattern *regexp.Regexp) (bool, int, string, error) {
+func matchesAnyGlob(name string, globs []string) bool {
+	for _, glob := range globs {
+		if matched, _ := filepath.Match(glob, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// fileLineMatches returns the lines of a file that match the pattern.
+func fileLineMatches(filePath string, pattern *regexp.Regexp) ([]grepMatch, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
-		return false, 0, "", err
+		return nil, err
 	}
 	defer file.Close()
 
+	var matches []grepMatch
 	scanner := bufio.NewScanner(file)
 	lineNum := 0
 	for scanner.Scan() {
 		lineNum++
 		line := scanner.Text()
 		if pattern.MatchString(line) {
-			return true, lineNum, line, nil
+			matches = append(matches, grepMatch{lineNum: lineNum, lineText: line})
+		}
+	}
+
+	return matches, scanner.Err()
+}
+
+// fileMultilineMatches matches the pattern against the whole file and
+// returns the lines each match spans.
+func fileMultilineMatches(filePath string, pattern *regexp.Regexp) ([]grepMatch, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	content := string(data)
+
+	var matches []grepMatch
+	lineNum, counted := 1, 0
+	lastStart, lastEnd := -1, -1
+	for _, loc := range pattern.FindAllStringIndex(content, -1) {
+		start, end := loc[0], loc[1]
+		lineNum += strings.Count(content[counted:start], "\n")
+		counted = start
+
+		// A match ending with a newline ends on the line before it
+		if end > start && content[end-1] == '\n' {
+			end--
+		}
+		lineStart := strings.LastIndexByte(content[:start], '\n') + 1
+		lineEnd := len(content)
+		if i := strings.IndexByte(content[end:], '\n'); i >= 0 {
+			lineEnd = end + i
 		}
+		if lineStart <= lastEnd {
+			// Matches sharing a line are reported once, like ripgrep does
+			lastEnd = max(lastEnd, lineEnd)
+			matches[len(matches)-1].lineText = strings.TrimRight(content[lastStart:lastEnd], "\r")
+			continue
+		}
+		lastStart, lastEnd = lineStart, lineEnd
+		matches = append(matches, grepMatch{
+			lineNum:  lineNum,
+			lineText: strings.TrimRight(content[lineStart:lineEnd], "\r"),
+		})
 	}
 
-	return false, 0, "", scanner.Err()
+	return matches, nil
 }
 
 func globToRegex(glob string) string {
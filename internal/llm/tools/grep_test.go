@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeGrepFiles(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":   "package main\n\nfunc main() {\n\tRun()\n}\n\nfunc Run() {}\n",
+		"util.py":   "def run():\n    pass\n",
+		"README.md": "Run the tool\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	return dir
+}
+
+func TestSearchFilesWithRegex(t *testing.T) {
+	dir := writeGrepFiles(t)
+
+	t.Run("case sensitive", func(t *testing.T) {
+		matches, err := searchFilesWithRegex("Run", dir, grepOptions{})
+		require.NoError(t, err)
+		assert.Len(t, matches, 3)
+	})
+
+	t.Run("ignore case", func(t *testing.T) {
+		matches, err := searchFilesWithRegex("run", dir, grepOptions{ignoreCase: true})
+		require.NoError(t, err)
+		assert.Len(t, matches, 4)
+	})
+
+	t.Run("file type", func(t *testing.T) {
+		matches, err := searchFilesWithRegex("Run", dir, grepOptions{fileGlobs: grepFileTypes["go"]})
+		require.NoError(t, err)
+		require.Len(t, matches, 2)
+		assert.Equal(t, filepath.Join(dir, "main.go"), matches[0].path)
+		assert.Equal(t, 4, matches[0].lineNum)
+		assert.Equal(t, 7, matches[1].lineNum)
+	})
+
+	t.Run("multiline", func(t *testing.T) {
+		matches, err := searchFilesWithRegex(`main\(\) \{\n\s+Run`, dir, grepOptions{multiline: true})
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		assert.Equal(t, 3, matches[0].lineNum)
+		assert.Equal(t, "func main() {\n\tRun()", matches[0].lineText)
+	})
+}
+
+func TestFormatGrepContent(t *testing.T) {
+	dir := writeGrepFiles(t)
+	path := filepath.Join(dir, "main.go")
+	matches := []grepMatch{
+		{path: path, lineNum: 3, lineText: "func main() {"},
+		{path: path, lineNum: 7, lineText: "func Run() {}"},
+	}
+
+	assert.Equal(t, path+":\n  3: func main() {\n  7: func Run() {}", formatGrepContent(matches, 0, 0))
+	assert.Equal(t, path+":\n  3: func main() {\n  4- \tRun()\n  --\n  7: func Run() {}", formatGrepContent(matches, 0, 1))
+	assert.Equal(t, path+":\n  2- \n  3: func main() {\n  4- \tRun()\n  5- }\n  6- \n  7: func Run() {}", formatGrepContent(matches, 1, 2))
+}
+
+func TestMatchedFiles(t *testing.T) {
+	matches := []grepMatch{
+		{path: "a.go", lineNum: 1},
+		{path: "a.go", lineNum: 5},
+		{path: "b.go", lineNum: 2},
+	}
+	assert.Equal(t, []grepFile{{path: "a.go", count: 2}, {path: "b.go", count: 1}}, matchedFiles(matches))
+}
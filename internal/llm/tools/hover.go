@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/opencode-ai/opencode/internal/lsp"
+	"github.com/opencode-ai/opencode/internal/lsp/protocol"
+)
+
+type hoverTool struct {
+	lspClients map[string]*lsp.Client
+}
+
+const (
+	HoverToolName    = "hover"
+	hoverDescription = `Shows the type, signature and documentation of a symbol using the language servers.
+
+WHEN TO USE THIS TOOL:
+- Use to learn the type of a variable or expression, or the signature of a function
+- Use to read the documentation of a library symbol without opening its source
+
+HOW TO USE:
+- Pass location as file:line:column of the symbol, or
+- Pass symbol with its name (e.g. OpenFile or Client.OpenFile) to search the workspace
+- Pass both location (file:line) and symbol to look the name up on that line
+
+FEATURES:
+- Returns what an editor shows when hovering the symbol
+
+LIMITATIONS:
+- Only works for languages with a configured language server
+- The amount of detail depends on the language server`
+)
+
+func NewHoverTool(lspClients map[string]*lsp.Client) BaseTool {
+	return &hoverTool{
+		lspClients,
+	}
+}
+
+func (h *hoverTool) Info() ToolInfo {
+	return ToolInfo{
+		Name:        HoverToolName,
+		Description: hoverDescription,
+		Parameters:  navigationParameters,
+		Required:    []string{},
+	}
+}
+
+func (h *hoverTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error) {
+	var params NavigationParams
+	if err := json.Unmarshal([]byte(call.Input), &params); err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("error parsing parameters: %s", err)), nil
+	}
+	if len(h.lspClients) == 0 {
+		return NewTextErrorResponse("no LSP clients available"), nil
+	}
+
+	target, others, err := resolveNavigationTarget(ctx, h.lspClients, params)
+	if err != nil {
+		return NewTextErrorResponse(err.Error()), nil
+	}
+
+	contents, err := queryClients(h.lspClients, func(client *lsp.Client) ([]string, error) {
+		result, err := client.Hover(ctx, protocol.HoverParams{
+			TextDocumentPositionParams: target.textDocumentPosition(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if text := strings.TrimSpace(result.Contents.Value); text != "" {
+			return []string{text}, nil
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("error getting hover information: %s", err)), nil
+	}
+
+	lines := sourceLines{}
+	location := lines.format(target.path, target.position)
+	if len(contents) == 0 {
+		return NewTextResponse(fmt.Sprintf("No information found at %s", location)), nil
+	}
+	return NewTextResponse(location + "\n\n" + contents[0] + otherMatchesNote(target, others)), nil
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/opencode-ai/opencode/internal/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -24,6 +25,10 @@ func TestLsTool_Info(t *testing.T) {
 }
 
 func TestLsTool_Run(t *testing.T) {
+	// The tool resolves relative paths against the configured working directory
+	cfg, err := config.Load(t.TempDir(), false)
+	require.NoError(t, err)
+
 	// Create a temporary directory for testing
 	tempDir, err := os.MkdirTemp("", "ls_tool_test")
 	require.NoError(t, err)
@@ -194,6 +199,11 @@ func TestLsTool_Run(t *testing.T) {
 		parentDir := filepath.Dir(tempDir)
 		err = os.Chdir(parentDir)
 		require.NoError(t, err)
+		origWorkingDir := cfg.WorkingDir
+		cfg.WorkingDir = parentDir
+		defer func() {
+			cfg.WorkingDir = origWorkingDir
+		}()
 		
 		tool := NewLsTool()
 		params := LSParams{
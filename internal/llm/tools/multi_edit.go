@@ -0,0 +1,267 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/diff"
+	"github.com/opencode-ai/opencode/internal/history"
+	"github.com/opencode-ai/opencode/internal/logging"
+	"github.com/opencode-ai/opencode/internal/lsp"
+	"github.com/opencode-ai/opencode/internal/permission"
+)
+
+type MultiEditOperation struct {
+	OldString string `json:"old_string"`
+	NewString string `json:"new_string"`
+}
+
+type MultiEditParams struct {
+	FilePath string               `json:"file_path"`
+	Edits    []MultiEditOperation `json:"edits"`
+}
+
+type multiEditTool struct {
+	lspClients  map[string]*lsp.Client
+	permissions permission.Service
+	files       history.Service
+}
+
+const (
+	MultiEditToolName    = "multi_edit"
+	multiEditDescription = `Makes several text replacements in one file at once. The edits are applied in order, and either all of them are applied or none are. Prefer it over the Edit tool when changing several places in the same file.
+
+Before using this tool:
+
+1. Use the FileRead tool to understand the file's contents and context
+
+2. Plan every change to the file so they can be made in one call
+
+To make the edits, provide the following:
+1. file_path: The absolute path to the file to modify (must be absolute, not relative)
+2. edits: A list of edits, each with:
+   - old_string: The text to replace (must be unique within the file at the time the edit is applied, and must match exactly, including all whitespace and indentation)
+   - new_string: The edited text to replace the old_string
+
+HOW THE EDITS ARE APPLIED:
+- Each edit is applied to the result of the edits before it, so an old_string must match the file as the earlier edits left it
+- If any edit fails, for example because its old_string is not found or is not unique, no change is made to the file and the failing edit is reported
+- The user approves all the edits at once with a single combined diff
+
+CRITICAL REQUIREMENTS FOR USING THIS TOOL:
+
+1. UNIQUENESS: Each old_string MUST uniquely identify the text to change, so include enough surrounding lines
+2. ORDER: Don't let an edit change text that a later edit's old_string needs to match
+3. NO NEW FILES: This tool only edits existing files, use the Write tool to create files
+
+When making edits:
+   - Ensure the edits result in idiomatic, correct code
+   - Do not leave the code in a broken state
+   - Always use absolute file paths (starting with /)`
+)
+
+func NewMultiEditTool(lspClients map[string]*lsp.Client, permissions permission.Service, files history.Service) BaseTool {
+	return &multiEditTool{
+		lspClients:  lspClients,
+		permissions: permissions,
+		files:       files,
+	}
+}
+
+func (m *multiEditTool) Info() ToolInfo {
+	return ToolInfo{
+		Name:        MultiEditToolName,
+		Description: multiEditDescription,
+		Parameters: map[string]any{
+			"file_path": map[string]any{
+				"type":        "string",
+				"description": "The absolute path to the file to modify",
+			},
+			"edits": map[string]any{
+				"type":        "array",
+				"description": "The edits to apply in order",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"old_string": map[string]any{
+							"type":        "string",
+							"description": "The text to replace",
+						},
+						"new_string": map[string]any{
+							"type":        "string",
+							"description": "The text to replace it with",
+						},
+					},
+					"required": []string{"old_string", "new_string"},
+				},
+			},
+		},
+		Required: []string{"file_path", "edits"},
+	}
+}
+
+func (m *multiEditTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error) {
+	var params MultiEditParams
+	if err := json.Unmarshal([]byte(call.Input), &params); err != nil {
+		return NewTextErrorResponse("invalid parameters"), nil
+	}
+
+	if params.FilePath == "" {
+		return NewTextErrorResponse("file_path is required"), nil
+	}
+	if len(params.Edits) == 0 {
+		return NewTextErrorResponse("edits are required"), nil
+	}
+
+	filePath := params.FilePath
+	if !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(config.WorkingDirectory(), filePath)
+	}
+
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewTextErrorResponse(fmt.Sprintf("file not found: %s. Use the Write tool to create files", filePath)), nil
+		}
+		return ToolResponse{}, fmt.Errorf("failed to access file: %w", err)
+	}
+
+	if fileInfo.IsDir() {
+		return NewTextErrorResponse(fmt.Sprintf("path is a directory, not a file: %s", filePath)), nil
+	}
+
+	if getLastReadTime(filePath).IsZero() {
+		return NewTextErrorResponse("you must read the file before editing it. Use the View tool first"), nil
+	}
+
+	modTime := fileInfo.ModTime()
+	lastRead := getLastReadTime(filePath)
+	if modTime.After(lastRead) {
+		return NewTextErrorResponse(
+			fmt.Sprintf("file %s has been modified since it was last read (mod time: %s, last read: %s)",
+				filePath, modTime.Format(time.RFC3339), lastRead.Format(time.RFC3339),
+			)), nil
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return ToolResponse{}, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	oldContent := string(content)
+	newContent, err := applyEdits(oldContent, params.Edits)
+	if err != nil {
+		return NewTextErrorResponse(err.Error() + ". No edits were applied"), nil
+	}
+	if oldContent == newContent {
+		return NewTextErrorResponse("new content is the same as old content. No changes made."), nil
+	}
+
+	sessionID, messageID := GetContextValues(ctx)
+	if sessionID == "" || messageID == "" {
+		return ToolResponse{}, fmt.Errorf("session ID and message ID are required for editing a file")
+	}
+
+	diff, additions, removals := diff.GenerateDiff(
+		oldContent,
+		newContent,
+		filePath,
+	)
+	rootDir := config.WorkingDirectory()
+	permissionPath := filepath.Dir(filePath)
+	if strings.HasPrefix(filePath, rootDir) {
+		permissionPath = rootDir
+	}
+	p := m.permissions.Request(
+		permission.CreatePermissionRequest{
+			SessionID:   sessionID,
+			Path:        permissionPath,
+			ToolName:    MultiEditToolName,
+			Action:      "write",
+			Description: fmt.Sprintf("Apply %d edits to file %s", len(params.Edits), filePath),
+			Params: EditPermissionsParams{
+				FilePath: filePath,
+				Diff:     diff,
+			},
+		},
+	)
+	if !p {
+		return ToolResponse{}, permission.ErrorPermissionDenied
+	}
+
+	err = os.WriteFile(filePath, []byte(newContent), 0o644)
+	if err != nil {
+		return ToolResponse{}, fmt.Errorf("failed to write file: %w", err)
+	}
+
+	formatted := formatOnWrite(ctx, filePath, oldContent, newContent, m.lspClients)
+	if formatted.note != "" {
+		newContent = formatted.content
+		diff, additions, removals = formatted.diff, formatted.additions, formatted.removals
+	}
+
+	// Check if file exists in history
+	file, err := m.files.GetByPathAndSession(ctx, filePath, sessionID)
+	if err != nil {
+		_, err = m.files.Create(ctx, sessionID, filePath, oldContent)
+		if err != nil {
+			return ToolResponse{}, fmt.Errorf("error creating file history: %w", err)
+		}
+	}
+	if file.Content != oldContent {
+		// User Manually changed the content store an intermediate version
+		_, err = m.files.CreateVersion(ctx, sessionID, filePath, oldContent)
+		if err != nil {
+			logging.Debug("Error creating file history version", "error", err)
+		}
+	}
+	// All the edits are stored as one version
+	_, err = m.files.CreateVersion(ctx, sessionID, filePath, newContent)
+	if err != nil {
+		logging.Debug("Error creating file history version", "error", err)
+	}
+
+	recordFileWrite(filePath)
+	recordFileRead(filePath)
+
+	waitForLspDiagnostics(ctx, filePath, m.lspClients)
+	text := fmt.Sprintf("<result>\n%s\n</result>\n", formatted.withNote(fmt.Sprintf("Applied %d edits to file: %s", len(params.Edits), filePath)))
+	text += getDiagnostics(filePath, m.lspClients)
+	return WithResponseMetadata(
+		NewTextResponse(text),
+		EditResponseMetadata{
+			Diff:      diff,
+			Additions: additions,
+			Removals:  removals,
+		},
+	), nil
+}
+
+// applyEdits applies the edits in order and fails on the first one that
+// can't be applied, naming it by its position.
+func applyEdits(content string, edits []MultiEditOperation) (string, error) {
+	for i, edit := range edits {
+		n := i + 1
+		if edit.OldString == "" {
+			return "", fmt.Errorf("edit %d: old_string is required, use the Write tool to create files", n)
+		}
+		if edit.OldString == edit.NewString {
+			return "", fmt.Errorf("edit %d: old_string and new_string are the same", n)
+		}
+		index := strings.Index(content, edit.OldString)
+		if index == -1 {
+			return "", fmt.Errorf("edit %d: old_string not found in file after applying the edits before it. Make sure it matches exactly, including whitespace and line breaks", n)
+		}
+		if strings.LastIndex(content, edit.OldString) != index {
+			return "", fmt.Errorf("edit %d: old_string appears multiple times in the file. Please provide more context to ensure a unique match", n)
+		}
+		content = content[:index] + edit.NewString + content[index+len(edit.OldString):]
+	}
+	return content, nil
+}
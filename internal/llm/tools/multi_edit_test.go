@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyEdits(t *testing.T) {
+	content := "func a() {}\nfunc b() {}\nfunc c() {}\n"
+
+	t.Run("applies edits in order", func(t *testing.T) {
+		got, err := applyEdits(content, []MultiEditOperation{
+			{OldString: "func a()", NewString: "func first()"},
+			{OldString: "func first() {}", NewString: "func first() { b() }"},
+			{OldString: "func c() {}\n", NewString: ""},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "func first() { b() }\nfunc b() {}\n", got)
+	})
+
+	t.Run("fails on the first bad edit", func(t *testing.T) {
+		_, err := applyEdits(content, []MultiEditOperation{
+			{OldString: "func a()", NewString: "func first()"},
+			{OldString: "func a()", NewString: "func again()"},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "edit 2: old_string not found")
+	})
+
+	t.Run("rejects ambiguous edits", func(t *testing.T) {
+		_, err := applyEdits(content, []MultiEditOperation{
+			{OldString: "() {}", NewString: "() { return }"},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "edit 1: old_string appears multiple times")
+	})
+
+	t.Run("rejects empty old_string", func(t *testing.T) {
+		_, err := applyEdits(content, []MultiEditOperation{{NewString: "x"}})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,444 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/lsp"
+	"github.com/opencode-ai/opencode/internal/lsp/protocol"
+)
+
+// NavigationParams point the navigation tools at a symbol, either by position
+// or by name. When both are set the symbol is looked up on the given line.
+type NavigationParams struct {
+	Location string `json:"location"`
+	Symbol   string `json:"symbol"`
+}
+
+const (
+	maxNavigationResults = 100
+	maxNavigationLineLen = 200
+)
+
+var navigationParameters = map[string]any{
+	"location": map[string]any{
+		"type":        "string",
+		"description": "Position of the symbol as file:line:column (1-based). The column can be left out when symbol is set, the symbol is then looked up on that line",
+	},
+	"symbol": map[string]any{
+		"type":        "string",
+		"description": "Name of the symbol, optionally qualified with its container (e.g. Client.OpenFile). Used to search the workspace when no location is given",
+	},
+}
+
+// navigationTarget is a position in a file the language servers are queried
+// at.
+type navigationTarget struct {
+	path     string
+	position protocol.Position
+	// Set when the target was found by name
+	symbol *symbolMatch
+}
+
+func (t navigationTarget) textDocumentPosition() protocol.TextDocumentPositionParams {
+	return protocol.TextDocumentPositionParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: fileURI(t.path)},
+		Position:     t.position,
+	}
+}
+
+type symbolMatch struct {
+	name      string
+	kind      protocol.SymbolKind
+	container string
+	path      string
+	rng       protocol.Range
+}
+
+func fileURI(path string) protocol.DocumentUri {
+	// Same form as lsp.Client.OpenFile so the servers see the open document
+	return protocol.DocumentUri(fmt.Sprintf("file://%s", path))
+}
+
+// resolveNavigationTarget turns the tool parameters into a position. Targets
+// found by name also return the other symbols with the same name so the
+// caller can mention them.
+func resolveNavigationTarget(ctx context.Context, lsps map[string]*lsp.Client, params NavigationParams) (navigationTarget, []symbolMatch, error) {
+	if params.Location == "" && params.Symbol == "" {
+		return navigationTarget{}, nil, errors.New("location or symbol is required")
+	}
+
+	if params.Location != "" {
+		path, line, column, err := parseLocation(params.Location)
+		if err != nil {
+			return navigationTarget{}, nil, err
+		}
+		lines, err := readSourceLines(path)
+		if err != nil {
+			return navigationTarget{}, nil, err
+		}
+		if line > len(lines) {
+			return navigationTarget{}, nil, fmt.Errorf("%s has only %d lines", relativePath(path), len(lines))
+		}
+		text := lines[line-1]
+
+		var character uint32
+		switch {
+		case column > 0:
+			character = utf16Column(text, column-1)
+		case params.Symbol != "":
+			index := findIdentifier(text, symbolName(params.Symbol))
+			if index < 0 {
+				return navigationTarget{}, nil, fmt.Errorf("%s not found on line %d of %s", params.Symbol, line, relativePath(path))
+			}
+			character = utf16Column(text, utf8.RuneCountInString(text[:index]))
+		default:
+			indent := len(text) - len(strings.TrimLeftFunc(text, unicode.IsSpace))
+			character = utf16Column(text, utf8.RuneCountInString(text[:indent]))
+		}
+
+		notifyLspOpenFile(ctx, path, lsps)
+		return navigationTarget{
+			path:     path,
+			position: protocol.Position{Line: uint32(line - 1), Character: character},
+		}, nil, nil
+	}
+
+	matches, err := findSymbols(ctx, lsps, params.Symbol)
+	if err != nil {
+		return navigationTarget{}, nil, err
+	}
+	if len(matches) == 0 {
+		return navigationTarget{}, nil, fmt.Errorf("no symbol named %s found in the workspace, use the symbols tool to search for similar names", params.Symbol)
+	}
+
+	match := matches[0]
+	position := match.rng.Start
+	// Workspace symbols may span the whole declaration, point at the name
+	if lines, err := readSourceLines(match.path); err == nil && int(position.Line) < len(lines) {
+		text := lines[position.Line]
+		start := byteOffset(text, position.Character)
+		if index := findIdentifier(text[start:], match.name); index >= 0 {
+			position.Character = utf16Column(text, utf8.RuneCountInString(text[:start+index]))
+		}
+	}
+
+	notifyLspOpenFile(ctx, match.path, lsps)
+	return navigationTarget{path: match.path, position: position, symbol: &match}, matches[1:], nil
+}
+
+// parseLocation parses file:line[:column]. Relative paths are resolved
+// against the working directory.
+func parseLocation(location string) (path string, line, column int, err error) {
+	parts := strings.Split(location, ":")
+	var numbers []int
+	for len(parts) > 1 && len(numbers) < 2 {
+		n, convErr := strconv.Atoi(parts[len(parts)-1])
+		if convErr != nil {
+			break
+		}
+		numbers = append([]int{n}, numbers...)
+		parts = parts[:len(parts)-1]
+	}
+	path = strings.Join(parts, ":")
+	if path == "" || len(numbers) == 0 {
+		return "", 0, 0, fmt.Errorf("invalid location %q, expected file:line or file:line:column", location)
+	}
+	line = numbers[0]
+	if len(numbers) > 1 {
+		column = numbers[1]
+	}
+	if line < 1 || (len(numbers) > 1 && column < 1) {
+		return "", 0, 0, fmt.Errorf("invalid location %q, lines and columns start at 1", location)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(config.WorkingDirectory(), path)
+	}
+	return path, line, column, nil
+}
+
+// findSymbols searches the workspace for symbols with the given name. A
+// qualified name like Client.OpenFile also has to match the container.
+func findSymbols(ctx context.Context, lsps map[string]*lsp.Client, name string) ([]symbolMatch, error) {
+	container := ""
+	if i := strings.LastIndexAny(name, ".:"); i > 0 {
+		container = strings.TrimRight(name[:i], ".:")
+	}
+	short := symbolName(name)
+
+	all, err := searchWorkspaceSymbols(ctx, lsps, short)
+	if err != nil {
+		return nil, err
+	}
+	var matches []symbolMatch
+	for _, match := range all {
+		if match.name != short && !strings.HasPrefix(match.name, short+"(") {
+			continue
+		}
+		if container != "" && !strings.Contains(match.container, container) && !strings.HasSuffix(match.name, container) {
+			continue
+		}
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
+
+// searchWorkspaceSymbols asks every language server and merges the results.
+func searchWorkspaceSymbols(ctx context.Context, lsps map[string]*lsp.Client, query string) ([]symbolMatch, error) {
+	var matches []symbolMatch
+	var errs []error
+	for _, name := range sortedClientNames(lsps) {
+		result, err := lsps[name].Symbol(ctx, protocol.WorkspaceSymbolParams{Query: query})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		switch symbols := result.Value.(type) {
+		case []protocol.SymbolInformation:
+			for _, symbol := range symbols {
+				matches = append(matches, symbolMatch{
+					name:      symbol.Name,
+					kind:      symbol.Kind,
+					container: symbol.ContainerName,
+					path:      symbol.Location.URI.Path(),
+					rng:       symbol.Location.Range,
+				})
+			}
+		case []protocol.WorkspaceSymbol:
+			for _, symbol := range symbols {
+				match := symbolMatch{
+					name:      symbol.Name,
+					kind:      symbol.Kind,
+					container: symbol.ContainerName,
+				}
+				switch location := symbol.Location.Value.(type) {
+				case protocol.Location:
+					match.path = location.URI.Path()
+					match.rng = location.Range
+				case protocol.LocationUriOnly:
+					match.path = location.URI.Path()
+				}
+				matches = append(matches, match)
+			}
+		}
+	}
+	if len(matches) == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return matches, nil
+}
+
+// queryClients runs a request against each language server until one returns
+// something. Servers that fail are only reported when none succeeds.
+func queryClients[T any](lsps map[string]*lsp.Client, request func(client *lsp.Client) ([]T, error)) ([]T, error) {
+	var errs []error
+	for _, name := range sortedClientNames(lsps) {
+		result, err := request(lsps[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		if len(result) > 0 {
+			return result, nil
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+func sortedClientNames(lsps map[string]*lsp.Client) []string {
+	names := make([]string, 0, len(lsps))
+	for name := range lsps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sourceLines caches file contents while one result is formatted.
+type sourceLines map[string][]string
+
+func (s sourceLines) line(path string, line uint32) string {
+	lines, ok := s[path]
+	if !ok {
+		lines, _ = readSourceLines(path)
+		s[path] = lines
+	}
+	if int(line) >= len(lines) {
+		return ""
+	}
+	return lines[line]
+}
+
+// position renders a position as path:line:column.
+func (s sourceLines) position(path string, position protocol.Position) string {
+	text := s.line(path, position.Line)
+	return fmt.Sprintf("%s:%d:%d", relativePath(path), position.Line+1, runeColumn(text, position.Character)+1)
+}
+
+// format renders a position as path:line:column: source line.
+func (s sourceLines) format(path string, position protocol.Position) string {
+	location := s.position(path, position)
+	text := strings.TrimSpace(s.line(path, position.Line))
+	if text == "" {
+		return location
+	}
+	if utf8.RuneCountInString(text) > maxNavigationLineLen {
+		text = string([]rune(text)[:maxNavigationLineLen]) + "..."
+	}
+	return location + ": " + text
+}
+
+func readSourceLines(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("file not found: %s", path)
+		}
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+	return strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n"), nil
+}
+
+// formatLocations renders locations sorted by file and position, one per
+// line, and notes how many were left out.
+func formatLocations(locations []protocol.Location) string {
+	sort.Slice(locations, func(i, j int) bool {
+		a, b := locations[i], locations[j]
+		if a.URI != b.URI {
+			return a.URI < b.URI
+		}
+		if a.Range.Start.Line != b.Range.Start.Line {
+			return a.Range.Start.Line < b.Range.Start.Line
+		}
+		return a.Range.Start.Character < b.Range.Start.Character
+	})
+
+	lines := sourceLines{}
+	var output []string
+	for i, location := range locations {
+		if i == maxNavigationResults {
+			output = append(output, fmt.Sprintf("... and %d more", len(locations)-maxNavigationResults))
+			break
+		}
+		output = append(output, lines.format(location.URI.Path(), location.Range.Start))
+	}
+	return strings.Join(output, "\n")
+}
+
+// otherMatchesNote tells the model which symbol was picked when several have
+// the same name.
+func otherMatchesNote(target navigationTarget, others []symbolMatch) string {
+	if target.symbol == nil || len(others) == 0 {
+		return ""
+	}
+	lines := sourceLines{}
+	note := fmt.Sprintf("\n\nUsed %s at %s. %d other symbol(s) have the same name, pass a location to pick one:", describeSymbol(*target.symbol), lines.format(target.path, target.position), len(others))
+	for i, other := range others {
+		if i == 10 {
+			note += fmt.Sprintf("\n... and %d more", len(others)-10)
+			break
+		}
+		note += "\n" + lines.format(other.path, other.rng.Start)
+	}
+	return note
+}
+
+func describeSymbol(match symbolMatch) string {
+	name := match.name
+	if match.container != "" {
+		name = match.container + "." + name
+	}
+	if kind, ok := protocol.TableKindMap[match.kind]; ok {
+		return strings.ToLower(kind) + " " + name
+	}
+	return name
+}
+
+// symbolName returns the last part of a qualified name.
+func symbolName(name string) string {
+	if i := strings.LastIndexAny(name, ".:"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// findIdentifier returns the byte index of name in text where it is not part
+// of a longer identifier, or -1.
+func findIdentifier(text, name string) int {
+	if name == "" {
+		return -1
+	}
+	for offset := 0; offset < len(text); {
+		index := strings.Index(text[offset:], name)
+		if index < 0 {
+			return -1
+		}
+		start := offset + index
+		end := start + len(name)
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (start == 0 || !isIdentifierRune(before)) && (end == len(text) || !isIdentifierRune(after)) {
+			return start
+		}
+		offset = start + 1
+	}
+	return -1
+}
+
+func isIdentifierRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// LSP positions count UTF-16 code units, the tools count characters.
+
+func utf16Column(text string, runes int) uint32 {
+	var units uint32
+	for i, r := range []rune(text) {
+		if i == runes {
+			break
+		}
+		units += uint32(utf16.RuneLen(r))
+	}
+	return units
+}
+
+func runeColumn(text string, units uint32) int {
+	var count uint32
+	column := 0
+	for _, r := range text {
+		if count >= units {
+			break
+		}
+		count += uint32(utf16.RuneLen(r))
+		column++
+	}
+	return column
+}
+
+func byteOffset(text string, units uint32) int {
+	var count uint32
+	for i, r := range text {
+		if count >= units {
+			return i
+		}
+		count += uint32(utf16.RuneLen(r))
+	}
+	return len(text)
+}
+
+func relativePath(path string) string {
+	if rel, err := filepath.Rel(config.WorkingDirectory(), path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
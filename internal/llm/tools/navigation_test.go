@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/opencode-ai/opencode/internal/lsp/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLocation(t *testing.T) {
+	path, line, column, err := parseLocation("/src/main.go:12:5")
+	require.NoError(t, err)
+	assert.Equal(t, "/src/main.go", path)
+	assert.Equal(t, 12, line)
+	assert.Equal(t, 5, column)
+
+	path, line, column, err = parseLocation("/src/main.go:12")
+	require.NoError(t, err)
+	assert.Equal(t, "/src/main.go", path)
+	assert.Equal(t, 12, line)
+	assert.Equal(t, 0, column)
+
+	for _, location := range []string{"/src/main.go", "/src/main.go:0:1", "/src/main.go:3:0", ":3"} {
+		_, _, _, err := parseLocation(location)
+		assert.Error(t, err, location)
+	}
+}
+
+func TestFindIdentifier(t *testing.T) {
+	assert.Equal(t, 17, findIdentifier("func (c *Client) OpenFile(ctx)", "OpenFile"))
+	assert.Equal(t, 17, findIdentifier("	x := openFile + open", "open"))
+	assert.Equal(t, -1, findIdentifier("OpenFileOnDemand()", "OpenFile"))
+}
+
+func TestColumns(t *testing.T) {
+	// é is one UTF-16 unit, 😀 is two
+	text := "é😀x := 1"
+	assert.Equal(t, uint32(3), utf16Column(text, 2))
+	assert.Equal(t, 2, runeColumn(text, 3))
+	assert.Equal(t, len("é😀"), byteOffset(text, 3))
+}
+
+func TestDefinitionLocations(t *testing.T) {
+	location := protocol.Location{URI: "file:///a.go", Range: protocol.Range{Start: protocol.Position{Line: 3}}}
+	assert.Equal(t, []protocol.Location{location}, definitionLocations(protocol.Or_Result_textDocument_definition{
+		Value: protocol.Definition{Value: location},
+	}))
+
+	links := []protocol.DefinitionLink{{TargetURI: location.URI, TargetRange: protocol.Range{}, TargetSelectionRange: location.Range}}
+	assert.Equal(t, []protocol.Location{location}, definitionLocations(protocol.Or_Result_textDocument_definition{Value: links}))
+}
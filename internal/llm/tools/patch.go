@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/opencode-ai/opencode/internal/config"
@@ -286,6 +287,7 @@ func (p *patchTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error
 
 	// Update file history for all modified files
 	changedFiles := []string{}
+	formatNotes := []string{}
 	totalAdditions := 0
 	totalRemovals := 0
 
@@ -307,6 +309,14 @@ func (p *patchTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error
 			newContent = *change.NewContent
 		}
 
+		if change.Type != diff.ActionDelete {
+			formatted := formatOnWrite(ctx, absPath, oldContent, newContent, p.lspClients)
+			if formatted.note != "" {
+				newContent = formatted.content
+				formatNotes = append(formatNotes, fmt.Sprintf("%s: %s", path, formatted.note))
+			}
+		}
+
 		// Calculate diff statistics
 		_, additions, removals := diff.GenerateDiff(oldContent, newContent, path)
 		totalAdditions += additions
@@ -352,6 +362,9 @@ func (p *patchTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error
 
 	result := fmt.Sprintf("Patch applied successfully. %d files changed, %d additions, %d removals",
 		len(changedFiles), totalAdditions, totalRemovals)
+	if len(formatNotes) > 0 {
+		result += "\n" + strings.Join(formatNotes, "\n")
+	}
 
 	diagnosticsText := ""
 	for _, filePath := range changedFiles {
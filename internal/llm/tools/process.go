@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opencode-ai/opencode/internal/llm/tools/shell"
+)
+
+type ProcessParams struct {
+	Action string `json:"action"`
+	Name   string `json:"name"`
+	Wait   int    `json:"wait"`
+}
+
+type processTool struct {
+	processes shell.BackgroundService
+}
+
+const (
+	ProcessToolName    = "process"
+	maxProcessWait     = 60 * 1000
+	processDescription = `Manages the background processes started with the bash tool.
+
+WHEN TO USE THIS TOOL:
+- Use to read the output of a dev server, watcher or long build started with background set to true
+- Use to check whether a background process is still running, or to stop it
+
+HOW TO USE:
+- action "list" shows the background processes of this session
+- action "output" with a name returns the output written since the last read, and whether the process is still running
+- action "kill" with a name stops the process and everything it started, and returns its remaining output
+- Optionally set wait (in milliseconds) with "output" to wait for the process to exit first, up to 60000
+
+FEATURES:
+- stdout and stderr are combined in the order they were written
+- Only output you haven't read yet is returned, so calling it again shows what is new
+
+LIMITATIONS:
+- Only the last megabyte of output is kept
+- Processes are stopped when the application exits
+
+TIPS:
+- Stop servers you no longer need, e.g. before starting them again with changes`
+)
+
+func NewProcessTool(processes shell.BackgroundService) BaseTool {
+	return &processTool{
+		processes: processes,
+	}
+}
+
+func (p *processTool) Info() ToolInfo {
+	return ToolInfo{
+		Name:        ProcessToolName,
+		Description: processDescription,
+		Parameters: map[string]any{
+			"action": map[string]any{
+				"type":        "string",
+				"description": "What to do: list, output or kill",
+				"enum":        []string{"list", "output", "kill"},
+			},
+			"name": map[string]any{
+				"type":        "string",
+				"description": "The name of the background process (required for output and kill)",
+			},
+			"wait": map[string]any{
+				"type":        "number",
+				"description": "Milliseconds to wait for the process to exit before reading its output (optional)",
+			},
+		},
+		Required: []string{"action"},
+	}
+}
+
+func (p *processTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error) {
+	var params ProcessParams
+	if err := json.Unmarshal([]byte(call.Input), &params); err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("error parsing parameters: %s", err)), nil
+	}
+	sessionID, _ := GetContextValues(ctx)
+	if sessionID == "" {
+		return ToolResponse{}, fmt.Errorf("session ID is required")
+	}
+
+	switch params.Action {
+	case "list":
+		processes := p.processes.List(sessionID)
+		if len(processes) == 0 {
+			return NewTextResponse("No background processes"), nil
+		}
+		lines := make([]string, 0, len(processes))
+		for _, process := range processes {
+			lines = append(lines, fmt.Sprintf("%s (%s): %s", process.Name, processStatus(process), process.Command))
+		}
+		return NewTextResponse(strings.Join(lines, "\n")), nil
+	case "output", "kill":
+		if params.Name == "" {
+			return NewTextErrorResponse("name is required"), nil
+		}
+	default:
+		return NewTextErrorResponse(fmt.Sprintf("unknown action %q, use list, output or kill", params.Action)), nil
+	}
+
+	var process shell.BackgroundProcess
+	var output string
+	var err error
+	if params.Action == "kill" {
+		process, output, err = p.processes.Kill(sessionID, params.Name)
+	} else {
+		if params.Wait > 0 {
+			wait := min(params.Wait, maxProcessWait)
+			if _, err := p.processes.Wait(ctx, sessionID, params.Name, time.Duration(wait)*time.Millisecond); err != nil {
+				return NewTextErrorResponse(err.Error()), nil
+			}
+		}
+		process, output, err = p.processes.ReadOutput(sessionID, params.Name)
+	}
+	if err != nil {
+		return NewTextErrorResponse(err.Error()), nil
+	}
+
+	result := fmt.Sprintf("Process %s: %s", process.Name, processStatus(process))
+	if params.Action == "kill" {
+		result = fmt.Sprintf("Stopped process %s: %s", process.Name, processStatus(process))
+	}
+	if output = SpillOutput(ctx, ProcessToolName, output); output != "" {
+		result += "\n\n" + output
+	} else {
+		result += "\n\nNo new output"
+	}
+	return NewTextResponse(result), nil
+}
+
+func processStatus(process shell.BackgroundProcess) string {
+	if process.Running {
+		return fmt.Sprintf("running, pid %d", process.PID)
+	}
+	return fmt.Sprintf("exited with code %d", process.ExitCode)
+}
@@ -0,0 +1,352 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/logging"
+)
+
+type ReadOutputParams struct {
+	ID      string `json:"id"`
+	Offset  int    `json:"offset,omitempty"`
+	Limit   int    `json:"limit,omitempty"`
+	Pattern string `json:"pattern,omitempty"`
+}
+
+type readOutputTool struct{}
+
+const (
+	ReadOutputToolName = "read_output"
+	// outputPreviewLength is how much of the start and of the end of a
+	// saved output the model gets right away.
+	outputPreviewLength    = MaxOutputLength / 4
+	defaultReadOutputLimit = 500
+	defaultOutputMatches   = 100
+	savedOutputMaxAge      = 7 * 24 * time.Hour
+	readOutputDescription  = `Reads the full output of a command that was too long to return at once.
+
+WHEN TO USE THIS TOOL:
+- Use when a tool result ends with a note that the output was saved, like "saved as bash-1"
+- Use to look at the part of a long output that was not shown, like the middle of a build log
+
+HOW TO USE:
+- Provide the id from the note
+- Set offset to the first line to read (1-based) and limit to the number of lines (default 500)
+- Or set pattern to a regular expression to list only the matching lines with their line numbers, offset then is the line to start searching from
+- Leave id empty to list the saved outputs of this session
+
+FEATURES:
+- Outputs of bash, process, git and MCP tools longer than 30000 characters are saved in full
+- Lines are numbered, so you can search first and then read around a match
+
+LIMITATIONS:
+- Only outputs of the current session can be read
+- Saved outputs are removed after a week
+- Lines longer than 2000 characters are shortened
+
+TIPS:
+- Search for "error", "FAIL" or "panic" to find the relevant part of a long log, then read the lines around it`
+)
+
+var outputIDPattern = regexp.MustCompile(`^[a-z0-9_]+-[0-9]+$`)
+
+func NewReadOutputTool() BaseTool {
+	return &readOutputTool{}
+}
+
+// savedOutputDir is the scratch folder with the saved outputs of a session.
+func savedOutputDir(sessionID string) (string, bool) {
+	cfg := config.Get()
+	if cfg == nil || sessionID == "" {
+		return "", false
+	}
+	return filepath.Join(cfg.Data.Directory, "scratch", sessionID), true
+}
+
+// SpillOutput returns content unchanged when it fits in a tool result.
+// Longer content is saved in the scratch folder of the session and replaced
+// by its start and end, with a note on how to read the rest with the
+// read_output tool. Without a session or when saving fails the middle is
+// dropped, and the result says so.
+func SpillOutput(ctx context.Context, name, content string) string {
+	if len(content) <= MaxOutputLength {
+		return content
+	}
+	sessionID, _ := GetContextValues(ctx)
+	dir, ok := savedOutputDir(sessionID)
+	if !ok {
+		return truncateOutput(content)
+	}
+	id, err := saveOutput(dir, name, content)
+	if err != nil {
+		logging.Warn("Failed to save long tool output", "tool", name, "error", err)
+		return truncateOutput(content)
+	}
+	return outputPreview(id, content)
+}
+
+// saveOutput writes content to a new file in dir and returns its id, the
+// name of the tool with a number.
+func saveOutput(dir, name, content string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	prefix := outputIDPrefix(name)
+	for n := 1; ; n++ {
+		id := fmt.Sprintf("%s-%d", prefix, n)
+		f, err := os.OpenFile(filepath.Join(dir, id+".txt"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		_, err = f.WriteString(content)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		return id, err
+	}
+}
+
+// outputIDPrefix makes a tool name, which for MCP tools can be anything,
+// safe to use in a file name.
+func outputIDPrefix(name string) string {
+	prefix := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return '_'
+	}, name)
+	if prefix == "" {
+		return "output"
+	}
+	return prefix
+}
+
+// outputPreview keeps the start and the end of a saved output, cut at line
+// breaks where there are any, and tells which lines are left out.
+func outputPreview(id, content string) string {
+	headEnd := outputPreviewLength
+	if i := strings.LastIndexByte(content[:headEnd], '\n'); i > 0 {
+		headEnd = i + 1
+	}
+	tailStart := len(content) - outputPreviewLength
+	if i := strings.IndexByte(content[tailStart:], '\n'); i >= 0 && tailStart+i+1 < len(content) {
+		tailStart += i + 1
+	}
+
+	totalLines := strings.Count(content, "\n")
+	if !strings.HasSuffix(content, "\n") {
+		totalLines++
+	}
+	firstHidden := strings.Count(content[:headEnd], "\n") + 1
+	lastHidden := strings.Count(content[:tailStart], "\n")
+
+	var hidden string
+	if firstHidden <= lastHidden {
+		hidden = fmt.Sprintf("lines %d-%d not shown", firstHidden, lastHidden)
+	} else {
+		hidden = fmt.Sprintf("%d characters not shown", tailStart-headEnd)
+	}
+	return fmt.Sprintf("%s\n... [%s] ...\n%s\n\n[The output has %d lines and was saved as %s. Use the read_output tool with id %q to read the lines that are not shown or to search them.]",
+		strings.TrimSuffix(content[:headEnd], "\n"), hidden, strings.TrimSuffix(content[tailStart:], "\n"), totalLines, id, id)
+}
+
+// PruneSavedOutputs removes the saved outputs of sessions that had no new
+// ones for a week.
+func PruneSavedOutputs() {
+	cfg := config.Get()
+	if cfg == nil {
+		return
+	}
+	root := filepath.Join(cfg.Data.Directory, "scratch")
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !entry.IsDir() || time.Since(info.ModTime()) < savedOutputMaxAge {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(root, entry.Name())); err != nil {
+			logging.Warn("Failed to remove saved tool outputs", "session", entry.Name(), "error", err)
+		}
+	}
+}
+
+func (r *readOutputTool) Info() ToolInfo {
+	return ToolInfo{
+		Name:        ReadOutputToolName,
+		Description: readOutputDescription,
+		Parameters: map[string]any{
+			"id": map[string]any{
+				"type":        "string",
+				"description": "The id of the saved output, like bash-1. Leave empty to list the saved outputs",
+			},
+			"offset": map[string]any{
+				"type":        "integer",
+				"description": "The line to start reading or searching from (1-based)",
+			},
+			"limit": map[string]any{
+				"type":        "integer",
+				"description": "The number of lines to read (default 500), or of matches to list (default 100)",
+			},
+			"pattern": map[string]any{
+				"type":        "string",
+				"description": "A regular expression, only the lines matching it are returned",
+			},
+		},
+		Required: []string{},
+	}
+}
+
+func (r *readOutputTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error) {
+	var params ReadOutputParams
+	if err := json.Unmarshal([]byte(call.Input), &params); err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("error parsing parameters: %s", err)), nil
+	}
+	sessionID, _ := GetContextValues(ctx)
+	dir, ok := savedOutputDir(sessionID)
+	if !ok {
+		return ToolResponse{}, fmt.Errorf("session ID is required for reading saved outputs")
+	}
+	if params.ID == "" {
+		return NewTextResponse(listSavedOutputs(dir)), nil
+	}
+	if !outputIDPattern.MatchString(params.ID) {
+		return NewTextErrorResponse(fmt.Sprintf("invalid id %q, use the id from the note of the saved output, like bash-1", params.ID)), nil
+	}
+
+	f, err := os.Open(filepath.Join(dir, params.ID+".txt"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return NewTextErrorResponse(fmt.Sprintf("no saved output %s in this session, leave id empty to list the saved outputs", params.ID)), nil
+	}
+	if err != nil {
+		return ToolResponse{}, fmt.Errorf("error opening saved output: %w", err)
+	}
+	defer f.Close()
+
+	output, err := readOutput(f, params)
+	if err != nil {
+		return NewTextErrorResponse(err.Error()), nil
+	}
+	return NewTextResponse(output), nil
+}
+
+func listSavedOutputs(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "No saved outputs in this session"
+	}
+	var outputs []string
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".txt")
+		if !ok || !outputIDPattern.MatchString(id) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		lines := bytes.Count(data, []byte("\n"))
+		if !bytes.HasSuffix(data, []byte("\n")) {
+			lines++
+		}
+		outputs = append(outputs, fmt.Sprintf("%s (%d lines)", id, lines))
+	}
+	if len(outputs) == 0 {
+		return "No saved outputs in this session"
+	}
+	sort.Strings(outputs)
+	return "Saved outputs:\n" + strings.Join(outputs, "\n")
+}
+
+// readOutput returns the numbered lines of a saved output, a page of them or
+// the ones matching the pattern, with a note on how to go on.
+func readOutput(r io.Reader, params ReadOutputParams) (string, error) {
+	var pattern *regexp.Regexp
+	if params.Pattern != "" {
+		var err error
+		if pattern, err = regexp.Compile(params.Pattern); err != nil {
+			return "", fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+	offset := max(params.Offset, 1)
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultReadOutputLimit
+		if pattern != nil {
+			limit = defaultOutputMatches
+		}
+	}
+
+	var shown []string
+	size, lineNum, matches, next := 0, 0, 0, 0
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line == "" && err != nil {
+			if err != io.EOF {
+				return "", fmt.Errorf("error reading saved output: %w", err)
+			}
+			break
+		}
+		lineNum++
+		line = strings.TrimRight(line, "\r\n")
+		if lineNum < offset || (pattern != nil && !pattern.MatchString(line)) {
+			continue
+		}
+		matches++
+		if next > 0 || len(shown) == limit || size > MaxOutputLength {
+			if next == 0 {
+				next = lineNum
+			}
+			continue
+		}
+		if len(line) > MaxLineLength {
+			line = line[:MaxLineLength] + "..."
+		}
+		numbered := fmt.Sprintf("%6d|%s", lineNum, line)
+		shown = append(shown, numbered)
+		size += len(numbered) + 1
+	}
+
+	if pattern == nil {
+		if len(shown) == 0 {
+			return "", fmt.Errorf("offset %d is past the end of the output, which has %d lines", offset, lineNum)
+		}
+		result := strings.Join(shown, "\n")
+		if next > 0 {
+			result += fmt.Sprintf("\n\n(Lines %d-%d of %d. Use offset %d to read more.)", offset, next-1, lineNum, next)
+		}
+		return result, nil
+	}
+
+	if len(shown) == 0 {
+		return fmt.Sprintf("No lines match %q", params.Pattern), nil
+	}
+	result := strings.Join(shown, "\n")
+	if next > 0 {
+		result += fmt.Sprintf("\n\n(Showing %d of %d matching lines. Use offset %d to list more.)", len(shown), matches, next)
+	}
+	return result, nil
+}
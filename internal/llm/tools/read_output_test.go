@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// numberedLines is an output of n lines, which are long enough for a
+// thousand of them to be more than a tool result can hold.
+func numberedLines(n int) string {
+	var sb strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&sb, "line %d %s\n", i, strings.Repeat("x", 40))
+	}
+	return sb.String()
+}
+
+func TestSaveOutput(t *testing.T) {
+	dir := t.TempDir()
+	content := numberedLines(1000)
+
+	id, err := saveOutput(dir, "github_search-code", content)
+	require.NoError(t, err)
+	assert.Equal(t, "github_search_code-1", id)
+	id, err = saveOutput(dir, BashToolName, content)
+	require.NoError(t, err)
+	assert.Equal(t, "bash-1", id)
+	id, err = saveOutput(dir, BashToolName, content)
+	require.NoError(t, err)
+	assert.Equal(t, "bash-2", id)
+
+	saved, err := os.ReadFile(filepath.Join(dir, "bash-2.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, content, string(saved))
+	assert.Equal(t, "Saved outputs:\nbash-1 (1000 lines)\nbash-2 (1000 lines)\ngithub_search_code-1 (1000 lines)", listSavedOutputs(dir))
+}
+
+func TestOutputPreview(t *testing.T) {
+	preview := outputPreview("bash-1", numberedLines(1000))
+	assert.Less(t, len(preview), MaxOutputLength)
+	assert.True(t, strings.HasPrefix(preview, "line 1 "))
+	assert.Contains(t, preview, "\nline 152 "+strings.Repeat("x", 40)+"\n... [lines 153-851 not shown] ...\nline 852 ")
+	assert.True(t, strings.HasSuffix(preview, "line 1000 "+strings.Repeat("x", 40)+
+		"\n\n[The output has 1000 lines and was saved as bash-1. Use the read_output tool with id \"bash-1\" to read the lines that are not shown or to search them.]"))
+
+	preview = outputPreview("bash-1", strings.Repeat("x", 40000))
+	assert.Contains(t, preview, "\n... [25000 characters not shown] ...\n")
+}
+
+func TestReadOutput(t *testing.T) {
+	content := numberedLines(1000)
+
+	output, err := readOutput(strings.NewReader(content), ReadOutputParams{Offset: 200, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("   200|line 200 %[1]s\n   201|line 201 %[1]s\n\n(Lines 200-201 of 1000. Use offset 202 to read more.)", strings.Repeat("x", 40)), output)
+
+	output, err = readOutput(strings.NewReader(content), ReadOutputParams{Offset: 999})
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(output, "\n")+1)
+
+	output, err = readOutput(strings.NewReader(content), ReadOutputParams{Pattern: `^line 9\d\d `, Offset: 950, Limit: 10})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(output, "   950|line 950 "))
+	assert.True(t, strings.HasSuffix(output, "\n\n(Showing 10 of 50 matching lines. Use offset 960 to list more.)"))
+
+	output, err = readOutput(strings.NewReader(content), ReadOutputParams{Pattern: "panic"})
+	require.NoError(t, err)
+	assert.Equal(t, `No lines match "panic"`, output)
+
+	_, err = readOutput(strings.NewReader(content), ReadOutputParams{Offset: 2000})
+	assert.EqualError(t, err, "offset 2000 is past the end of the output, which has 1000 lines")
+	_, err = readOutput(strings.NewReader(content), ReadOutputParams{Pattern: "("})
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/opencode-ai/opencode/internal/lsp"
+	"github.com/opencode-ai/opencode/internal/lsp/protocol"
+)
+
+type referencesTool struct {
+	lspClients map[string]*lsp.Client
+}
+
+const (
+	ReferencesToolName    = "references"
+	referencesDescription = `Finds all references to a symbol using the language servers.
+
+WHEN TO USE THIS TOOL:
+- Use before changing a function, type or field to see every place that uses it
+- Prefer it over grepping for an identifier, it skips comments, strings and unrelated symbols with the same name
+
+HOW TO USE:
+- Pass location as file:line:column of the symbol or one of its uses, or
+- Pass symbol with its name (e.g. OpenFile or Client.OpenFile) to search the workspace
+- Pass both location (file:line) and symbol to look the name up on that line
+
+FEATURES:
+- Returns file:line:column with the source line of each reference, sorted by file
+- Includes the declaration itself
+
+LIMITATIONS:
+- Only works for languages with a configured language server
+- Shows at most 100 references
+
+TIPS:
+- When searching by name and several symbols match, the first one is used and the others are listed, pass a location to pick another`
+)
+
+func NewReferencesTool(lspClients map[string]*lsp.Client) BaseTool {
+	return &referencesTool{
+		lspClients,
+	}
+}
+
+func (r *referencesTool) Info() ToolInfo {
+	return ToolInfo{
+		Name:        ReferencesToolName,
+		Description: referencesDescription,
+		Parameters:  navigationParameters,
+		Required:    []string{},
+	}
+}
+
+func (r *referencesTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error) {
+	var params NavigationParams
+	if err := json.Unmarshal([]byte(call.Input), &params); err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("error parsing parameters: %s", err)), nil
+	}
+	if len(r.lspClients) == 0 {
+		return NewTextErrorResponse("no LSP clients available"), nil
+	}
+
+	target, others, err := resolveNavigationTarget(ctx, r.lspClients, params)
+	if err != nil {
+		return NewTextErrorResponse(err.Error()), nil
+	}
+
+	locations, err := queryClients(r.lspClients, func(client *lsp.Client) ([]protocol.Location, error) {
+		return client.References(ctx, protocol.ReferenceParams{
+			TextDocumentPositionParams: target.textDocumentPosition(),
+			Context:                    protocol.ReferenceContext{IncludeDeclaration: true},
+		})
+	})
+	if err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("error finding references: %s", err)), nil
+	}
+	if len(locations) == 0 {
+		lines := sourceLines{}
+		return NewTextResponse(fmt.Sprintf("No references found at %s", lines.format(target.path, target.position))), nil
+	}
+
+	output := fmt.Sprintf("%d reference(s)\n%s", len(locations), formatLocations(locations))
+	return NewTextResponse(output + otherMatchesNote(target, others)), nil
+}
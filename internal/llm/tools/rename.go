@@ -0,0 +1,166 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/opencode-ai/opencode/internal/history"
+	"github.com/opencode-ai/opencode/internal/lsp"
+	"github.com/opencode-ai/opencode/internal/lsp/protocol"
+	"github.com/opencode-ai/opencode/internal/lsp/util"
+	"github.com/opencode-ai/opencode/internal/permission"
+)
+
+type RenameSymbolParams struct {
+	NavigationParams
+	NewName string `json:"new_name"`
+}
+
+type renameSymbolTool struct {
+	workspaceEditApplier
+}
+
+const (
+	RenameSymbolToolName    = "rename_symbol"
+	renameSymbolDescription = `Renames a symbol and updates every reference to it across the project using the language servers.
+
+WHEN TO USE THIS TOOL:
+- Use to rename a function, method, type, variable, field or parameter
+- Prefer it over a series of edit calls, it finds all references including ones in other files
+
+HOW TO USE:
+- Pass location as file:line:column of the symbol or one of its uses, or
+- Pass symbol with its name (e.g. OpenFile or Client.OpenFile) to search the workspace
+- Pass both location (file:line) and symbol to look the name up on that line
+- Pass new_name with the new name of the symbol
+
+FEATURES:
+- The language server computes the changes, so comments and unrelated symbols with the same name are left alone
+- All changed files are shown for approval at once and recorded in the file history
+- Some language servers also rename files, e.g. a module named after the symbol
+
+LIMITATIONS:
+- Only works with language servers that support renaming
+- The server may refuse to rename symbols it doesn't own, like those from dependencies
+
+TIPS:
+- Use the references tool first to see what will change
+- Check the diagnostics of the result, some servers can't update every use`
+)
+
+func NewRenameSymbolTool(lspClients map[string]*lsp.Client, permissions permission.Service, files history.Service) BaseTool {
+	return &renameSymbolTool{
+		workspaceEditApplier{
+			lspClients:  lspClients,
+			permissions: permissions,
+			files:       files,
+		},
+	}
+}
+
+func (r *renameSymbolTool) Info() ToolInfo {
+	parameters := map[string]any{
+		"new_name": map[string]any{
+			"type":        "string",
+			"description": "The new name of the symbol",
+		},
+	}
+	for name, parameter := range navigationParameters {
+		parameters[name] = parameter
+	}
+	return ToolInfo{
+		Name:        RenameSymbolToolName,
+		Description: renameSymbolDescription,
+		Parameters:  parameters,
+		Required:    []string{"new_name"},
+	}
+}
+
+func (r *renameSymbolTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error) {
+	var params RenameSymbolParams
+	if err := json.Unmarshal([]byte(call.Input), &params); err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("error parsing parameters: %s", err)), nil
+	}
+	params.NewName = strings.TrimSpace(params.NewName)
+	if params.NewName == "" {
+		return NewTextErrorResponse("new_name is required"), nil
+	}
+	if len(r.lspClients) == 0 {
+		return NewTextErrorResponse("no LSP clients available"), nil
+	}
+
+	target, others, err := resolveNavigationTarget(ctx, r.lspClients, params.NavigationParams)
+	if err != nil {
+		return NewTextErrorResponse(err.Error()), nil
+	}
+	if len(others) > 0 {
+		// Renaming the wrong symbol is worse than asking again
+		return NewTextErrorResponse(strings.TrimSpace(otherMatchesNote(target, others))), nil
+	}
+
+	edit, err := r.renameEdit(ctx, target, params.NewName)
+	if err != nil {
+		return NewTextErrorResponse(err.Error()), nil
+	}
+	changes, err := util.PreviewWorkspaceEdit(edit)
+	if err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("error preparing the rename: %s", err)), nil
+	}
+	if len(changes) == 0 {
+		return NewTextErrorResponse("the rename doesn't change any file"), nil
+	}
+
+	lines := sourceLines{}
+	metadata, err := r.apply(ctx, RenameSymbolToolName,
+		fmt.Sprintf("Rename the symbol at %s to %s", lines.position(target.path, target.position), params.NewName),
+		changes,
+	)
+	if err != nil {
+		return ToolResponse{}, err
+	}
+
+	waitForLspDiagnostics(ctx, target.path, r.lspClients)
+	result := fmt.Sprintf("Renamed to %s. %s", params.NewName, summarizeFileDiffs(metadata))
+	if diagnostics := getDiagnostics(target.path, r.lspClients); diagnostics != "" {
+		result += "\n" + diagnostics
+	}
+	return WithResponseMetadata(NewTextResponse(result), metadata), nil
+}
+
+// renameEdit asks the servers for the rename edit. A server that can't
+// rename at the position says so in PrepareRename, servers that don't
+// implement PrepareRename are asked for the rename directly.
+func (r *renameSymbolTool) renameEdit(ctx context.Context, target navigationTarget, newName string) (protocol.WorkspaceEdit, error) {
+	var errs []error
+	for _, name := range sortedClientNames(r.lspClients) {
+		client := r.lspClients[name]
+		prepared, err := client.PrepareRename(ctx, protocol.PrepareRenameParams{
+			TextDocumentPositionParams: target.textDocumentPosition(),
+		})
+		if err == nil && prepared.Value == nil {
+			continue
+		}
+
+		edit, err := client.Rename(ctx, protocol.RenameParams{
+			TextDocument: target.textDocumentPosition().TextDocument,
+			Position:     target.position,
+			NewName:      newName,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		if len(edit.Changes) > 0 || len(edit.DocumentChanges) > 0 {
+			return edit, nil
+		}
+	}
+
+	lines := sourceLines{}
+	if len(errs) > 0 {
+		return protocol.WorkspaceEdit{}, fmt.Errorf("error renaming %s: %w", lines.format(target.path, target.position), errors.Join(errs...))
+	}
+	return protocol.WorkspaceEdit{}, fmt.Errorf("no symbol that can be renamed at %s", lines.format(target.path, target.position))
+}
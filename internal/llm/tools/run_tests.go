@@ -0,0 +1,709 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/llm/tools/shell"
+	"github.com/opencode-ai/opencode/internal/permission"
+)
+
+type RunTestsParams struct {
+	Framework string `json:"framework"`
+	Target    string `json:"target"`
+	Name      string `json:"name"`
+	Timeout   int    `json:"timeout"`
+}
+
+type runTestsTool struct {
+	permissions permission.Service
+}
+
+const (
+	RunTestsToolName       = "run_tests"
+	DefaultTestTimeout     = 5 * 60 * 1000 // 5 minutes in milliseconds
+	maxReportedFailures    = 20
+	maxFailureMessageLines = 20
+	maxFailureLogLines     = 10
+	runTestsDescription    = `Runs tests and returns a summary of the failing tests, with where they failed, their assertion messages and their last log lines.
+
+WHEN TO USE THIS TOOL:
+- Use instead of running tests through the bash tool, so the failures aren't lost in long output
+- Use after changing code to check that the tests still pass
+
+HOW TO USE:
+- Set target to the package, directory or file to test, like "./internal/parser" for Go or "tests/test_api.py" for pytest. Without it all the tests run
+- Set name to run only matching tests: a regular expression for go test and jest, a -k expression for pytest
+- The framework is detected from the project, set framework to go, jest or pytest to choose it
+
+FEATURES:
+- Runs go test -json, jest --json and pytest with a JUnit report, and reads their results instead of their console output
+- Lists each failing test with file:line, the assertion message and the last lines it logged
+- Reports build and collection errors, which keep tests from running at all
+
+LIMITATIONS:
+- Only go test, jest and pytest are supported, run other test commands with the bash tool
+- At most 20 failing tests are listed
+- The default timeout is 5 minutes, the maximum 10 minutes
+
+TIPS:
+- Run the tests of the package you changed first, then all of them
+- Use name to rerun a single failing test while fixing it`
+)
+
+// testFailure is a failing test, or a package or file that couldn't be
+// tested, in a test report.
+type testFailure struct {
+	Name     string
+	Suite    string
+	Location string
+	Message  []string
+	Logs     []string
+}
+
+type testReport struct {
+	Passed   int
+	Failed   int
+	Skipped  int
+	Failures []testFailure
+}
+
+var testFrameworks = []string{"go", "jest", "pytest"}
+
+func NewRunTestsTool(permissions permission.Service) BaseTool {
+	return &runTestsTool{
+		permissions: permissions,
+	}
+}
+
+func (r *runTestsTool) Info() ToolInfo {
+	return ToolInfo{
+		Name:        RunTestsToolName,
+		Description: runTestsDescription,
+		Parameters: map[string]any{
+			"framework": map[string]any{
+				"type":        "string",
+				"description": "The test framework, detected from the project when not set",
+				"enum":        testFrameworks,
+			},
+			"target": map[string]any{
+				"type":        "string",
+				"description": "The package, directory or file to test (optional, defaults to all the tests)",
+			},
+			"name": map[string]any{
+				"type":        "string",
+				"description": "Only run the tests matching this pattern (optional)",
+			},
+			"timeout": map[string]any{
+				"type":        "number",
+				"description": "Optional timeout in milliseconds (max 600000)",
+			},
+		},
+	}
+}
+
+func (r *runTestsTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error) {
+	var params RunTestsParams
+	if err := json.Unmarshal([]byte(call.Input), &params); err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("error parsing parameters: %s", err)), nil
+	}
+	if params.Timeout > MaxTimeout {
+		params.Timeout = MaxTimeout
+	} else if params.Timeout <= 0 {
+		params.Timeout = DefaultTestTimeout
+	}
+	if strings.HasPrefix(params.Target, "-") || strings.HasPrefix(params.Name, "-") {
+		return NewTextErrorResponse("target and name must not start with -"), nil
+	}
+
+	workingDir := config.WorkingDirectory()
+	framework := params.Framework
+	if framework == "" {
+		framework = detectTestFramework(workingDir, params.Target)
+		if framework == "" {
+			return NewTextErrorResponse("could not detect the test framework, set framework to go, jest or pytest or run the tests with the bash tool"), nil
+		}
+	} else if !slices.Contains(testFrameworks, framework) {
+		return NewTextErrorResponse(fmt.Sprintf("unknown framework %q, use go, jest or pytest", framework)), nil
+	}
+
+	// jest and pytest write their report to a file, so it doesn't mix with
+	// what the tests print
+	reportFile, err := os.CreateTemp("", "opencode-tests-*")
+	if err != nil {
+		return ToolResponse{}, fmt.Errorf("error creating report file: %w", err)
+	}
+	reportFile.Close()
+	defer os.Remove(reportFile.Name())
+
+	command := testCommand(framework, params, reportFile.Name())
+	commands, err := checkBashCommand(command)
+	if err != nil {
+		return NewTextErrorResponse(err.Error()), nil
+	}
+
+	sessionID, messageID := GetContextValues(ctx)
+	if sessionID == "" || messageID == "" {
+		return ToolResponse{}, fmt.Errorf("session ID and message ID are required for running tests")
+	}
+	if err := shell.CheckSandbox(); err != nil {
+		return NewTextErrorResponse(err.Error()), nil
+	}
+	if !allReadOnly(commands) && !shell.SandboxAutoApprove() {
+		p := r.permissions.Request(
+			permission.CreatePermissionRequest{
+				SessionID:   sessionID,
+				Path:        workingDir,
+				ToolName:    RunTestsToolName,
+				Action:      "execute",
+				Description: fmt.Sprintf("Run tests: %s", command),
+				Params: BashPermissionsParams{
+					Command:  command,
+					Commands: commands,
+				},
+			},
+		)
+		if !p {
+			return ToolResponse{}, permission.ErrorPermissionDenied
+		}
+	}
+
+	startTime := time.Now()
+	persistentShell := shell.GetPersistentShell(workingDir)
+	// The subshell keeps the directory of the shell as the bash tool left it
+	stdout, stderr, exitCode, interrupted, err := persistentShell.Exec(ctx, fmt.Sprintf("(cd %s && %s)", shellQuoteArgs([]string{workingDir}), command), params.Timeout)
+	if err != nil {
+		return ToolResponse{}, fmt.Errorf("error running tests: %w", err)
+	}
+	elapsed := time.Since(startTime).Round(100 * time.Millisecond)
+	if interrupted {
+		return NewTextErrorResponse(fmt.Sprintf("the tests were interrupted after %s, use a longer timeout or a narrower target\n\n%s", elapsed, lastLines(stdout+stderr, 30))), nil
+	}
+
+	var report testReport
+	switch framework {
+	case "go":
+		report = parseGoTestJSON(stdout)
+	case "jest":
+		data, _ := os.ReadFile(reportFile.Name())
+		report, err = parseJestReport(data)
+	case "pytest":
+		data, _ := os.ReadFile(reportFile.Name())
+		report, err = parseJUnitReport(data)
+	}
+	if err != nil || (exitCode != 0 && len(report.Failures) == 0) {
+		// The runner failed before reporting anything, its output says why
+		output := stdout
+		if framework == "go" {
+			output = ""
+			for _, line := range strings.Split(stdout, "\n") {
+				if !strings.HasPrefix(line, "{") {
+					output += line + "\n"
+				}
+			}
+		}
+		output = strings.TrimSpace(output + "\n" + stderr)
+		if violation := shell.SandboxViolation(output); violation != "" {
+			output += "\n\n" + violation
+		}
+		return NewTextErrorResponse(fmt.Sprintf("%s exited with code %d without reporting test results:\n\n%s", command, exitCode, lastLines(output, 50))), nil
+	}
+
+	return NewTextResponse(formatTestReport(framework, report, elapsed)), nil
+}
+
+// detectTestFramework picks the framework from the target's file type, or
+// else from the project files in dir.
+func detectTestFramework(dir, target string) string {
+	switch filepath.Ext(target) {
+	case ".go":
+		return "go"
+	case ".py":
+		return "pytest"
+	case ".js", ".jsx", ".ts", ".tsx", ".mjs", ".cjs":
+		return "jest"
+	}
+
+	if fileExists(filepath.Join(dir, "go.mod")) {
+		return "go"
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+		var pkg struct {
+			Scripts         map[string]string `json:"scripts"`
+			Dependencies    map[string]string `json:"dependencies"`
+			DevDependencies map[string]string `json:"devDependencies"`
+		}
+		if json.Unmarshal(data, &pkg) == nil {
+			_, dep := pkg.Dependencies["jest"]
+			_, devDep := pkg.DevDependencies["jest"]
+			if dep || devDep || strings.Contains(pkg.Scripts["test"], "jest") {
+				return "jest"
+			}
+		}
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "jest.config.*")); len(matches) > 0 {
+		return "jest"
+	}
+	for _, name := range []string{"pytest.ini", "conftest.py", "pyproject.toml", "setup.py", "setup.cfg", "tox.ini"} {
+		if fileExists(filepath.Join(dir, name)) {
+			return "pytest"
+		}
+	}
+	return ""
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// testCommand is the shell command that runs the tests with a report the
+// tool can read.
+func testCommand(framework string, params RunTestsParams, reportPath string) string {
+	var args []string
+	switch framework {
+	case "go":
+		target := params.Target
+		if target == "" {
+			target = "./..."
+		} else if strings.HasSuffix(target, ".go") {
+			// go test takes packages, not files
+			target = filepath.Dir(target)
+		}
+		if !filepath.IsAbs(target) && !strings.HasPrefix(target, ".") {
+			target = "./" + target
+		}
+		args = []string{"go", "test", "-json"}
+		if params.Name != "" {
+			args = append(args, "-run", params.Name)
+		}
+		args = append(args, target)
+	case "jest":
+		args = []string{"npx", "jest", "--json", "--outputFile=" + reportPath, "--testLocationInResults"}
+		if params.Name != "" {
+			args = append(args, "--testNamePattern", params.Name)
+		}
+		if params.Target != "" {
+			args = append(args, params.Target)
+		}
+	case "pytest":
+		args = []string{"python3", "-m", "pytest", "-q", "-o", "junit_logging=all", "--junitxml=" + reportPath}
+		if params.Name != "" {
+			args = append(args, "-k", params.Name)
+		}
+		if params.Target != "" {
+			args = append(args, params.Target)
+		}
+	}
+	return shellQuoteArgs(args)
+}
+
+var (
+	goLocationPattern   = regexp.MustCompile(`^\s*([\w./\\-]+\.go):(\d+): ?(.*)$`)
+	goErrorTracePattern = regexp.MustCompile(`Error Trace:\s+(\S+\.go:\d+)`)
+	goPanicLocation     = regexp.MustCompile(`(\S+_test\.go:\d+)`)
+)
+
+type goTestEvent struct {
+	Action      string
+	Package     string
+	Test        string
+	Output      string
+	ImportPath  string
+	FailedBuild string
+}
+
+// parseGoTestJSON reads the events of go test -json. Packages that fail
+// without a failing test, because they didn't build or a test binary
+// crashed, are reported as failures of the package.
+func parseGoTestJSON(output string) testReport {
+	var report testReport
+	outputs := make(map[string][]string)
+	buildOutputs := make(map[string][]string)
+	var failed []goTestEvent
+	var failedPackages []goTestEvent
+	for _, line := range strings.Split(output, "\n") {
+		var event goTestEvent
+		if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &event) != nil {
+			continue
+		}
+		key := event.Package + " " + event.Test
+		switch event.Action {
+		case "output":
+			outputs[key] = append(outputs[key], strings.TrimRight(event.Output, "\n"))
+		case "build-output":
+			buildOutputs[event.ImportPath] = append(buildOutputs[event.ImportPath], strings.TrimRight(event.Output, "\n"))
+		case "pass":
+			if event.Test != "" {
+				report.Passed++
+			}
+		case "skip":
+			if event.Test != "" {
+				report.Skipped++
+			}
+		case "fail":
+			if event.Test != "" {
+				failed = append(failed, event)
+			} else {
+				failedPackages = append(failedPackages, event)
+			}
+		}
+	}
+
+	for _, event := range failed {
+		// A failing subtest fails its parents too, only the subtest is listed
+		if slices.ContainsFunc(failed, func(other goTestEvent) bool {
+			return other.Package == event.Package && strings.HasPrefix(other.Test, event.Test+"/")
+		}) {
+			continue
+		}
+		report.Failed++
+		report.Failures = append(report.Failures, goTestFailure(event, outputs[event.Package+" "+event.Test]))
+	}
+	for _, event := range failedPackages {
+		if slices.ContainsFunc(failed, func(other goTestEvent) bool {
+			return other.Package == event.Package
+		}) {
+			continue
+		}
+		lines := buildOutputs[event.FailedBuild]
+		name := "build failed"
+		if event.FailedBuild == "" {
+			lines = outputs[event.Package+" "]
+			name = "package failed"
+		}
+		lines = slices.DeleteFunc(slices.Clone(lines), func(line string) bool {
+			return line == "FAIL" || strings.HasPrefix(line, "FAIL\t") || strings.HasPrefix(line, "# ")
+		})
+		report.Failures = append(report.Failures, testFailure{
+			Name:    name,
+			Suite:   event.Package,
+			Message: tailLines(lines, maxFailureMessageLines),
+		})
+	}
+	return report
+}
+
+func goTestFailure(event goTestEvent, output []string) testFailure {
+	var lines []string
+	for _, line := range output {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "=== ") || strings.HasPrefix(trimmed, "--- ") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	failure := testFailure{Name: event.Test, Suite: event.Package}
+	start, message := -1, ""
+	for i, line := range lines {
+		if match := goErrorTracePattern.FindStringSubmatch(line); match != nil {
+			// testify prints the assertion as a block starting with the trace
+			start, failure.Location = i, match[1]
+			break
+		}
+		if strings.HasPrefix(line, "panic: ") {
+			start = i
+			if match := goPanicLocation.FindStringSubmatch(strings.Join(lines[i:], "\n")); match != nil {
+				failure.Location = match[1]
+			}
+			break
+		}
+		if match := goLocationPattern.FindStringSubmatch(line); match != nil {
+			// t.Error and t.Fatal report where they were called, the last
+			// one is usually the failure and the ones before it logs
+			start, failure.Location, message = i, match[1]+":"+match[2], match[3]
+		}
+	}
+	if message != "" {
+		lines[start] = message
+	}
+	if start == -1 {
+		failure.Message = tailLines(trimIndent(lines), maxFailureMessageLines)
+		return failure
+	}
+	failure.Message = headLines(trimIndent(lines[start:]), maxFailureMessageLines)
+	failure.Logs = tailLines(trimIndent(lines[:start]), maxFailureLogLines)
+	return failure
+}
+
+type jestReport struct {
+	TestResults []struct {
+		Name             string `json:"name"`
+		Status           string `json:"status"`
+		Message          string `json:"message"`
+		AssertionResults []struct {
+			FullName        string   `json:"fullName"`
+			Status          string   `json:"status"`
+			FailureMessages []string `json:"failureMessages"`
+			Location        *struct {
+				Line int `json:"line"`
+			} `json:"location"`
+		} `json:"assertionResults"`
+	} `json:"testResults"`
+}
+
+// parseJestReport reads the report of jest --json. Test files that fail
+// without a failing test, e.g. because they don't compile, are reported as
+// failures of the file.
+func parseJestReport(data []byte) (testReport, error) {
+	var jest jestReport
+	if err := json.Unmarshal(data, &jest); err != nil {
+		return testReport{}, fmt.Errorf("invalid jest report: %w", err)
+	}
+	var report testReport
+	for _, file := range jest.TestResults {
+		relPath := relativeTestPath(file.Name)
+		fileFailed := false
+		for _, assertion := range file.AssertionResults {
+			switch assertion.Status {
+			case "passed":
+				report.Passed++
+			case "pending", "skipped", "todo", "disabled":
+				report.Skipped++
+			case "failed":
+				fileFailed = true
+				report.Failed++
+				failure := testFailure{Name: assertion.FullName, Suite: relPath}
+				if assertion.Location != nil {
+					failure.Location = fmt.Sprintf("%s:%d", relPath, assertion.Location.Line)
+				}
+				text := stripANSI(strings.Join(assertion.FailureMessages, "\n"))
+				// The stack points at the failing assertion in the test file
+				if match := regexp.MustCompile(regexp.QuoteMeta(file.Name) + `:(\d+):\d+`).FindStringSubmatch(text); match != nil {
+					failure.Location = relPath + ":" + match[1]
+				}
+				failure.Message = headLines(trimIndent(jestMessageLines(text)), maxFailureMessageLines)
+				report.Failures = append(report.Failures, failure)
+			}
+		}
+		if file.Status == "failed" && !fileFailed {
+			report.Failures = append(report.Failures, testFailure{
+				Name:    "test file failed",
+				Suite:   relPath,
+				Message: headLines(trimIndent(strings.Split(stripANSI(strings.TrimSpace(file.Message)), "\n")), maxFailureMessageLines),
+			})
+		}
+	}
+	return report, nil
+}
+
+// jestMessageLines drops the stack trace from a jest failure message.
+func jestMessageLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "at ") {
+			break
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	File      string        `xml:"file,attr"`
+	Line      string        `xml:"line,attr"`
+	Failures  []junitResult `xml:"failure"`
+	Errors    []junitResult `xml:"error"`
+	Skipped   []junitResult `xml:"skipped"`
+	SystemOut string        `xml:"system-out"`
+	SystemErr string        `xml:"system-err"`
+}
+
+type junitResult struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSuite struct {
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+var pytestLocationPattern = regexp.MustCompile(`(?m)^(\S+\.py):(\d+): `)
+
+// parseJUnitReport reads the JUnit XML report of pytest.
+func parseJUnitReport(data []byte) (testReport, error) {
+	var suites struct {
+		XMLName   xml.Name
+		Suites    []junitSuite    `xml:"testsuite"`
+		TestCases []junitTestCase `xml:"testcase"`
+	}
+	if err := xml.Unmarshal(data, &suites); err != nil {
+		return testReport{}, fmt.Errorf("invalid JUnit report: %w", err)
+	}
+	// The root is either <testsuites> or a single <testsuite>
+	testCases := suites.TestCases
+	for _, suite := range suites.Suites {
+		testCases = append(testCases, suite.TestCases...)
+	}
+
+	var report testReport
+	for _, testCase := range testCases {
+		results := append(testCase.Failures, testCase.Errors...)
+		switch {
+		case len(results) > 0:
+			report.Failed++
+			report.Failures = append(report.Failures, pytestFailure(testCase, results[0]))
+		case len(testCase.Skipped) > 0:
+			report.Skipped++
+		default:
+			report.Passed++
+		}
+	}
+	return report, nil
+}
+
+func pytestFailure(testCase junitTestCase, result junitResult) testFailure {
+	failure := testFailure{Name: testCase.Name, Suite: testCase.ClassName}
+	if testCase.File != "" && testCase.Line != "" {
+		// JUnit lines are 0-based
+		if line, err := strconv.Atoi(testCase.Line); err == nil {
+			failure.Location = fmt.Sprintf("%s:%d", testCase.File, line+1)
+		}
+	}
+	if matches := pytestLocationPattern.FindAllStringSubmatch(result.Text, -1); len(matches) > 0 {
+		last := matches[len(matches)-1]
+		failure.Location = last[1] + ":" + last[2]
+	}
+
+	// pytest marks the lines explaining the failure with "E"
+	var message []string
+	for _, line := range strings.Split(result.Text, "\n") {
+		if text, ok := strings.CutPrefix(line, "E "); ok {
+			message = append(message, text)
+		}
+	}
+	message = trimIndent(message)
+	if len(message) == 0 {
+		message = strings.Split(strings.TrimSpace(result.Message), "\n")
+	}
+	if len(message) == 1 && message[0] == "" {
+		message = tailLines(strings.Split(strings.TrimSpace(result.Text), "\n"), maxFailureMessageLines)
+	}
+	failure.Message = headLines(message, maxFailureMessageLines)
+
+	var logs []string
+	for _, output := range []string{testCase.SystemOut, testCase.SystemErr} {
+		for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+			// junit_logging=all labels the captured sections with dashes
+			if line != "" && !strings.HasPrefix(line, "-----") {
+				logs = append(logs, line)
+			}
+		}
+	}
+	failure.Logs = tailLines(logs, maxFailureLogLines)
+	return failure
+}
+
+// formatTestReport renders the report as a summary line followed by one
+// block per failure.
+func formatTestReport(framework string, report testReport, elapsed time.Duration) string {
+	counts := []string{fmt.Sprintf("%d passed", report.Passed)}
+	if report.Failed > 0 {
+		counts = append(counts, fmt.Sprintf("%d failed", report.Failed))
+	}
+	if report.Skipped > 0 {
+		counts = append(counts, fmt.Sprintf("%d skipped", report.Skipped))
+	}
+	if errors := len(report.Failures) - report.Failed; errors > 0 {
+		counts = append(counts, fmt.Sprintf("%d not run because of errors", errors))
+	}
+	summary := fmt.Sprintf("%s: %s in %s", framework, strings.Join(counts, ", "), elapsed)
+	if len(report.Failures) == 0 {
+		if report.Passed == 0 {
+			return summary + "\n\nNo tests matched, check the target and the name"
+		}
+		return summary + "\n\nAll tests passed"
+	}
+
+	blocks := []string{summary}
+	for i, failure := range report.Failures {
+		if i == maxReportedFailures {
+			blocks = append(blocks, fmt.Sprintf("... and %d more failures", len(report.Failures)-i))
+			break
+		}
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "FAIL %s", failure.Name)
+		if failure.Suite != "" {
+			fmt.Fprintf(&sb, " (%s)", failure.Suite)
+		}
+		if failure.Location != "" {
+			fmt.Fprintf(&sb, "\n  at %s", failure.Location)
+		}
+		for _, line := range failure.Message {
+			sb.WriteString("\n  " + line)
+		}
+		if len(failure.Logs) > 0 {
+			sb.WriteString("\n  logs:")
+			for _, line := range failure.Logs {
+				sb.WriteString("\n    " + line)
+			}
+		}
+		blocks = append(blocks, sb.String())
+	}
+	return truncateOutput(strings.Join(blocks, "\n\n"))
+}
+
+// relativeTestPath shortens paths in the working directory.
+func relativeTestPath(path string) string {
+	cfg := config.Get()
+	if cfg == nil {
+		return path
+	}
+	if rel, err := filepath.Rel(config.WorkingDirectory(), path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+// trimIndent removes the indentation the lines share.
+func trimIndent(lines []string) []string {
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent == -1 || n < indent {
+			indent = n
+		}
+	}
+	trimmed := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			line = line[indent:]
+		}
+		trimmed = append(trimmed, strings.TrimRight(line, " \t"))
+	}
+	return trimmed
+}
+
+func headLines(lines []string, n int) []string {
+	if len(lines) <= n {
+		return lines
+	}
+	return append(slices.Clone(lines[:n]), fmt.Sprintf("... %d more lines", len(lines)-n))
+}
+
+func tailLines(lines []string, n int) []string {
+	if len(lines) <= n {
+		return lines
+	}
+	return lines[len(lines)-n:]
+}
+
+func lastLines(text string, n int) string {
+	return strings.Join(tailLines(strings.Split(strings.TrimSpace(text), "\n"), n), "\n")
+}
@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const goTestOutput = `{"Action":"start","Package":"example.com/gt/p"}
+{"Action":"run","Package":"example.com/gt/p","Test":"TestAdd"}
+{"Action":"output","Package":"example.com/gt/p","Test":"TestAdd","Output":"=== RUN   TestAdd\n"}
+{"Action":"output","Package":"example.com/gt/p","Test":"TestAdd","Output":"    p_test.go:6: adding\n"}
+{"Action":"output","Package":"example.com/gt/p","Test":"TestAdd","Output":"    p_test.go:8: got 2, want 3\n"}
+{"Action":"output","Package":"example.com/gt/p","Test":"TestAdd","Output":"--- FAIL: TestAdd (0.00s)\n"}
+{"Action":"fail","Package":"example.com/gt/p","Test":"TestAdd","Elapsed":0}
+{"Action":"run","Package":"example.com/gt/p","Test":"TestSub"}
+{"Action":"run","Package":"example.com/gt/p","Test":"TestSub/neg"}
+{"Action":"output","Package":"example.com/gt/p","Test":"TestSub/neg","Output":"    p_test.go:14: negative\n"}
+{"Action":"fail","Package":"example.com/gt/p","Test":"TestSub/neg","Elapsed":0}
+{"Action":"fail","Package":"example.com/gt/p","Test":"TestSub","Elapsed":0}
+{"Action":"run","Package":"example.com/gt/p","Test":"TestOK"}
+{"Action":"pass","Package":"example.com/gt/p","Test":"TestOK","Elapsed":0}
+{"Action":"output","Package":"example.com/gt/p","Output":"FAIL\texample.com/gt/p\t0.003s\n"}
+{"Action":"fail","Package":"example.com/gt/p","Elapsed":0.003}
+{"ImportPath":"example.com/gt/b [example.com/gt/b.test]","Action":"build-output","Output":"# example.com/gt/b [example.com/gt/b.test]\n"}
+{"ImportPath":"example.com/gt/b [example.com/gt/b.test]","Action":"build-output","Output":"b/b.go:2:12: undefined: x\n"}
+{"ImportPath":"example.com/gt/b [example.com/gt/b.test]","Action":"build-fail"}
+{"Action":"output","Package":"example.com/gt/b","Output":"FAIL\texample.com/gt/b [build failed]\n"}
+{"Action":"fail","Package":"example.com/gt/b","Elapsed":0,"FailedBuild":"example.com/gt/b [example.com/gt/b.test]"}`
+
+func TestParseGoTestJSON(t *testing.T) {
+	report := parseGoTestJSON(goTestOutput)
+	assert.Equal(t, 1, report.Passed)
+	assert.Equal(t, 2, report.Failed)
+	assert.Equal(t, []testFailure{
+		{Name: "TestAdd", Suite: "example.com/gt/p", Location: "p_test.go:8", Message: []string{"got 2, want 3"}, Logs: []string{"p_test.go:6: adding"}},
+		{Name: "TestSub/neg", Suite: "example.com/gt/p", Location: "p_test.go:14", Message: []string{"negative"}, Logs: []string{}},
+		{Name: "build failed", Suite: "example.com/gt/b", Message: []string{"b/b.go:2:12: undefined: x"}},
+	}, report.Failures)
+
+	assert.Equal(t, "go: 1 passed, 2 failed, 1 not run because of errors in 1s\n\n"+
+		"FAIL TestAdd (example.com/gt/p)\n  at p_test.go:8\n  got 2, want 3\n  logs:\n    p_test.go:6: adding\n\n"+
+		"FAIL TestSub/neg (example.com/gt/p)\n  at p_test.go:14\n  negative\n\n"+
+		"FAIL build failed (example.com/gt/b)\n  b/b.go:2:12: undefined: x", formatTestReport("go", report, time.Second))
+}
+
+func TestParseJestReport(t *testing.T) {
+	data := `{"testResults": [
+	  {"name": "/app/sum.test.js", "status": "failed", "message": "", "assertionResults": [
+	    {"fullName": "sum adds", "status": "passed", "failureMessages": []},
+	    {"fullName": "sum subtracts", "status": "failed", "location": {"line": 7, "column": 3},
+	     "failureMessages": ["Error: \u001b[2mexpect(\u001b[22mreceived\u001b[2m).toBe(\u001b[22mexpected\u001b[2m)\u001b[22m\n\nExpected: 1\nReceived: 3\n    at Object.<anonymous> (/app/sum.test.js:9:22)\n    at Promise.then.completed (/app/node_modules/jest-circus/build/utils.js:298:28)"]},
+	    {"fullName": "sum later", "status": "pending", "failureMessages": []}
+	  ]},
+	  {"name": "/app/broken.test.js", "status": "failed", "message": "  SyntaxError: Unexpected token (3:4)", "assertionResults": []}
+	]}`
+	report, err := parseJestReport([]byte(data))
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Passed)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, 1, report.Skipped)
+	require.Len(t, report.Failures, 2)
+	assert.Equal(t, "/app/sum.test.js:9", report.Failures[0].Location)
+	assert.Equal(t, []string{"Error: expect(received).toBe(expected)", "", "Expected: 1", "Received: 3"}, report.Failures[0].Message)
+	assert.Equal(t, []string{"SyntaxError: Unexpected token (3:4)"}, report.Failures[1].Message)
+}
+
+func TestParseJUnitReport(t *testing.T) {
+	data := `<?xml version="1.0" encoding="utf-8"?>
+<testsuites><testsuite name="pytest" errors="0" failures="1" skipped="1" tests="3">
+<testcase classname="tests.test_math" name="test_add" file="tests/test_math.py" line="3" />
+<testcase classname="tests.test_math" name="test_div" file="tests/test_math.py" line="7">
+<failure message="assert 2 == 3">def test_div():
+        print("dividing")
+&gt;       assert 4 / 2 == 3
+E       assert 2.0 == 3
+E        +  where 2.0 = 4 / 2
+
+tests/test_math.py:10: AssertionError</failure>
+<system-out>--------------------------------- Captured Out ---------------------------------
+dividing</system-out>
+</testcase>
+<testcase classname="tests.test_math" name="test_skip" file="tests/test_math.py" line="12"><skipped message="later" /></testcase>
+</testsuite></testsuites>`
+	report, err := parseJUnitReport([]byte(data))
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Passed)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, []testFailure{{
+		Name:     "test_div",
+		Suite:    "tests.test_math",
+		Location: "tests/test_math.py:10",
+		Message:  []string{"assert 2.0 == 3", " +  where 2.0 = 4 / 2"},
+		Logs:     []string{"dividing"},
+	}}, report.Failures)
+}
+
+func TestDetectTestFramework(t *testing.T) {
+	dir := t.TempDir()
+	assert.Equal(t, "", detectTestFramework(dir, ""))
+	assert.Equal(t, "pytest", detectTestFramework(dir, "tests/test_api.py"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"devDependencies": {"jest": "^29.0.0"}}`), 0o644))
+	assert.Equal(t, "jest", detectTestFramework(dir, ""))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/x\n"), 0o644))
+	assert.Equal(t, "go", detectTestFramework(dir, ""))
+}
+
+func TestTestCommand(t *testing.T) {
+	assert.Equal(t, "go test -json -run '^TestParse$' ./internal/parser", testCommand("go", RunTestsParams{Target: "internal/parser/parser_test.go", Name: "^TestParse$"}, ""))
+	assert.Equal(t, "python3 -m pytest -q -o junit_logging=all --junitxml=/tmp/r.xml -k 'add or sub' tests", testCommand("pytest", RunTestsParams{Target: "tests", Name: "add or sub"}, "/tmp/r.xml"))
+}
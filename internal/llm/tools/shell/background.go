@@ -0,0 +1,325 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/logging"
+	"github.com/opencode-ai/opencode/internal/pubsub"
+)
+
+const (
+	// Older output of a background process is dropped past this size
+	maxBackgroundOutput = 1024 * 1024
+	// How long a process gets to exit after SIGTERM before it is killed
+	backgroundKillTimeout = 5 * time.Second
+)
+
+var ErrProcessNotFound = errors.New("process not found")
+
+// BackgroundProcess describes a command the agent started in the
+// background.
+type BackgroundProcess struct {
+	SessionID string
+	Name      string
+	Command   string
+	PID       int
+	StartedAt int64
+	Running   bool
+	ExitCode  int
+}
+
+// BackgroundService runs commands that keep going after the bash tool
+// returns, like dev servers or watchers. Processes belong to a session and
+// are identified by their name in it.
+type BackgroundService interface {
+	pubsub.Suscriber[BackgroundProcess]
+	Start(sessionID, name, command string) (BackgroundProcess, error)
+	// ReadOutput returns the combined stdout and stderr written since the
+	// previous read.
+	ReadOutput(sessionID, name string) (BackgroundProcess, string, error)
+	// Wait waits until the process exits or the timeout passes.
+	Wait(ctx context.Context, sessionID, name string, timeout time.Duration) (BackgroundProcess, error)
+	// Kill stops the process and forgets it, returning its unread output.
+	Kill(sessionID, name string) (BackgroundProcess, string, error)
+	List(sessionID string) []BackgroundProcess
+	// Shutdown kills every process.
+	Shutdown()
+}
+
+type backgroundProcess struct {
+	mu   sync.Mutex
+	info BackgroundProcess
+	cmd  *exec.Cmd
+	done chan struct{}
+
+	output []byte
+	// Bytes dropped from the start of output and the offset of the next
+	// read, both counted from the start of the process output
+	dropped int
+	read    int
+}
+
+// Write collects the output of the process.
+func (p *backgroundProcess) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.output = append(p.output, b...)
+	if over := len(p.output) - maxBackgroundOutput; over > 0 {
+		p.output = append(p.output[:0], p.output[over:]...)
+		p.dropped += over
+	}
+	return len(b), nil
+}
+
+func (p *backgroundProcess) unread() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	start := p.read
+	skipped := 0
+	if start < p.dropped {
+		skipped = p.dropped - start
+		start = p.dropped
+	}
+	text := string(p.output[start-p.dropped:])
+	p.read = p.dropped + len(p.output)
+	if skipped > 0 {
+		text = fmt.Sprintf("[%d bytes of older output were dropped]\n", skipped) + text
+	}
+	return text
+}
+
+func (p *backgroundProcess) snapshot() BackgroundProcess {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.info
+}
+
+type backgroundService struct {
+	*pubsub.Broker[BackgroundProcess]
+
+	mu        sync.Mutex
+	processes map[string]*backgroundProcess
+	// Names of processes being started, they are added to processes once
+	// they run
+	starting map[string]bool
+}
+
+func NewBackgroundService() BackgroundService {
+	return &backgroundService{
+		Broker:    pubsub.NewBroker[BackgroundProcess](),
+		processes: make(map[string]*backgroundProcess),
+		starting:  make(map[string]bool),
+	}
+}
+
+func processKey(sessionID, name string) string {
+	return sessionID + "/" + name
+}
+
+// currentDir is the working directory of the persistent shell, so background
+// commands start where foreground ones run. It waits for a command the shell
+// is running to finish, as that can change the directory.
+func currentDir() string {
+	if shellInstance != nil {
+		if cwd, alive := shellInstance.workingDir(); alive {
+			return cwd
+		}
+	}
+	if config.Get() == nil {
+		return ""
+	}
+	return config.WorkingDirectory()
+}
+
+func (s *backgroundService) Start(sessionID, name, command string) (BackgroundProcess, error) {
+	key := processKey(sessionID, name)
+	// The name is reserved while the process starts, without holding the lock
+	// while currentDir waits for the persistent shell
+	s.mu.Lock()
+	if existing, ok := s.processes[key]; (ok && existing.snapshot().Running) || s.starting[key] {
+		s.mu.Unlock()
+		return BackgroundProcess{}, fmt.Errorf("a process named %s is already running", name)
+	}
+	s.starting[key] = true
+	s.mu.Unlock()
+
+	process, err := s.start(sessionID, name, command)
+
+	s.mu.Lock()
+	delete(s.starting, key)
+	if err != nil {
+		s.mu.Unlock()
+		return BackgroundProcess{}, err
+	}
+	// An exited process is replaced by the new one with the same name
+	replaced, hasReplaced := s.processes[key]
+	s.processes[key] = process
+	s.mu.Unlock()
+	if hasReplaced {
+		s.Publish(pubsub.DeletedEvent, replaced.snapshot())
+	}
+
+	go func() {
+		err := process.cmd.Wait()
+		process.mu.Lock()
+		process.info.Running = false
+		process.info.ExitCode = process.cmd.ProcessState.ExitCode()
+		process.mu.Unlock()
+		logging.Debug("Background process exited", "name", name, "error", err)
+		s.Publish(pubsub.UpdatedEvent, process.snapshot())
+		close(process.done)
+	}()
+
+	info := process.snapshot()
+	s.Publish(pubsub.CreatedEvent, info)
+	return info, nil
+}
+
+// start runs the command in the directory of the persistent shell.
+func (s *backgroundService) start(sessionID, name, command string) (*backgroundProcess, error) {
+	shellPath, shellArgs := shellCommand()
+	argv, err := sandboxed(append(append([]string{shellPath}, shellArgs...), "-c", command))
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Dir = currentDir()
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+	startProcessGroup(cmd)
+	// Children that inherited the output pipes don't keep Wait from returning
+	cmd.WaitDelay = time.Second
+
+	process := &backgroundProcess{
+		cmd:  cmd,
+		done: make(chan struct{}),
+	}
+	cmd.Stdout = process
+	cmd.Stderr = process
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start process: %w", err)
+	}
+	process.info = BackgroundProcess{
+		SessionID: sessionID,
+		Name:      name,
+		Command:   command,
+		PID:       cmd.Process.Pid,
+		StartedAt: time.Now().Unix(),
+		Running:   true,
+	}
+	return process, nil
+}
+
+func (s *backgroundService) get(sessionID, name string) (*backgroundProcess, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	process, ok := s.processes[processKey(sessionID, name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrProcessNotFound, name)
+	}
+	return process, nil
+}
+
+func (s *backgroundService) ReadOutput(sessionID, name string) (BackgroundProcess, string, error) {
+	process, err := s.get(sessionID, name)
+	if err != nil {
+		return BackgroundProcess{}, "", err
+	}
+	return process.snapshot(), process.unread(), nil
+}
+
+func (s *backgroundService) Wait(ctx context.Context, sessionID, name string, timeout time.Duration) (BackgroundProcess, error) {
+	process, err := s.get(sessionID, name)
+	if err != nil {
+		return BackgroundProcess{}, err
+	}
+	select {
+	case <-process.done:
+	case <-time.After(timeout):
+	case <-ctx.Done():
+	}
+	return process.snapshot(), nil
+}
+
+func (s *backgroundService) Kill(sessionID, name string) (BackgroundProcess, string, error) {
+	process, err := s.get(sessionID, name)
+	if err != nil {
+		return BackgroundProcess{}, "", err
+	}
+	terminate(process)
+
+	s.mu.Lock()
+	key := processKey(sessionID, name)
+	if s.processes[key] == process {
+		delete(s.processes, key)
+	}
+	s.mu.Unlock()
+
+	info := process.snapshot()
+	s.Publish(pubsub.DeletedEvent, info)
+	return info, process.unread(), nil
+}
+
+// terminate stops the process group and kills it if it is still running
+// after backgroundKillTimeout.
+func terminate(process *backgroundProcess) {
+	select {
+	case <-process.done:
+		return
+	default:
+	}
+	pid := process.cmd.Process.Pid
+	if err := stopProcessGroup(pid); err != nil {
+		logging.Debug("Error terminating background process", "pid", pid, "error", err)
+	}
+	select {
+	case <-process.done:
+	case <-time.After(backgroundKillTimeout):
+		killProcessGroup(pid)
+		<-process.done
+	}
+}
+
+func (s *backgroundService) List(sessionID string) []BackgroundProcess {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var processes []BackgroundProcess
+	for _, process := range s.processes {
+		if info := process.snapshot(); info.SessionID == sessionID {
+			processes = append(processes, info)
+		}
+	}
+	slices.SortFunc(processes, func(a, b BackgroundProcess) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return processes
+}
+
+func (s *backgroundService) Shutdown() {
+	s.mu.Lock()
+	processes := make([]*backgroundProcess, 0, len(s.processes))
+	for _, process := range s.processes {
+		processes = append(processes, process)
+	}
+	s.processes = make(map[string]*backgroundProcess)
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, process := range processes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			terminate(process)
+		}()
+	}
+	wg.Wait()
+	s.Broker.Shutdown()
+}
@@ -0,0 +1,91 @@
+package shell
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackgroundService(t *testing.T) {
+	s := NewBackgroundService()
+	defer s.Shutdown()
+
+	_, err := s.Start("session", "echo", "echo one; echo two >&2")
+	require.NoError(t, err)
+	info, err := s.Wait(context.Background(), "session", "echo", 5*time.Second)
+	require.NoError(t, err)
+	assert.False(t, info.Running)
+	assert.Equal(t, 0, info.ExitCode)
+
+	_, output, err := s.ReadOutput("session", "echo")
+	require.NoError(t, err)
+	// Login shells can print their own output first
+	assert.True(t, strings.HasSuffix(output, "one\ntwo\n"), output)
+	// Output is only returned once
+	_, output, err = s.ReadOutput("session", "echo")
+	require.NoError(t, err)
+	assert.Empty(t, output)
+
+	info, err = s.Start("session", "sleep", "sleep 30")
+	require.NoError(t, err)
+	assert.True(t, info.Running)
+	_, err = s.Start("session", "sleep", "sleep 30")
+	assert.Error(t, err)
+	assert.Empty(t, s.List("other"))
+	assert.Len(t, s.List("session"), 2)
+
+	info, _, err = s.Kill("session", "sleep")
+	require.NoError(t, err)
+	assert.False(t, info.Running)
+	_, _, err = s.ReadOutput("session", "sleep")
+	assert.ErrorIs(t, err, ErrProcessNotFound)
+}
+
+func TestBackgroundOutputLimit(t *testing.T) {
+	process := &backgroundProcess{}
+	process.Write(make([]byte, maxBackgroundOutput))
+	process.Write([]byte("tail"))
+	output := process.unread()
+	assert.Contains(t, output, "[4 bytes of older output were dropped]")
+	assert.True(t, len(output) > maxBackgroundOutput)
+	assert.Equal(t, "tail", output[len(output)-4:])
+}
+
+func TestBackgroundServiceStartsOneProcessPerName(t *testing.T) {
+	s := NewBackgroundService()
+	defer s.Shutdown()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	started := 0
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.Start("session", "sleep", "sleep 30"); err == nil {
+				mu.Lock()
+				started++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, started)
+	assert.Len(t, s.List("session"), 1)
+
+	// An exited process is replaced
+	_, err := s.Start("session", "exit", "exit 3")
+	require.NoError(t, err)
+	info, err := s.Wait(context.Background(), "session", "exit", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 3, info.ExitCode)
+	info, err = s.Start("session", "exit", "sleep 30")
+	require.NoError(t, err)
+	assert.True(t, info.Running)
+	assert.Len(t, s.List("session"), 2)
+}
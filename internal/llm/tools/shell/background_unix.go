@@ -0,0 +1,24 @@
+//go:build !windows
+
+package shell
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// startProcessGroup makes the command the leader of its own process group,
+// so killing it also kills what it started.
+func startProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// stopProcessGroup asks the process group of pid to exit with SIGTERM.
+func stopProcessGroup(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGTERM)
+}
+
+// killProcessGroup kills the process group of pid with SIGKILL.
+func killProcessGroup(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGKILL)
+}
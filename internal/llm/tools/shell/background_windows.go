@@ -0,0 +1,23 @@
+//go:build windows
+
+package shell
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// startProcessGroup does nothing on Windows, where taskkill finds the
+// processes the command started by itself.
+func startProcessGroup(cmd *exec.Cmd) {}
+
+// stopProcessGroup kills the process of pid and the processes it started.
+// Windows has no signal to ask them to exit.
+func stopProcessGroup(pid int) error {
+	return killProcessGroup(pid)
+}
+
+// killProcessGroup kills the process of pid and the processes it started.
+func killProcessGroup(pid int) error {
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(pid)).Run()
+}
@@ -0,0 +1,333 @@
+package shell
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// CommandPart is one simple command of a command line, like each side of a
+// pipe, each command of a list or a command in a substitution. Output
+// redirections to files are parts of their own.
+type CommandPart struct {
+	// The command as written
+	Text string
+	// Words of the command with quotes removed, nil when the command name
+	// comes from an expansion like $cmd and can't be known before it runs
+	Args []string
+	// The file written by an output redirection
+	WritesTo string
+}
+
+// Name is the base name of the command, or "" when it isn't known.
+func (p CommandPart) Name() string {
+	if len(p.Args) == 0 {
+		return ""
+	}
+	return filepath.Base(p.Args[0])
+}
+
+// Commands that run the command given in their arguments, with the options
+// that take a value
+var wrapperCommands = map[string][]string{
+	"builtin": nil,
+	"command": nil,
+	"doas":    {"-u", "-C"},
+	"env":     {"-u", "-C", "-S"},
+	"exec":    {"-a"},
+	"nice":    {"-n"},
+	"nohup":   nil,
+	"stdbuf":  {"-i", "-o", "-e"},
+	"sudo":    {"-u", "-g", "-C", "-D", "-h", "-p", "-r", "-t", "-U"},
+	"time":    {"-f", "-o"},
+	"timeout": {"-k", "-s"},
+	"xargs":   {"-a", "-d", "-E", "-I", "-L", "-n", "-P", "-s"},
+}
+
+// Shells whose -c argument is parsed as a command line too
+var shellCommands = []string{"bash", "sh", "zsh", "dash", "ksh"}
+
+// Redirection targets that don't write files
+var harmlessTargets = []string{"/dev/null", "/dev/stdout", "/dev/stderr", "/dev/tty"}
+
+// Commands that only prepare the environment of the ones after them
+var setupCommands = []string{"cd", "pushd", "popd", "export", "set", "unset", "source", ".", "true", ":"}
+
+// MainCommand returns the name of the first command of a command line that
+// does more than prepare the environment or run another command line, like
+// npm in "cd web && FOO=1 npm run dev", or "" when there is none.
+func MainCommand(command string) string {
+	parts, err := ParseCommand(command)
+	if err != nil {
+		return ""
+	}
+	for _, part := range parts {
+		name := part.Name()
+		if name == "" || slices.Contains(setupCommands, name) {
+			continue
+		}
+		if _, ok := wrapperCommands[name]; ok {
+			continue
+		}
+		if _, ok := shellScript(part.Args[1:]); ok && slices.Contains(shellCommands, name) {
+			continue
+		}
+		return name
+	}
+	return ""
+}
+
+// ParseCommand splits a command line into the simple commands it runs, so
+// each can be checked on its own.
+func ParseCommand(command string) ([]CommandPart, error) {
+	file, err := syntax.NewParser(syntax.Variant(syntax.LangBash)).Parse(strings.NewReader(command), "")
+	if err != nil {
+		return nil, err
+	}
+
+	var parts []CommandPart
+	var walkErr error
+	syntax.Walk(file, func(node syntax.Node) bool {
+		switch n := node.(type) {
+		case *syntax.Stmt:
+			for _, redirect := range n.Redirs {
+				if target, ok := writeTarget(redirect); ok {
+					parts = append(parts, CommandPart{Text: fmt.Sprintf("%s %s", redirect.Op, printNode(redirect.Word)), WritesTo: target})
+				}
+			}
+		case *syntax.CallExpr:
+			if len(n.Args) == 0 {
+				return true
+			}
+			callParts, err := parseCall(printNode(n), n.Args)
+			if err != nil {
+				walkErr = err
+				return false
+			}
+			parts = append(parts, callParts...)
+		}
+		return true
+	})
+	if walkErr != nil {
+		return nil, walkErr
+	}
+	return parts, nil
+}
+
+// parseCall returns the part for a call and the parts of the commands it
+// runs in turn, like the command of sudo or the script of bash -c.
+func parseCall(text string, words []*syntax.Word) ([]CommandPart, error) {
+	args := make([]string, 0, len(words))
+	for _, word := range words {
+		arg, ok := literal(word)
+		if !ok {
+			if len(args) == 0 {
+				// The command itself is unknown
+				return []CommandPart{{Text: text}}, nil
+			}
+			arg = printNode(word)
+		}
+		args = append(args, arg)
+	}
+	parts := []CommandPart{{Text: text, Args: args}}
+	inner, err := innerCommand(args)
+	if err != nil {
+		return nil, err
+	}
+	return append(parts, inner...), nil
+}
+
+func innerCommand(args []string) ([]CommandPart, error) {
+	name := filepath.Base(args[0])
+	switch {
+	case name == "eval":
+		if len(args) > 1 {
+			return ParseCommand(strings.Join(args[1:], " "))
+		}
+	case slices.Contains(shellCommands, name):
+		if script, ok := shellScript(args[1:]); ok {
+			return ParseCommand(script)
+		}
+	case name == "find":
+		return findCommands(args[1:])
+	default:
+		valueOptions, ok := wrapperCommands[name]
+		if !ok {
+			return nil, nil
+		}
+		rest := skipOptions(name, args[1:], valueOptions)
+		if len(rest) == 0 {
+			return nil, nil
+		}
+		part := CommandPart{Text: strings.Join(rest, " "), Args: rest}
+		inner, err := innerCommand(rest)
+		if err != nil {
+			return nil, err
+		}
+		return append([]CommandPart{part}, inner...), nil
+	}
+	return nil, nil
+}
+
+// shellScript returns the command line a shell runs with -c. The flag can
+// be combined with others like in bash -lc, the command line is the first
+// argument that isn't an option.
+func shellScript(args []string) (string, bool) {
+	hasC := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--" || arg == "-":
+			if i+1 < len(args) {
+				return args[i+1], hasC
+			}
+			return "", false
+		case arg == "-o" || arg == "+o" || arg == "-O" || arg == "+O" || arg == "--rcfile" || arg == "--init-file":
+			// The option takes a value
+			i++
+		case strings.HasPrefix(arg, "--"):
+		case strings.HasPrefix(arg, "-"):
+			hasC = hasC || strings.Contains(arg[1:], "c")
+		case strings.HasPrefix(arg, "+"):
+		default:
+			return arg, hasC
+		}
+	}
+	return "", false
+}
+
+// findCommands returns the commands find runs for the files it finds, the
+// arguments of -exec and -execdir up to the closing ; or +.
+func findCommands(args []string) ([]CommandPart, error) {
+	var parts []CommandPart
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-exec", "-execdir", "-ok", "-okdir":
+		default:
+			continue
+		}
+		end := i + 1
+		for end < len(args) && args[end] != ";" && args[end] != "+" {
+			end++
+		}
+		command := args[i+1 : end]
+		i = end
+		if len(command) == 0 {
+			continue
+		}
+		parts = append(parts, CommandPart{Text: strings.Join(command, " "), Args: command})
+		inner, err := innerCommand(command)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, inner...)
+	}
+	return parts, nil
+}
+
+// skipOptions drops the options of a wrapper command, and its other
+// arguments before the wrapped command.
+func skipOptions(name string, args, valueOptions []string) []string {
+	for len(args) > 0 {
+		arg := args[0]
+		switch {
+		case arg == "--":
+			return args[1:]
+		case strings.HasPrefix(arg, "-"):
+			args = args[1:]
+			if slices.Contains(valueOptions, arg) && len(args) > 0 {
+				args = args[1:]
+			}
+		case name == "env" && strings.Contains(arg, "="):
+			args = args[1:]
+		case name == "timeout":
+			// The duration
+			return args[1:]
+		default:
+			return args
+		}
+	}
+	return args
+}
+
+// literal returns the value of a word without quotes and escapes when it
+// has no expansions.
+func literal(word *syntax.Word) (string, bool) {
+	var sb strings.Builder
+	for _, part := range word.Parts {
+		switch p := part.(type) {
+		case *syntax.Lit:
+			sb.WriteString(unescape(p.Value, ""))
+		case *syntax.SglQuoted:
+			if p.Dollar {
+				// $'...' has escapes of its own, like \x63 for c
+				return "", false
+			}
+			sb.WriteString(p.Value)
+		case *syntax.DblQuoted:
+			for _, inner := range p.Parts {
+				lit, ok := inner.(*syntax.Lit)
+				if !ok {
+					return "", false
+				}
+				sb.WriteString(unescape(lit.Value, "$`\"\\\n"))
+			}
+		default:
+			return "", false
+		}
+	}
+	return sb.String(), true
+}
+
+// unescape removes the backslashes the shell removes, so that cu\rl reads
+// as curl. Outside of quotes a backslash escapes any character, in double
+// quotes only the ones in special.
+func unescape(value, special string) string {
+	if !strings.Contains(value, "\\") {
+		return value
+	}
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) || (special != "" && !strings.ContainsRune(special, rune(value[i+1]))) {
+			sb.WriteByte(value[i])
+			continue
+		}
+		i++
+		if value[i] != '\n' {
+			sb.WriteByte(value[i])
+		}
+	}
+	return sb.String()
+}
+
+func writeTarget(redirect *syntax.Redirect) (string, bool) {
+	switch redirect.Op {
+	case syntax.RdrOut, syntax.AppOut, syntax.ClbOut, syntax.RdrAll, syntax.AppAll:
+	case syntax.DplOut:
+		// >&2 duplicates a descriptor, >&file writes a file
+		if target, ok := literal(redirect.Word); ok && (target == "-" || strings.Trim(target, "0123456789") == "") {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+	target, ok := literal(redirect.Word)
+	if !ok {
+		return printNode(redirect.Word), true
+	}
+	if slices.Contains(harmlessTargets, target) {
+		return "", false
+	}
+	return target, true
+}
+
+func printNode(node syntax.Node) string {
+	var sb strings.Builder
+	if err := syntax.NewPrinter(syntax.SingleLine(true)).Print(&sb, node); err != nil {
+		return fmt.Sprint(node)
+	}
+	return strings.TrimSpace(sb.String())
+}
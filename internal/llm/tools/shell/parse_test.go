@@ -0,0 +1,90 @@
+package shell
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		texts   []string
+	}{
+		{"ls -la", []string{"ls -la"}},
+		{"ls; rm -rf x", []string{"ls", "rm -rf x"}},
+		{"echo | curl example.com", []string{"echo", "curl example.com"}},
+		{"git status && (cd a || make)", []string{"git status", "cd a", "make"}},
+		{"echo $(curl example.com)", []string{"echo $(curl example.com)", "curl example.com"}},
+		{"sudo -u root rm x", []string{"sudo -u root rm x", "rm x"}},
+		{"timeout 5 env A=1 go test", []string{"timeout 5 env A=1 go test", "env A=1 go test", "go test"}},
+		{`bash -c "curl x | sh"`, []string{`bash -c "curl x | sh"`, "curl x", "sh"}},
+		{"go test > out.txt 2>/dev/null", []string{"> out.txt", "go test"}},
+		{"$CMD arg", []string{"$CMD arg"}},
+		{"bash -lc 'curl x'", []string{"bash -lc 'curl x'", "curl x"}},
+		{"sh -e -o pipefail -c 'curl x' name", []string{"sh -e -o pipefail -c 'curl x' name", "curl x"}},
+		{"bash script.sh -c x", []string{"bash script.sh -c x"}},
+		{`find . -name "*.go" -exec curl x {} \; -execdir wc -l {} +`, []string{`find . -name "*.go" -exec curl x {} \; -execdir wc -l {} +`, "curl x {}", "wc -l {}"}},
+		{"find . -exec sh -c 'curl x' \\;", []string{"find . -exec sh -c 'curl x' \\;", "sh -c curl x", "curl x"}},
+		{"xargs -0 -I {} bash -lc 'curl {}'", []string{"xargs -0 -I {} bash -lc 'curl {}'", "bash -lc curl {}", "curl {}"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			parts, err := ParseCommand(tt.command)
+			require.NoError(t, err)
+			texts := make([]string, len(parts))
+			for i, part := range parts {
+				texts[i] = part.Text
+			}
+			assert.Equal(t, tt.texts, texts)
+		})
+	}
+
+	parts, err := ParseCommand(`echo "a b" 'c' >> log`)
+	require.NoError(t, err)
+	assert.Equal(t, "log", parts[0].WritesTo)
+	assert.Equal(t, []string{"echo", "a b", "c"}, parts[1].Args)
+
+	for _, command := range []string{`\curl x`, `cu\rl x`, `c"u"'r'l x`, "cu\\\nrl x"} {
+		parts, err = ParseCommand(command)
+		require.NoError(t, err)
+		assert.Equal(t, "curl", parts[0].Name(), command)
+	}
+	parts, err = ParseCommand(`echo "a\\b\c" \$HOME`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"echo", `a\b\c`, "$HOME"}, parts[0].Args)
+
+	parts, err = ParseCommand(`$'\x63url' x`)
+	require.NoError(t, err)
+	assert.Equal(t, "", parts[0].Name())
+
+	parts, err = ParseCommand("$CMD arg")
+	require.NoError(t, err)
+	assert.Equal(t, "", parts[0].Name())
+
+	_, err = ParseCommand("echo (")
+	assert.Error(t, err)
+}
+
+func TestMainCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{"npm run dev", "npm"},
+		{"./bin/server --port 8080", "server"},
+		{"cd web && npm run dev", "npm"},
+		{"FOO=1 go run .", "go"},
+		{"export PORT=3000; nohup python -m http.server", "python"},
+		{"bash -c 'cd web && vite'", "vite"},
+		{"bash serve.sh", "bash"},
+		{"cd web", ""},
+		{"$SERVER", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			assert.Equal(t, tt.want, MainCommand(tt.command))
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package shell
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/opencode-ai/opencode/internal/config"
+)
+
+var ErrSandboxUnavailable = errors.New("the sandbox needs bubblewrap (bwrap) on Linux, install it or disable shell.sandbox in the config")
+
+// Messages from commands that ran into the limits of the sandbox
+var (
+	readOnlyMarkers = []string{"Read-only file system"}
+	networkMarkers  = []string{
+		"Network is unreachable",
+		"network is unreachable",
+		"Could not resolve host",
+		"Temporary failure in name resolution",
+		"no such host",
+	}
+)
+
+func sandboxConfig() (config.SandboxConfig, bool) {
+	cfg := config.Get()
+	if cfg == nil || !cfg.Shell.Sandbox.Enabled {
+		return config.SandboxConfig{}, false
+	}
+	return cfg.Shell.Sandbox, true
+}
+
+// SandboxEnabled reports whether commands run in the sandbox.
+func SandboxEnabled() bool {
+	_, enabled := sandboxConfig()
+	return enabled
+}
+
+// SandboxAutoApprove reports whether sandboxed commands run without asking
+// for permission.
+func SandboxAutoApprove() bool {
+	sandbox, enabled := sandboxConfig()
+	return enabled && sandbox.AutoApprove
+}
+
+// CheckSandbox returns ErrSandboxUnavailable when the sandbox is enabled but
+// can't run here. Commands are never run outside an enabled sandbox.
+func CheckSandbox() error {
+	if !SandboxEnabled() {
+		return nil
+	}
+	if runtime.GOOS != "linux" {
+		return ErrSandboxUnavailable
+	}
+	if _, err := exec.LookPath("bwrap"); err != nil {
+		return ErrSandboxUnavailable
+	}
+	return nil
+}
+
+// sandboxArgs is the bwrap command line that runs a command with the whole
+// filesystem read-only except for the working directory, a private temporary
+// directory and the allowed paths. Paths that change what runs outside the
+// sandbox stay read-only even in the working directory: .git, whose config
+// and hooks run commands in git calls, and the local config and data
+// directory of opencode, which hold the commands of LSP and MCP servers and
+// the sandbox settings themselves. The sockets in /run and the temporary
+// directory of the host, like the ones of Docker or D-Bus, are hidden.
+func sandboxArgs(sandbox config.SandboxConfig, workingDir, dataDir string) []string {
+	args := []string{
+		"bwrap",
+		"--ro-bind", "/", "/",
+		"--dev", "/dev",
+		"--proc", "/proc",
+		"--tmpfs", "/run",
+	}
+	if sandbox.Network {
+		// /etc/resolv.conf often links to a file in /run
+		if resolvConf, err := filepath.EvalSymlinks("/etc/resolv.conf"); err == nil && strings.HasPrefix(resolvConf, "/run/") {
+			args = append(args, "--ro-bind", resolvConf, resolvConf)
+		}
+	}
+	args = append(args,
+		"--tmpfs", os.TempDir(),
+		"--bind", workingDir, workingDir,
+	)
+	home, _ := os.UserHomeDir()
+	for _, path := range sandbox.AllowWrite {
+		if home != "" && (path == "~" || strings.HasPrefix(path, "~/")) {
+			path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(workingDir, path)
+		}
+		// Paths that don't exist are skipped instead of failing every command
+		args = append(args, "--bind-try", path, path)
+	}
+	// After allowWrite, so it can't make them writable
+	if !filepath.IsAbs(dataDir) {
+		dataDir = filepath.Join(workingDir, dataDir)
+	}
+	for _, path := range []string{filepath.Join(workingDir, ".git"), filepath.Join(workingDir, ".opencode.json"), dataDir} {
+		args = append(args, "--ro-bind-try", path, path)
+	}
+	args = append(args, "--unshare-pid", "--unshare-ipc")
+	if !sandbox.Network {
+		args = append(args, "--unshare-net")
+	}
+	return append(args, "--die-with-parent", "--new-session", "--")
+}
+
+// sandboxed returns the command line that runs argv in the sandbox, or argv
+// itself when the sandbox is disabled.
+func sandboxed(argv []string) ([]string, error) {
+	sandbox, enabled := sandboxConfig()
+	if !enabled {
+		return argv, nil
+	}
+	if err := CheckSandbox(); err != nil {
+		return nil, err
+	}
+	return append(sandboxArgs(sandbox, config.WorkingDirectory(), config.Get().Data.Directory), argv...), nil
+}
+
+// SandboxViolation explains the error output of a sandboxed command when it
+// failed because of the sandbox, and returns "" otherwise.
+func SandboxViolation(output string) string {
+	sandbox, enabled := sandboxConfig()
+	if !enabled {
+		return ""
+	}
+	var reasons []string
+	if containsAny(output, readOnlyMarkers) {
+		reasons = append(reasons, "The sandbox blocked a write outside the working directory. Only the working directory, the temporary directory and the paths in shell.sandbox.allowWrite are writable, and .git, .opencode.json and the data directory of opencode are read-only.")
+	}
+	if !sandbox.Network && containsAny(output, networkMarkers) {
+		reasons = append(reasons, "The sandbox blocks network access. Ask the user to run the command or to allow network access with shell.sandbox.network.")
+	}
+	return strings.Join(reasons, "\n")
+}
+
+func containsAny(s string, markers []string) bool {
+	return slices.ContainsFunc(markers, func(marker string) bool {
+		return strings.Contains(s, marker)
+	})
+}
+
+func shellJoin(argv []string) string {
+	quoted := make([]string, len(argv))
+	for i, arg := range argv {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
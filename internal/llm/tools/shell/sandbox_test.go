@@ -0,0 +1,41 @@
+package shell
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSandboxArgs(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+
+	args := sandboxArgs(config.SandboxConfig{AllowWrite: []string{"~/.cache", "build", ".git"}}, "/work", ".opencode")
+	assert.Equal(t, []string{
+		"bwrap",
+		"--ro-bind", "/", "/",
+		"--dev", "/dev",
+		"--proc", "/proc",
+		"--tmpfs", "/run",
+		"--tmpfs", os.TempDir(),
+		"--bind", "/work", "/work",
+		"--bind-try", filepath.Join(home, ".cache"), filepath.Join(home, ".cache"),
+		"--bind-try", "/work/build", "/work/build",
+		"--bind-try", "/work/.git", "/work/.git",
+		"--ro-bind-try", "/work/.git", "/work/.git",
+		"--ro-bind-try", "/work/.opencode.json", "/work/.opencode.json",
+		"--ro-bind-try", "/work/.opencode", "/work/.opencode",
+		"--unshare-pid", "--unshare-ipc",
+		"--unshare-net",
+		"--die-with-parent", "--new-session", "--",
+	}, args)
+
+	args = sandboxArgs(config.SandboxConfig{Network: true}, "/work", "/data/opencode")
+	assert.NotContains(t, args, "--unshare-net")
+	assert.Contains(t, args, "/data/opencode")
+}
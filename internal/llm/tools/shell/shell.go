@@ -58,7 +58,8 @@ func GetPersistentShell(workingDir string) *PersistentShell {
 	return shellInstance
 }
 
-func newPersistentShell(cwd string) *PersistentShell {
+// shellCommand returns the configured shell and its arguments.
+func shellCommand() (string, []string) {
 	// Get shell configuration from config
 	cfg := config.Get()
 	
@@ -82,6 +83,11 @@ func newPersistentShell(cwd string) *PersistentShell {
 	if len(shellArgs) == 0 {
 		shellArgs = []string{"-l"}
 	}
+	return shellPath, shellArgs
+}
+
+func newPersistentShell(cwd string) *PersistentShell {
+	shellPath, shellArgs := shellCommand()
 
 	cmd := exec.Command(shellPath, shellArgs...)
 	cmd.Dir = cwd
@@ -148,6 +154,21 @@ func (s *PersistentShell) execCommand(command string, timeout time.Duration, ctx
 		}
 	}
 
+	if SandboxEnabled() {
+		// The sandbox inherits the environment and the directory of the
+		// shell, but changes made in it don't come back
+		shellPath, _ := shellCommand()
+		argv, err := sandboxed([]string{shellPath, "-c", command})
+		if err != nil {
+			return commandResult{
+				stderr:   err.Error(),
+				exitCode: 1,
+				err:      err,
+			}
+		}
+		command = shellJoin(argv)
+	}
+
 	tempDir := os.TempDir()
 	stdoutFile := filepath.Join(tempDir, fmt.Sprintf("opencode-stdout-%d", time.Now().UnixNano()))
 	stderrFile := filepath.Join(tempDir, fmt.Sprintf("opencode-stderr-%d", time.Now().UnixNano()))
@@ -287,6 +308,14 @@ func (s *PersistentShell) Exec(ctx context.Context, command string, timeoutMs in
 	return result.stdout, result.stderr, result.exitCode, result.interrupted, result.err
 }
 
+// workingDir returns the current directory of the shell, and false when the
+// shell has exited.
+func (s *PersistentShell) workingDir() (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cwd, s.isAlive
+}
+
 func (s *PersistentShell) Close() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -6,9 +6,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/httpclient"
 )
 
 type SourcegraphParams struct {
@@ -29,7 +33,9 @@ type sourcegraphTool struct {
 
 const (
 	SourcegraphToolName        = "sourcegraph"
-	sourcegraphToolDescription = `Search code across public repositories using Sourcegraph's GraphQL API.
+	defaultSourcegraphEndpoint = "https://sourcegraph.com"
+	maxSourcegraphErrorBody    = 500
+	sourcegraphToolDescription = `Search code across %s using Sourcegraph's GraphQL API.%s
 
 WHEN TO USE THIS TOOL:
 - Use when you need to find code examples or implementations across public repositories
@@ -113,7 +119,7 @@ BOOLEAN OPERATORS:
 - "term1 and (term2 or term3)" - Grouping with parentheses
 
 LIMITATIONS:
-- Only searches public repositories
+- Only searches %s
 - Rate limits may apply
 - Complex queries may take longer to execute
 - Maximum of 20 results per query
@@ -127,16 +133,44 @@ TIPS:
 
 func NewSourcegraphTool() BaseTool {
 	return &sourcegraphTool{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		client: httpclient.New(httpclient.Timeout(30 * time.Second)),
+	}
+}
+
+func sourcegraphConfig() config.SourcegraphConfig {
+	cfg := config.Get()
+	if cfg == nil {
+		return config.SourcegraphConfig{}
+	}
+	return cfg.Sourcegraph
+}
+
+// sourcegraphEndpoint returns the base URL of the instance without a
+// trailing slash.
+func sourcegraphEndpoint(cfg config.SourcegraphConfig) string {
+	if cfg.Endpoint == "" {
+		return defaultSourcegraphEndpoint
+	}
+	return strings.TrimRight(cfg.Endpoint, "/")
+}
+
+func sourcegraphDescription(cfg config.SourcegraphConfig) string {
+	endpoint := sourcegraphEndpoint(cfg)
+	if endpoint == defaultSourcegraphEndpoint {
+		return fmt.Sprintf(sourcegraphToolDescription, "public repositories", "", "public repositories")
+	}
+	instance := fmt.Sprintf("the repositories of the Sourcegraph instance at %s", endpoint)
+	var filters string
+	if len(cfg.RepoFilters) > 0 {
+		filters = fmt.Sprintf("\n\nQueries without a repo: or context: filter are limited to: %s", strings.Join(cfg.RepoFilters, " "))
 	}
+	return fmt.Sprintf(sourcegraphToolDescription, instance, filters, instance)
 }
 
 func (t *sourcegraphTool) Info() ToolInfo {
 	return ToolInfo{
 		Name:        SourcegraphToolName,
-		Description: sourcegraphToolDescription,
+		Description: sourcegraphDescription(sourcegraphConfig()),
 		Parameters: map[string]any{
 			"query": map[string]any{
 				"type":        "string",
@@ -184,11 +218,30 @@ func (t *sourcegraphTool) Run(ctx context.Context, call ToolCall) (ToolResponse,
 		if params.Timeout > maxTimeout {
 			params.Timeout = maxTimeout
 		}
-		client = &http.Client{
-			Timeout: time.Duration(params.Timeout) * time.Second,
+		client = httpclient.New(time.Duration(params.Timeout) * time.Second)
+	}
+
+	return searchSourcegraph(ctx, client, sourcegraphConfig(), params)
+}
+
+// sourcegraphQuery adds the configured filters to queries that don't
+// choose repositories themselves.
+func sourcegraphQuery(query string, filters []string) string {
+	if len(filters) == 0 {
+		return query
+	}
+	for _, term := range strings.Fields(query) {
+		term = strings.TrimPrefix(term, "-")
+		for _, prefix := range []string{"repo:", "r:", "context:"} {
+			if strings.HasPrefix(term, prefix) {
+				return query
+			}
 		}
 	}
+	return strings.Join(filters, " ") + " " + query
+}
 
+func searchSourcegraph(ctx context.Context, client *http.Client, cfg config.SourcegraphConfig, params SourcegraphParams) (ToolResponse, error) {
 	type graphqlRequest struct {
 		Query     string `json:"query"`
 		Variables struct {
@@ -199,52 +252,64 @@ func (t *sourcegraphTool) Run(ctx context.Context, call ToolCall) (ToolResponse,
 	request := graphqlRequest{
 		Query: "query Search($query: String!) { search(query: $query, version: V2, patternType: keyword ) { results { matchCount, limitHit, resultCount, approximateResultCount, missing { name }, timedout { name }, indexUnavailable, results { __typename, ... on FileMatch { repository { name }, file { path, url, content }, lineMatches { preview, lineNumber, offsetAndLengths } } } } } }",
 	}
-	request.Variables.Query = params.Query
+	request.Variables.Query = sourcegraphQuery(params.Query, cfg.RepoFilters)
 
 	graphqlQueryBytes, err := json.Marshal(request)
 	if err != nil {
 		return ToolResponse{}, fmt.Errorf("failed to marshal GraphQL request: %w", err)
 	}
-	graphqlQuery := string(graphqlQueryBytes)
 
+	endpoint := sourcegraphEndpoint(cfg)
 	req, err := http.NewRequestWithContext(
 		ctx,
 		"POST",
-		"https://sourcegraph.com/.api/graphql",
-		bytes.NewBuffer([]byte(graphqlQuery)),
+		endpoint+"/.api/graphql",
+		bytes.NewBuffer(graphqlQueryBytes),
 	)
 	if err != nil {
-		return ToolResponse{}, fmt.Errorf("failed to create request: %w", err)
+		return NewTextErrorResponse(fmt.Sprintf("Invalid Sourcegraph endpoint %q: %s", endpoint, err)), nil
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "opencode/1.0")
+	if cfg.Token != "" {
+		req.Header.Set("Authorization", "token "+cfg.Token)
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return ToolResponse{}, fmt.Errorf("failed to fetch URL: %w", err)
+		if ctx.Err() != nil {
+			return ToolResponse{}, ctx.Err()
+		}
+		return NewTextErrorResponse(fmt.Sprintf("Could not reach Sourcegraph at %s: %s", endpoint, err)), nil
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		if len(body) > 0 {
-			return NewTextErrorResponse(fmt.Sprintf("Request failed with status code: %d, response: %s", resp.StatusCode, string(body))), nil
-		}
-
-		return NewTextErrorResponse(fmt.Sprintf("Request failed with status code: %d", resp.StatusCode)), nil
-	}
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return ToolResponse{}, fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	if resp.StatusCode != http.StatusOK {
+		return NewTextErrorResponse(sourcegraphStatusError(endpoint, cfg.Token != "", resp.StatusCode, body)), nil
+	}
+
+	// Instances behind single sign-on answer with a login page instead of
+	// the API
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "application/json" {
+		return NewTextErrorResponse(fmt.Sprintf("Sourcegraph at %s answered with %s instead of JSON, the instance may need an access token or a sign-in proxy may be in the way", endpoint, mediaType)), nil
+	}
+
 	var result map[string]any
 	if err = json.Unmarshal(body, &result); err != nil {
-		return ToolResponse{}, fmt.Errorf("failed to unmarshal response: %w", err)
+		return NewTextErrorResponse(fmt.Sprintf("Sourcegraph at %s returned an invalid response: %s", endpoint, err)), nil
 	}
 
-	formattedResults, err := formatSourcegraphResults(result, params.ContextWindow)
+	if message := sourcegraphGraphQLErrors(result); message != "" {
+		return NewTextErrorResponse(message), nil
+	}
+
+	formattedResults, err := formatSourcegraphResults(result, params.ContextWindow, endpoint)
 	if err != nil {
 		return NewTextErrorResponse("Failed to format results: " + err.Error()), nil
 	}
@@ -252,20 +317,59 @@ func (t *sourcegraphTool) Run(ctx context.Context, call ToolCall) (ToolResponse,
 	return NewTextResponse(formattedResults), nil
 }
 
-func formatSourcegraphResults(result map[string]any, contextWindow int) (string, error) {
-	var buffer strings.Builder
+// sourcegraphStatusError explains a failed request, with what to change in
+// the config for the errors private instances return.
+func sourcegraphStatusError(endpoint string, hasToken bool, status int, body []byte) string {
+	var message string
+	switch status {
+	case http.StatusUnauthorized:
+		if hasToken {
+			message = fmt.Sprintf("Sourcegraph at %s rejected the access token, check that sourcegraph.token or SRC_ACCESS_TOKEN holds a valid token", endpoint)
+		} else {
+			message = fmt.Sprintf("Sourcegraph at %s requires an access token, set sourcegraph.token in the config or SRC_ACCESS_TOKEN", endpoint)
+		}
+	case http.StatusForbidden:
+		message = fmt.Sprintf("Sourcegraph at %s refused the search, the access token may lack permissions", endpoint)
+	case http.StatusNotFound:
+		message = fmt.Sprintf("No Sourcegraph API found at %s/.api/graphql, check sourcegraph.endpoint", endpoint)
+	case http.StatusTooManyRequests:
+		message = fmt.Sprintf("Sourcegraph at %s is rate limiting requests, try again later", endpoint)
+	default:
+		message = fmt.Sprintf("Request to Sourcegraph at %s failed with status code: %d", endpoint, status)
+	}
 
-	if errors, ok := result["errors"].([]any); ok && len(errors) > 0 {
-		buffer.WriteString("## Sourcegraph API Error\n\n")
-		for _, err := range errors {
-			if errMap, ok := err.(map[string]any); ok {
-				if message, ok := errMap["message"].(string); ok {
-					buffer.WriteString(fmt.Sprintf("- %s\n", message))
-				}
+	text := strings.TrimSpace(string(body))
+	if text == "" || strings.HasPrefix(text, "<") {
+		// HTML error pages don't help
+		return message
+	}
+	if len(text) > maxSourcegraphErrorBody {
+		text = text[:maxSourcegraphErrorBody] + "..."
+	}
+	return message + "\n\nResponse: " + text
+}
+
+// sourcegraphGraphQLErrors lists the errors of a GraphQL response, or
+// returns "" when there are none.
+func sourcegraphGraphQLErrors(result map[string]any) string {
+	errors, ok := result["errors"].([]any)
+	if !ok || len(errors) == 0 {
+		return ""
+	}
+	var buffer strings.Builder
+	buffer.WriteString("Sourcegraph API error:\n")
+	for _, err := range errors {
+		if errMap, ok := err.(map[string]any); ok {
+			if message, ok := errMap["message"].(string); ok {
+				buffer.WriteString(fmt.Sprintf("- %s\n", message))
 			}
 		}
-		return buffer.String(), nil
 	}
+	return strings.TrimRight(buffer.String(), "\n")
+}
+
+func formatSourcegraphResults(result map[string]any, contextWindow int, endpoint string) (string, error) {
+	var buffer strings.Builder
 
 	data, ok := result["data"].(map[string]any)
 	if !ok {
@@ -333,6 +437,10 @@ func formatSourcegraphResults(result map[string]any, contextWindow int) (string,
 		buffer.WriteString(fmt.Sprintf("## Result %d: %s/%s\n\n", i+1, repoName, filePath))
 
 		if fileURL != "" {
+			// File URLs are relative to the instance
+			if strings.HasPrefix(fileURL, "/") {
+				fileURL = endpoint + fileURL
+			}
 			buffer.WriteString(fmt.Sprintf("URL: %s\n\n", fileURL))
 		}
 
@@ -0,0 +1,123 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sourcegraphSearchResponse = `{"data": {"search": {"results": {
+  "matchCount": 1, "resultCount": 1, "limitHit": false,
+  "results": [{
+    "__typename": "FileMatch",
+    "repository": {"name": "git.example.com/team/api"},
+    "file": {"path": "server.go", "url": "/git.example.com/team/api/-/blob/server.go", "content": "package api\n\nfunc Serve() {}\n"},
+    "lineMatches": [{"preview": "func Serve() {}", "lineNumber": 3}]
+  }]
+}}}}`
+
+// newSourcegraphServer stands in for the GraphQL API of an instance and
+// records the search queries it gets.
+func newSourcegraphServer(t *testing.T, handler func(w http.ResponseWriter, r *http.Request)) (*httptest.Server, *[]string) {
+	t.Helper()
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.api/graphql" {
+			http.NotFound(w, r)
+			return
+		}
+		var request struct {
+			Variables struct {
+				Query string `json:"query"`
+			} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		queries = append(queries, request.Variables.Query)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, &queries
+}
+
+func TestSearchSourcegraph(t *testing.T) {
+	ctx := context.Background()
+	params := SourcegraphParams{Query: "func Serve", ContextWindow: 1}
+
+	t.Run("private instance", func(t *testing.T) {
+		server, queries := newSourcegraphServer(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "token secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(sourcegraphSearchResponse))
+		})
+		cfg := config.SourcegraphConfig{
+			Endpoint:    server.URL + "/",
+			Token:       "secret",
+			RepoFilters: []string{`repo:^git\.example\.com/team/`},
+		}
+
+		response, err := searchSourcegraph(ctx, server.Client(), cfg, params)
+		require.NoError(t, err)
+		require.False(t, response.IsError, response.Content)
+		assert.Contains(t, response.Content, "## Result 1: git.example.com/team/api/server.go")
+		assert.Contains(t, response.Content, "URL: "+server.URL+"/git.example.com/team/api/-/blob/server.go")
+		assert.Contains(t, response.Content, "3|  func Serve() {}")
+
+		_, err = searchSourcegraph(ctx, server.Client(), cfg, SourcegraphParams{Query: "repo:other func Serve"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{`repo:^git\.example\.com/team/ func Serve`, "repo:other func Serve"}, *queries)
+
+		cfg.Token = ""
+		response, err = searchSourcegraph(ctx, server.Client(), cfg, params)
+		require.NoError(t, err)
+		assert.True(t, response.IsError)
+		assert.Contains(t, response.Content, "requires an access token")
+	})
+
+	t.Run("sign-in page", func(t *testing.T) {
+		server, _ := newSourcegraphServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte("<html>Sign in</html>"))
+		})
+		response, err := searchSourcegraph(ctx, server.Client(), config.SourcegraphConfig{Endpoint: server.URL}, params)
+		require.NoError(t, err)
+		assert.True(t, response.IsError)
+		assert.Contains(t, response.Content, "answered with text/html instead of JSON")
+	})
+
+	t.Run("graphql errors", func(t *testing.T) {
+		server, _ := newSourcegraphServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"errors": [{"message": "invalid query: unknown field \"lang2\""}]}`))
+		})
+		response, err := searchSourcegraph(ctx, server.Client(), config.SourcegraphConfig{Endpoint: server.URL}, params)
+		require.NoError(t, err)
+		assert.True(t, response.IsError)
+		assert.Equal(t, "Sourcegraph API error:\n- invalid query: unknown field \"lang2\"", response.Content)
+	})
+
+	t.Run("wrong endpoint", func(t *testing.T) {
+		server, _ := newSourcegraphServer(t, nil)
+		response, err := searchSourcegraph(ctx, server.Client(), config.SourcegraphConfig{Endpoint: server.URL + "/search"}, params)
+		require.NoError(t, err)
+		assert.True(t, response.IsError)
+		assert.Contains(t, response.Content, "No Sourcegraph API found at "+server.URL+"/search/.api/graphql")
+	})
+}
+
+func TestSourcegraphDescription(t *testing.T) {
+	assert.Contains(t, sourcegraphDescription(config.SourcegraphConfig{}), "Search code across public repositories using")
+
+	description := sourcegraphDescription(config.SourcegraphConfig{Endpoint: "https://sg.example.com", RepoFilters: []string{"context:team"}})
+	assert.Contains(t, description, "Search code across the repositories of the Sourcegraph instance at https://sg.example.com using")
+	assert.Contains(t, description, "limited to: context:team")
+	assert.NotContains(t, description, "%!")
+}
@@ -0,0 +1,174 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/lsp"
+	"github.com/opencode-ai/opencode/internal/lsp/protocol"
+)
+
+type SymbolsParams struct {
+	Query    string `json:"query"`
+	FilePath string `json:"file_path"`
+	Limit    int    `json:"limit"`
+}
+
+type symbolsTool struct {
+	lspClients map[string]*lsp.Client
+}
+
+const (
+	SymbolsToolName     = "symbols"
+	defaultSymbolsLimit = 50
+	symbolsDescription  = `Searches the workspace for symbols by name, or lists the symbols of a file, using the language servers.
+
+WHEN TO USE THIS TOOL:
+- Use to find a function, type or variable when you know part of its name
+- Use with a file path to get an outline of a file without reading all of it
+
+HOW TO USE:
+- Pass query to search the whole workspace, matching is fuzzy and depends on the language server
+- Or pass file_path to list the symbols declared in that file
+- Optionally set limit to change the maximum number of results (default 50)
+
+FEATURES:
+- Each result shows the kind, the qualified name and file:line:column
+- File outlines are indented to show nesting, e.g. methods inside classes
+
+LIMITATIONS:
+- Only works for languages with a configured language server
+- Workspace search only covers what the language server has indexed
+
+TIPS:
+- Pass a result location to the definition, references, hover or call_hierarchy tools`
+)
+
+func NewSymbolsTool(lspClients map[string]*lsp.Client) BaseTool {
+	return &symbolsTool{
+		lspClients,
+	}
+}
+
+func (s *symbolsTool) Info() ToolInfo {
+	return ToolInfo{
+		Name:        SymbolsToolName,
+		Description: symbolsDescription,
+		Parameters: map[string]any{
+			"query": map[string]any{
+				"type":        "string",
+				"description": "The name or part of the name of the symbols to search the workspace for",
+			},
+			"file_path": map[string]any{
+				"type":        "string",
+				"description": "List the symbols of this file instead of searching the workspace",
+			},
+			"limit": map[string]any{
+				"type":        "number",
+				"description": "Maximum number of symbols to return (default 50)",
+			},
+		},
+		Required: []string{},
+	}
+}
+
+func (s *symbolsTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error) {
+	var params SymbolsParams
+	if err := json.Unmarshal([]byte(call.Input), &params); err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("error parsing parameters: %s", err)), nil
+	}
+	if len(s.lspClients) == 0 {
+		return NewTextErrorResponse("no LSP clients available"), nil
+	}
+	if params.Query == "" && params.FilePath == "" {
+		return NewTextErrorResponse("query or file_path is required"), nil
+	}
+	if params.Limit <= 0 {
+		params.Limit = defaultSymbolsLimit
+	}
+
+	if params.FilePath != "" {
+		return s.documentSymbols(ctx, params)
+	}
+
+	matches, err := searchWorkspaceSymbols(ctx, s.lspClients, params.Query)
+	if err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("error searching symbols: %s", err)), nil
+	}
+	if len(matches) == 0 {
+		return NewTextResponse(fmt.Sprintf("No symbols found for %q", params.Query)), nil
+	}
+
+	lines := sourceLines{}
+	var output []string
+	for i, match := range matches {
+		if i == params.Limit {
+			output = append(output, fmt.Sprintf("... and %d more, refine the query to narrow the results", len(matches)-params.Limit))
+			break
+		}
+		location := relativePath(match.path)
+		if match.rng != (protocol.Range{}) {
+			location = lines.position(match.path, match.rng.Start)
+		}
+		output = append(output, fmt.Sprintf("%s %s", describeSymbol(match), location))
+	}
+	return NewTextResponse(strings.Join(output, "\n")), nil
+}
+
+func (s *symbolsTool) documentSymbols(ctx context.Context, params SymbolsParams) (ToolResponse, error) {
+	filePath := params.FilePath
+	if !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(config.WorkingDirectory(), filePath)
+	}
+	if _, err := readSourceLines(filePath); err != nil {
+		return NewTextErrorResponse(err.Error()), nil
+	}
+	notifyLspOpenFile(ctx, filePath, s.lspClients)
+
+	output, err := queryClients(s.lspClients, func(client *lsp.Client) ([]string, error) {
+		result, err := client.DocumentSymbol(ctx, protocol.DocumentSymbolParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: fileURI(filePath)},
+		})
+		if err != nil {
+			return nil, err
+		}
+		var output []string
+		switch symbols := result.Value.(type) {
+		case []protocol.DocumentSymbol:
+			output = formatDocumentSymbols(symbols, 0)
+		case []protocol.SymbolInformation:
+			for _, symbol := range symbols {
+				match := symbolMatch{name: symbol.Name, kind: symbol.Kind, container: symbol.ContainerName}
+				output = append(output, fmt.Sprintf("%s %d", describeSymbol(match), symbol.Location.Range.Start.Line+1))
+			}
+		}
+		return output, nil
+	})
+	if err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("error listing symbols: %s", err)), nil
+	}
+	if len(output) == 0 {
+		return NewTextResponse(fmt.Sprintf("No symbols found in %s", relativePath(filePath))), nil
+	}
+	if len(output) > params.Limit {
+		output = append(output[:params.Limit], fmt.Sprintf("... and %d more", len(output)-params.Limit))
+	}
+	return NewTextResponse(relativePath(filePath) + " (kind name line)\n" + strings.Join(output, "\n")), nil
+}
+
+func formatDocumentSymbols(symbols []protocol.DocumentSymbol, depth int) []string {
+	var output []string
+	for _, symbol := range symbols {
+		line := fmt.Sprintf("%s%s %d", strings.Repeat("  ", depth), describeSymbol(symbolMatch{name: symbol.Name, kind: symbol.Kind}), symbol.SelectionRange.Start.Line+1)
+		if symbol.Detail != "" {
+			line += " " + symbol.Detail
+		}
+		output = append(output, line)
+		output = append(output, formatDocumentSymbols(symbol.Children, depth+1)...)
+	}
+	return output
+}
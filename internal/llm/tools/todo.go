@@ -0,0 +1,194 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/opencode-ai/opencode/internal/todo"
+)
+
+type TodoParams struct {
+	Action  string   `json:"action"`
+	Items   []string `json:"items"`
+	Number  int64    `json:"number"`
+	Status  string   `json:"status"`
+	Content string   `json:"content"`
+}
+
+type todoTool struct {
+	todos todo.Service
+}
+
+const (
+	TodoToolName    = "todo"
+	todoDescription = `Keeps a todo list for the current session, to plan multi-step tasks and track what is left.
+
+WHEN TO USE THIS TOOL:
+- Use at the start of a task that takes several steps, to write down the plan
+- Use while working to mark the item you are working on and the items you finish
+- Use to check what is left, for example after the conversation was summarized
+
+HOW TO USE:
+- action "add" with items adds one item per string, in order
+- action "update" with a number changes the status (pending, in_progress or completed) and/or the content of an item
+- action "complete" with a number marks an item as completed
+- action "remove" with a number deletes an item, "clear" deletes the whole list
+- action "list" shows the list
+- Every action returns the whole list with the numbers of the items
+
+FEATURES:
+- The list is saved with the session and shown to the user in the sidebar
+- The list is kept when the conversation is summarized
+
+TIPS:
+- Keep items small and concrete, like "Add the migration for the todos table"
+- Have only one item in_progress at a time and mark items completed as soon as they are done
+- Add items you discover along the way instead of keeping them in mind
+- Don't use it for trivial tasks that take one or two steps`
+)
+
+func NewTodoTool(todos todo.Service) BaseTool {
+	return &todoTool{
+		todos: todos,
+	}
+}
+
+func (t *todoTool) Info() ToolInfo {
+	return ToolInfo{
+		Name:        TodoToolName,
+		Description: todoDescription,
+		Parameters: map[string]any{
+			"action": map[string]any{
+				"type":        "string",
+				"description": "What to do: list, add, update, complete, remove or clear",
+				"enum":        []string{"list", "add", "update", "complete", "remove", "clear"},
+			},
+			"items": map[string]any{
+				"type":        "array",
+				"description": "The items to add (for add)",
+				"items": map[string]any{
+					"type": "string",
+				},
+			},
+			"number": map[string]any{
+				"type":        "integer",
+				"description": "The number of the item (for update, complete and remove)",
+			},
+			"status": map[string]any{
+				"type":        "string",
+				"description": "The new status of the item (for update)",
+				"enum":        []string{string(todo.StatusPending), string(todo.StatusInProgress), string(todo.StatusCompleted)},
+			},
+			"content": map[string]any{
+				"type":        "string",
+				"description": "The new text of the item (for update)",
+			},
+		},
+		Required: []string{"action"},
+	}
+}
+
+func (t *todoTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error) {
+	var params TodoParams
+	if err := json.Unmarshal([]byte(call.Input), &params); err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("error parsing parameters: %s", err)), nil
+	}
+	sessionID, _ := GetContextValues(ctx)
+	if sessionID == "" {
+		return ToolResponse{}, fmt.Errorf("session ID is required")
+	}
+
+	switch params.Action {
+	case "list":
+	case "add":
+		if len(params.Items) == 0 {
+			return NewTextErrorResponse("items are required"), nil
+		}
+		for _, item := range params.Items {
+			if strings.TrimSpace(item) == "" {
+				continue
+			}
+			if _, err := t.todos.Create(ctx, sessionID, strings.TrimSpace(item)); err != nil {
+				return ToolResponse{}, fmt.Errorf("error adding todo: %w", err)
+			}
+		}
+	case "update", "complete", "remove":
+		item, ok, err := t.find(ctx, sessionID, params.Number)
+		if err != nil {
+			return ToolResponse{}, err
+		}
+		if !ok {
+			return NewTextErrorResponse(fmt.Sprintf("no item with number %d, use list to see the items", params.Number)), nil
+		}
+		if params.Action == "remove" {
+			if err := t.todos.Delete(ctx, item.ID); err != nil {
+				return ToolResponse{}, fmt.Errorf("error removing todo: %w", err)
+			}
+			break
+		}
+		if params.Action == "complete" {
+			item.Status = todo.StatusCompleted
+		} else {
+			if params.Status == "" && params.Content == "" {
+				return NewTextErrorResponse("status or content is required"), nil
+			}
+			if params.Status != "" {
+				status := todo.Status(params.Status)
+				if !status.Valid() {
+					return NewTextErrorResponse(fmt.Sprintf("unknown status %q, use pending, in_progress or completed", params.Status)), nil
+				}
+				item.Status = status
+			}
+			if params.Content != "" {
+				item.Content = params.Content
+			}
+		}
+		if _, err := t.todos.Update(ctx, item); err != nil {
+			return ToolResponse{}, fmt.Errorf("error updating todo: %w", err)
+		}
+	case "clear":
+		if err := t.todos.DeleteSessionTodos(ctx, sessionID); err != nil {
+			return ToolResponse{}, fmt.Errorf("error clearing todos: %w", err)
+		}
+	default:
+		return NewTextErrorResponse(fmt.Sprintf("unknown action %q, use list, add, update, complete, remove or clear", params.Action)), nil
+	}
+
+	todos, err := t.todos.List(ctx, sessionID)
+	if err != nil {
+		return ToolResponse{}, fmt.Errorf("error listing todos: %w", err)
+	}
+	return NewTextResponse(formatTodoList(todos)), nil
+}
+
+func (t *todoTool) find(ctx context.Context, sessionID string, number int64) (todo.Todo, bool, error) {
+	todos, err := t.todos.List(ctx, sessionID)
+	if err != nil {
+		return todo.Todo{}, false, fmt.Errorf("error listing todos: %w", err)
+	}
+	for _, item := range todos {
+		if item.Position == number {
+			return item, true, nil
+		}
+	}
+	return todo.Todo{}, false, nil
+}
+
+func formatTodoList(todos []todo.Todo) string {
+	if len(todos) == 0 {
+		return "The todo list is empty"
+	}
+	completed := 0
+	for _, item := range todos {
+		if item.Status == todo.StatusCompleted {
+			completed++
+		}
+	}
+	text := fmt.Sprintf("Todo list (%d of %d completed):\n%s", completed, len(todos), todo.Format(todos))
+	if completed == len(todos) {
+		text += "\n\nAll items are completed."
+	}
+	return text
+}
@@ -0,0 +1,101 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/opencode-ai/opencode/internal/pubsub"
+	"github.com/opencode-ai/opencode/internal/todo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryTodos keeps the todos in memory, numbering them like the database.
+type memoryTodos struct {
+	*pubsub.Broker[todo.Todo]
+	items []todo.Todo
+}
+
+func (m *memoryTodos) Create(ctx context.Context, sessionID, content string) (todo.Todo, error) {
+	position := int64(1)
+	if len(m.items) > 0 {
+		position = m.items[len(m.items)-1].Position + 1
+	}
+	item := todo.Todo{ID: fmt.Sprint(position), SessionID: sessionID, Content: content, Status: todo.StatusPending, Position: position}
+	m.items = append(m.items, item)
+	return item, nil
+}
+
+func (m *memoryTodos) Get(ctx context.Context, id string) (todo.Todo, error) {
+	for _, item := range m.items {
+		if item.ID == id {
+			return item, nil
+		}
+	}
+	return todo.Todo{}, fmt.Errorf("not found")
+}
+
+func (m *memoryTodos) List(ctx context.Context, sessionID string) ([]todo.Todo, error) {
+	return append([]todo.Todo(nil), m.items...), nil
+}
+
+func (m *memoryTodos) Update(ctx context.Context, updated todo.Todo) (todo.Todo, error) {
+	for i, item := range m.items {
+		if item.ID == updated.ID {
+			m.items[i] = updated
+			return updated, nil
+		}
+	}
+	return todo.Todo{}, fmt.Errorf("not found")
+}
+
+func (m *memoryTodos) Delete(ctx context.Context, id string) error {
+	for i, item := range m.items {
+		if item.ID == id {
+			m.items = append(m.items[:i], m.items[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("not found")
+}
+
+func (m *memoryTodos) DeleteSessionTodos(ctx context.Context, sessionID string) error {
+	m.items = nil
+	return nil
+}
+
+func runTodo(t *testing.T, tool BaseTool, params TodoParams) ToolResponse {
+	t.Helper()
+	input, err := json.Marshal(params)
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), SessionIDContextKey, "session")
+	response, err := tool.Run(ctx, ToolCall{Input: string(input)})
+	require.NoError(t, err)
+	return response
+}
+
+func TestTodoTool(t *testing.T) {
+	todos := &memoryTodos{Broker: pubsub.NewBroker[todo.Todo]()}
+	tool := NewTodoTool(todos)
+
+	response := runTodo(t, tool, TodoParams{Action: "list"})
+	assert.Equal(t, "The todo list is empty", response.Content)
+
+	response = runTodo(t, tool, TodoParams{Action: "add", Items: []string{"Write the migration", "Add the tool", "Update the README"}})
+	assert.Equal(t, "Todo list (0 of 3 completed):\n1. [ ] Write the migration\n2. [ ] Add the tool\n3. [ ] Update the README", response.Content)
+
+	runTodo(t, tool, TodoParams{Action: "complete", Number: 1})
+	runTodo(t, tool, TodoParams{Action: "update", Number: 2, Status: "in_progress"})
+	response = runTodo(t, tool, TodoParams{Action: "remove", Number: 3})
+	assert.Equal(t, "Todo list (1 of 2 completed):\n1. [x] Write the migration\n2. [~] Add the tool", response.Content)
+
+	response = runTodo(t, tool, TodoParams{Action: "update", Number: 3, Status: "completed"})
+	assert.True(t, response.IsError)
+	response = runTodo(t, tool, TodoParams{Action: "update", Number: 2, Status: "done"})
+	assert.True(t, response.IsError)
+
+	response = runTodo(t, tool, TodoParams{Action: "complete", Number: 2})
+	assert.Contains(t, response.Content, "All items are completed.")
+}
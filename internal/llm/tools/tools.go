@@ -32,6 +32,10 @@ type ToolResponse struct {
 	Content  string           `json:"content"`
 	Metadata string           `json:"metadata,omitempty"`
 	IsError  bool             `json:"is_error"`
+
+	// Set for image responses only
+	MIMEType string `json:"mime_type,omitempty"`
+	Data     []byte `json:"data,omitempty"`
 }
 
 func NewTextResponse(content string) ToolResponse {
@@ -41,6 +45,18 @@ func NewTextResponse(content string) ToolResponse {
 	}
 }
 
+// NewImageResponse returns an image to the model. The content is a short
+// text description that is used as a fallback for models that can't take
+// images.
+func NewImageResponse(content, mimeType string, data []byte) ToolResponse {
+	return ToolResponse{
+		Type:     ToolResponseTypeImage,
+		Content:  content,
+		MIMEType: mimeType,
+		Data:     data,
+	}
+}
+
 func WithResponseMetadata(response ToolResponse, metadata any) ToolResponse {
 	if metadata != nil {
 		metadataBytes, err := json.Marshal(metadata)
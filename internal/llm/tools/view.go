@@ -33,6 +33,7 @@ type ViewResponseMetadata struct {
 const (
 	ViewToolName     = "view"
 	MaxReadSize      = 250 * 1024
+	MaxImageSize     = 5 * 1024 * 1024
 	DefaultReadLimit = 2000
 	MaxLineLength    = 2000
 	viewDescription  = `File viewing tool that reads and displays the contents of files with line numbers, allowing you to examine code, logs, or text data.
@@ -53,13 +54,18 @@ FEATURES:
 - Handles large files by limiting the number of lines read
 - Automatically truncates very long lines for better display
 - Suggests similar file names when the requested file isn't found
+- Shows Jupyter notebooks (.ipynb) as numbered cells with their source and text outputs
+- Extracts the text of PDF files page by page
 
 LIMITATIONS:
-- Maximum file size is 250KB
+- Maximum file size is 250KB, or 20MB for notebooks and PDFs
+- For notebooks and PDFs, offset and limit count cells or pages instead of lines (defaults are 100 cells and 20 pages)
+- Image outputs of notebooks are not shown, and scanned PDFs without a text layer have no text
 - Default reading limit is 2000 lines
 - Lines longer than 2000 characters are truncated
-- Cannot display binary files or images
-- Images can be identified but not displayed
+- Cannot display binary files
+- PNG, JPEG, GIF and WebP images (up to 5MB) are returned as images to models that support them
+- Other image formats can be identified but not displayed
 
 TIPS:
 - Use with Glob tool to first find files you want to view
@@ -84,11 +90,11 @@ func (v *viewTool) Info() ToolInfo {
 			},
 			"offset": map[string]any{
 				"type":        "integer",
-				"description": "The line number to start reading from (0-based)",
+				"description": "The line number to start reading from (0-based), or the cell or page for notebooks and PDFs",
 			},
 			"limit": map[string]any{
 				"type":        "integer",
-				"description": "The number of lines to read (defaults to 2000)",
+				"description": "The number of lines to read (defaults to 2000), or of cells or pages for notebooks and PDFs",
 			},
 		},
 		Required: []string{"file_path"},
@@ -150,6 +156,38 @@ func (v *viewTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error)
 		return NewTextErrorResponse(fmt.Sprintf("Path is a directory, not a file: %s", filePath)), nil
 	}
 
+	// Check if it's an image file
+	isImage, imageType := isImageFile(filePath)
+	if isImage {
+		mimeType := imageMIMEType(imageType)
+		if mimeType == "" {
+			return NewTextErrorResponse(fmt.Sprintf("This is an image file of type: %s\nUse a different tool to process images", imageType)), nil
+		}
+		if fileInfo.Size() > MaxImageSize {
+			return NewTextErrorResponse(fmt.Sprintf("Image is too large (%d bytes). Maximum size is %d bytes",
+				fileInfo.Size(), MaxImageSize)), nil
+		}
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return ToolResponse{}, fmt.Errorf("error reading file: %w", err)
+		}
+		recordFileRead(filePath)
+		return WithResponseMetadata(
+			NewImageResponse(
+				fmt.Sprintf("This is an image file of type: %s (%d bytes). The image is attached if the model supports images.", imageType, len(data)),
+				mimeType,
+				data,
+			),
+			ViewResponseMetadata{
+				FilePath: filePath,
+			},
+		), nil
+	}
+
+	if isDocumentFile(filePath) {
+		return viewDocument(filePath, fileInfo.Size(), params)
+	}
+
 	// Check file size
 	if fileInfo.Size() > MaxReadSize {
 		return NewTextErrorResponse(fmt.Sprintf("File is too large (%d bytes). Maximum size is %d bytes",
@@ -161,13 +199,6 @@ func (v *viewTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error)
 		params.Limit = DefaultReadLimit
 	}
 
-	// Check if it's an image file
-	isImage, imageType := isImageFile(filePath)
-	// TODO: handle images
-	if isImage {
-		return NewTextErrorResponse(fmt.Sprintf("This is an image file of type: %s\nUse a different tool to process images", imageType)), nil
-	}
-
 	// Read the file content
 	content, lineCount, err := readTextFile(filePath, params.Offset, params.Limit)
 	if err != nil {
@@ -196,6 +227,54 @@ func (v *viewTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error)
 	), nil
 }
 
+func isDocumentFile(filePath string) bool {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	return ext == ".ipynb" || ext == ".pdf"
+}
+
+// viewDocument shows a notebook as its cells or a PDF as the text of its
+// pages. Offset and limit count cells or pages instead of lines.
+func viewDocument(filePath string, size int64, params ViewParams) (ToolResponse, error) {
+	if size > MaxDocumentSize {
+		return NewTextErrorResponse(fmt.Sprintf("File is too large (%d bytes). Maximum size is %d bytes",
+			size, MaxDocumentSize)), nil
+	}
+
+	read, kind, unit, limit := readNotebook, "Notebook", "cell", DefaultCellLimit
+	if strings.ToLower(filepath.Ext(filePath)) == ".pdf" {
+		read, kind, unit, limit = readPDF, "PDF", "page", DefaultPageLimit
+	}
+	if params.Limit > 0 {
+		limit = params.Limit
+	}
+	offset := max(params.Offset, 0)
+
+	content, end, total, err := read(filePath, offset, limit)
+	if err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("Failed to read %s: %s", filePath, err)), nil
+	}
+	if total == 0 {
+		return NewTextErrorResponse(fmt.Sprintf("%s has no %ss: %s", kind, unit, filePath)), nil
+	}
+	if offset >= total {
+		return NewTextErrorResponse(fmt.Sprintf("%s has only %d %ss, offset %d is past the end", kind, total, unit, offset)), nil
+	}
+
+	output := fmt.Sprintf("<file>\n%s with %d %ss, showing %ss %d-%d\n\n%s", kind, total, unit, unit, offset+1, end, content)
+	if end < total {
+		output += fmt.Sprintf("\n\n(%s has more %ss. Use 'offset' parameter to read beyond %s %d)", kind, unit, unit, end)
+	}
+	output += "\n</file>\n"
+	recordFileRead(filePath)
+	return WithResponseMetadata(
+		NewTextResponse(output),
+		ViewResponseMetadata{
+			FilePath: filePath,
+			Content:  content,
+		},
+	), nil
+}
+
 func addLineNumbers(content string, startLine int) string {
 	if content == "" {
 		return ""
@@ -291,6 +370,23 @@ func isImageFile(filePath string) (bool, string) {
 	}
 }
 
+// imageMIMEType returns the MIME type for image types that can be sent to
+// the model, or an empty string if the type is not supported.
+func imageMIMEType(imageType string) string {
+	switch imageType {
+	case "JPEG":
+		return "image/jpeg"
+	case "PNG":
+		return "image/png"
+	case "GIF":
+		return "image/gif"
+	case "WebP":
+		return "image/webp"
+	default:
+		return ""
+	}
+}
+
 type LineScanner struct {
 	scanner *bufio.Scanner
 }
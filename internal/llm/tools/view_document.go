@@ -0,0 +1,236 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+const (
+	// Notebooks and PDFs are larger than the text they contain, mostly
+	// because of embedded images
+	MaxDocumentSize      = 20 * 1024 * 1024
+	DefaultCellLimit     = 100
+	DefaultPageLimit     = 20
+	maxCellOutputLines   = 50
+	maxCellOutputLength  = 4000
+	maxDocumentTextBytes = MaxReadSize
+)
+
+// notebook is the part of the Jupyter notebook format that is shown.
+type notebook struct {
+	Cells    []notebookCell `json:"cells"`
+	Metadata struct {
+		KernelSpec struct {
+			Language string `json:"language"`
+		} `json:"kernelspec"`
+		LanguageInfo struct {
+			Name string `json:"name"`
+		} `json:"language_info"`
+	} `json:"metadata"`
+}
+
+type notebookCell struct {
+	CellType       string           `json:"cell_type"`
+	Source         notebookText     `json:"source"`
+	ExecutionCount *int             `json:"execution_count"`
+	Outputs        []notebookOutput `json:"outputs"`
+}
+
+type notebookOutput struct {
+	OutputType string                  `json:"output_type"`
+	Text       notebookText            `json:"text"`
+	Data       map[string]notebookText `json:"data"`
+	EName      string                  `json:"ename"`
+	EValue     string                  `json:"evalue"`
+	Traceback  []string                `json:"traceback"`
+}
+
+// notebookText is a multiline string, stored either as one string or as a
+// list of lines.
+type notebookText string
+
+func (t *notebookText) UnmarshalJSON(data []byte) error {
+	var lines []string
+	if err := json.Unmarshal(data, &lines); err == nil {
+		*t = notebookText(strings.Join(lines, ""))
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		// Outputs like application/json hold objects, they are not shown
+		*t = ""
+		return nil
+	}
+	*t = notebookText(text)
+	return nil
+}
+
+func (n notebook) language() string {
+	if n.Metadata.LanguageInfo.Name != "" {
+		return n.Metadata.LanguageInfo.Name
+	}
+	return n.Metadata.KernelSpec.Language
+}
+
+// readNotebook renders the cells of a notebook from offset on, with their
+// source and text outputs. It returns the index after the last cell shown
+// and the number of cells as well.
+func readNotebook(filePath string, offset, limit int) (string, int, int, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	var nb notebook
+	if err := json.Unmarshal(data, &nb); err != nil {
+		return "", 0, 0, fmt.Errorf("invalid notebook: %w", err)
+	}
+
+	var sb strings.Builder
+	end := min(offset+limit, len(nb.Cells))
+	for i := offset; i < end; i++ {
+		cell := nb.Cells[i]
+		header := fmt.Sprintf("Cell %d [%s", i+1, cell.CellType)
+		if cell.ExecutionCount != nil {
+			header += fmt.Sprintf(", execution %d", *cell.ExecutionCount)
+		}
+		fmt.Fprintf(&sb, "%s]:\n", header)
+
+		source := strings.TrimRight(string(cell.Source), "\n")
+		if cell.CellType == "code" {
+			fmt.Fprintf(&sb, "```%s\n%s\n```\n", nb.language(), source)
+		} else {
+			sb.WriteString(source + "\n")
+		}
+
+		if outputs := formatCellOutputs(cell.Outputs); outputs != "" {
+			sb.WriteString("Output:\n" + outputs + "\n")
+		}
+		sb.WriteString("\n")
+
+		if sb.Len() > maxDocumentTextBytes {
+			end = i + 1
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n"), max(end, offset), len(nb.Cells), nil
+}
+
+func formatCellOutputs(outputs []notebookOutput) string {
+	var parts []string
+	for _, output := range outputs {
+		switch output.OutputType {
+		case "stream":
+			parts = append(parts, truncateCellOutput(string(output.Text)))
+		case "execute_result", "display_data":
+			if text, ok := output.Data["text/plain"]; ok && text != "" {
+				parts = append(parts, truncateCellOutput(string(text)))
+			}
+			for _, mimeType := range slices.Sorted(maps.Keys(output.Data)) {
+				if strings.HasPrefix(mimeType, "image/") {
+					parts = append(parts, fmt.Sprintf("[%s output not shown]", mimeType))
+				}
+			}
+		case "error":
+			text := fmt.Sprintf("%s: %s", output.EName, output.EValue)
+			if len(output.Traceback) > 0 {
+				text = stripANSI(strings.Join(output.Traceback, "\n"))
+			}
+			parts = append(parts, truncateCellOutput(text))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// truncateCellOutput keeps the start of long outputs, which usually shows
+// what the cell did.
+func truncateCellOutput(text string) string {
+	text = strings.TrimRight(text, "\n")
+	lines := strings.Split(text, "\n")
+	truncated := false
+	if len(lines) > maxCellOutputLines {
+		lines = lines[:maxCellOutputLines]
+		truncated = true
+	}
+	text = strings.Join(lines, "\n")
+	if len(text) > maxCellOutputLength {
+		text = text[:maxCellOutputLength]
+		truncated = true
+	}
+	if truncated {
+		text += "\n[output truncated]"
+	}
+	return text
+}
+
+func stripANSI(text string) string {
+	var sb strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] == 0x1b && i+1 < len(text) && text[i+1] == '[' {
+			// Skip to the final byte of the escape sequence
+			i += 2
+			for i < len(text) && (text[i] < 0x40 || text[i] > 0x7e) {
+				i++
+			}
+			continue
+		}
+		sb.WriteByte(text[i])
+	}
+	return sb.String()
+}
+
+// readPDF extracts the text of the pages of a PDF from offset on. It returns
+// the index after the last page shown and the number of pages as well.
+// Malformed files are an error, also where the PDF reader panics on them.
+func readPDF(filePath string, offset, limit int) (content string, next int, pages int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			content, next, pages, err = "", 0, 0, fmt.Errorf("invalid PDF: %v", r)
+		}
+	}()
+	file, reader, err := pdf.Open(filePath)
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("invalid PDF: %w", err)
+	}
+	defer file.Close()
+
+	pages = reader.NumPage()
+	var sb strings.Builder
+	end := min(offset+limit, pages)
+	for i := offset; i < end; i++ {
+		fmt.Fprintf(&sb, "--- Page %d ---\n", i+1)
+		text := strings.TrimSpace(pageText(reader, i+1))
+		if text == "" {
+			text = "(No text on this page, it may be a scanned image)"
+		}
+		sb.WriteString(text + "\n\n")
+
+		if sb.Len() > maxDocumentTextBytes {
+			end = i + 1
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n"), max(end, offset), pages, nil
+}
+
+// pageText returns the text of a page, or "" when it can't be extracted.
+// The PDF reader panics on some malformed files.
+func pageText(reader *pdf.Reader, num int) (text string) {
+	defer func() {
+		if r := recover(); r != nil {
+			text = ""
+		}
+	}()
+	page := reader.Page(num)
+	if page.V.IsNull() {
+		return ""
+	}
+	text, err := page.GetPlainText(nil)
+	if err != nil {
+		return ""
+	}
+	return text
+}
@@ -0,0 +1,119 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNotebook = `{
+ "cells": [
+  {"cell_type": "markdown", "metadata": {}, "source": ["# Analysis\n", "Loads the data"]},
+  {"cell_type": "code", "execution_count": 1, "metadata": {}, "source": "print('hi')",
+   "outputs": [{"output_type": "stream", "name": "stdout", "text": ["hi\n"]}]},
+  {"cell_type": "code", "execution_count": 2, "metadata": {}, "source": ["df.plot()"],
+   "outputs": [{"output_type": "display_data", "data": {"image/png": "iVBORw0KGgo=", "text/plain": ["<Figure>"]}, "metadata": {}}]},
+  {"cell_type": "code", "execution_count": 3, "metadata": {}, "source": ["1/0"],
+   "outputs": [{"output_type": "error", "ename": "ZeroDivisionError", "evalue": "division by zero",
+     "traceback": ["\u001b[0;31mZeroDivisionError\u001b[0m: division by zero"]}]}
+ ],
+ "metadata": {"kernelspec": {"language": "python", "name": "python3"}},
+ "nbformat": 4,
+ "nbformat_minor": 5
+}`
+
+func TestReadNotebook(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "analysis.ipynb")
+	require.NoError(t, os.WriteFile(path, []byte(testNotebook), 0o644))
+
+	content, end, total, err := readNotebook(path, 0, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 4, end)
+	assert.Equal(t, 4, total)
+	assert.Equal(t, "Cell 1 [markdown]:\n# Analysis\nLoads the data\n\n"+
+		"Cell 2 [code, execution 1]:\n```python\nprint('hi')\n```\nOutput:\nhi\n\n"+
+		"Cell 3 [code, execution 2]:\n```python\ndf.plot()\n```\nOutput:\n<Figure>\n[image/png output not shown]\n\n"+
+		"Cell 4 [code, execution 3]:\n```python\n1/0\n```\nOutput:\nZeroDivisionError: division by zero", content)
+
+	content, end, _, err = readNotebook(path, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, end)
+	assert.True(t, strings.HasPrefix(content, "Cell 2 [code"))
+	assert.NotContains(t, content, "Cell 3")
+}
+
+func TestTruncateCellOutput(t *testing.T) {
+	long := strings.Repeat("line\n", maxCellOutputLines+10)
+	truncated := truncateCellOutput(long)
+	assert.Equal(t, maxCellOutputLines+1, strings.Count(truncated, "\n")+1)
+	assert.True(t, strings.HasSuffix(truncated, "[output truncated]"))
+
+	assert.Equal(t, "short", truncateCellOutput("short\n"))
+}
+
+// writeTestPDF writes a PDF with one line of text on each page.
+func writeTestPDF(t *testing.T, path string, pages ...string) {
+	t.Helper()
+	var objects []string
+	objects = append(objects, "<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	objects = append(objects, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	for i, text := range pages {
+		objects = append(objects, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", 5+2*i))
+		stream := fmt.Sprintf("BT /F1 12 Tf 72 720 Td (%s) Tj ET", text)
+		objects = append(objects, fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream))
+	}
+
+	var sb strings.Builder
+	sb.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = sb.Len()
+		fmt.Fprintf(&sb, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := sb.Len()
+	fmt.Fprintf(&sb, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&sb, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&sb, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	require.NoError(t, os.WriteFile(path, []byte(sb.String()), 0o644))
+}
+
+func TestReadPDF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.pdf")
+	writeTestPDF(t, path, "Introduction", "Requirements", "Appendix")
+
+	content, end, total, err := readPDF(path, 0, 20)
+	require.NoError(t, err)
+	assert.Equal(t, 3, end)
+	assert.Equal(t, 3, total)
+	assert.Contains(t, content, "--- Page 1 ---\nIntroduction")
+	assert.Contains(t, content, "--- Page 3 ---\nAppendix")
+
+	content, end, _, err = readPDF(path, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, end)
+	assert.Equal(t, "--- Page 2 ---\nRequirements", content)
+
+	_, _, _, err = readPDF(filepath.Join(t.TempDir(), "missing.pdf"), 0, 20)
+	assert.Error(t, err)
+
+	// The PDF reader panics on this one
+	broken := filepath.Join(t.TempDir(), "broken.pdf")
+	body := "%PDF-1.4\n1 0 obj\n[1 2\nendobj\n"
+	xref := len(body)
+	body += fmt.Sprintf("xref\n0 2\n0000000000 65535 f \n%010d 00000 n \ntrailer\n<< /Root 1 0 R /Size 2 >>\nstartxref\n%d\n%%%%EOF\n", len("%PDF-1.4\n"), xref)
+	require.NoError(t, os.WriteFile(broken, []byte(body), 0o644))
+	_, _, _, err = readPDF(broken, 0, 20)
+	assert.ErrorContains(t, err, "invalid PDF")
+}
@@ -0,0 +1,395 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/httpclient"
+	"github.com/opencode-ai/opencode/internal/permission"
+)
+
+type WebSearchParams struct {
+	Query string `json:"query"`
+	Count int    `json:"count,omitempty"`
+}
+
+type WebSearchPermissionsParams struct {
+	Query   string `json:"query"`
+	Backend string `json:"backend"`
+}
+
+// webSearchResult is a search result as every backend returns it.
+type webSearchResult struct {
+	Title   string
+	URL     string
+	Snippet string
+}
+
+// webSearchBackend runs searches on one search engine.
+type webSearchBackend interface {
+	Search(ctx context.Context, client *http.Client, query string, count int) ([]webSearchResult, error)
+}
+
+type webSearchTool struct {
+	client      *http.Client
+	permissions permission.Service
+}
+
+const (
+	WebSearchToolName        = "web_search"
+	defaultWebSearchResults  = 5
+	maxWebSearchResults      = 20
+	maxWebSearchSnippet      = 300
+	defaultBraveEndpoint     = "https://api.search.brave.com/res/v1/web/search"
+	webSearchToolDescription = `Searches the web and returns the title, URL and a snippet of each result.
+
+WHEN TO USE THIS TOOL:
+- Use when you need a URL you don't know, like the documentation of a library, a changelog or an error message discussed online
+- Use when the information may be newer than what you know
+
+HOW TO USE:
+- Provide a search query, like a search engine would get it
+- Optionally set count to the number of results to return (default 5, max 20)
+- The user approves each query before it is sent
+- To read a result, call the fetch tool with its URL. The user approves fetches as usual
+
+FEATURES:
+- Results come from the search engine the user configured
+- Site filters like "site:go.dev" work when the search engine supports them
+
+LIMITATIONS:
+- Snippets are short, fetch the page for the details
+- The search engine may rate limit requests
+
+TIPS:
+- Use specific queries with the names of libraries, versions or the exact error message
+- Fetch only the results that look relevant`
+)
+
+// webSearchBackends creates the backends by their name in the config.
+var webSearchBackends = map[string]func(cfg config.WebSearchConfig) (webSearchBackend, error){
+	"searxng": newSearxngBackend,
+	"brave":   newBraveBackend,
+}
+
+func NewWebSearchTool(permissions permission.Service) BaseTool {
+	return &webSearchTool{
+		client:      httpclient.New(httpclient.Timeout(30 * time.Second)),
+		permissions: permissions,
+	}
+}
+
+func webSearchConfig() config.WebSearchConfig {
+	cfg := config.Get()
+	if cfg == nil {
+		return config.WebSearchConfig{}
+	}
+	searchCfg := cfg.WebSearch
+	if searchCfg.Backend == "" && searchCfg.Endpoint != "" {
+		searchCfg.Backend = "searxng"
+	}
+	return searchCfg
+}
+
+// WebSearchEnabled reports whether a search backend is configured, the
+// web_search tool is only offered then.
+func WebSearchEnabled() bool {
+	return webSearchConfig().Backend != ""
+}
+
+func (w *webSearchTool) Info() ToolInfo {
+	return ToolInfo{
+		Name:        WebSearchToolName,
+		Description: webSearchToolDescription,
+		Parameters: map[string]any{
+			"query": map[string]any{
+				"type":        "string",
+				"description": "The search query",
+			},
+			"count": map[string]any{
+				"type":        "number",
+				"description": "Optional number of results to return (default: 5, max: 20)",
+			},
+		},
+		Required: []string{"query"},
+	}
+}
+
+func (w *webSearchTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error) {
+	var params WebSearchParams
+	if err := json.Unmarshal([]byte(call.Input), &params); err != nil {
+		return NewTextErrorResponse(fmt.Sprintf("error parsing parameters: %s", err)), nil
+	}
+	if strings.TrimSpace(params.Query) == "" {
+		return NewTextErrorResponse("query is required"), nil
+	}
+
+	sessionID, messageID := GetContextValues(ctx)
+	if sessionID == "" || messageID == "" {
+		return ToolResponse{}, fmt.Errorf("session ID and message ID are required for searching the web")
+	}
+	// The query leaves the machine like the URL of a fetch does
+	cfg := webSearchConfig()
+	backend := webSearchBackendName(cfg)
+	p := w.permissions.Request(
+		permission.CreatePermissionRequest{
+			SessionID:   sessionID,
+			Path:        config.WorkingDirectory(),
+			ToolName:    WebSearchToolName,
+			Action:      "search",
+			Description: fmt.Sprintf("Send this query to %s: %s", backend, params.Query),
+			Params: WebSearchPermissionsParams{
+				Query:   params.Query,
+				Backend: backend,
+			},
+		},
+	)
+	if !p {
+		return ToolResponse{}, permission.ErrorPermissionDenied
+	}
+	return searchWeb(ctx, w.client, cfg, params)
+}
+
+// webSearchBackendName names the search engine queries are sent to, with
+// the host of the instance for SearxNG.
+func webSearchBackendName(cfg config.WebSearchConfig) string {
+	if cfg.Backend == "searxng" {
+		if u, err := url.Parse(cfg.Endpoint); err == nil && u.Host != "" {
+			return "SearxNG at " + u.Host
+		}
+		return "SearxNG"
+	}
+	if cfg.Backend == "brave" {
+		return "Brave Search"
+	}
+	return cfg.Backend
+}
+
+func searchWeb(ctx context.Context, client *http.Client, cfg config.WebSearchConfig, params WebSearchParams) (ToolResponse, error) {
+	count := params.Count
+	if count <= 0 {
+		count = cfg.MaxResults
+	}
+	if count <= 0 {
+		count = defaultWebSearchResults
+	}
+	count = min(count, maxWebSearchResults)
+
+	newBackend, ok := webSearchBackends[cfg.Backend]
+	if !ok {
+		return NewTextErrorResponse(fmt.Sprintf("unknown web search backend %q, use searxng or brave", cfg.Backend)), nil
+	}
+	backend, err := newBackend(cfg)
+	if err != nil {
+		return NewTextErrorResponse(err.Error()), nil
+	}
+
+	results, err := backend.Search(ctx, client, params.Query, count)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ToolResponse{}, ctx.Err()
+		}
+		return NewTextErrorResponse(err.Error()), nil
+	}
+	if len(results) == 0 {
+		return NewTextResponse("No results found. Try a different query."), nil
+	}
+	return NewTextResponse(formatWebSearchResults(results[:min(count, len(results))])), nil
+}
+
+// formatWebSearchResults numbers the results, with the URL and snippet
+// under each title.
+func formatWebSearchResults(results []webSearchResult) string {
+	blocks := make([]string, 0, len(results))
+	for i, result := range results {
+		title := strings.TrimSpace(result.Title)
+		if title == "" {
+			title = result.URL
+		}
+		block := fmt.Sprintf("%d. %s\n   %s", i+1, title, result.URL)
+		if snippet := webSearchSnippet(result.Snippet); snippet != "" {
+			block += "\n   " + snippet
+		}
+		blocks = append(blocks, block)
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// webSearchSnippet puts a snippet on one line and shortens it.
+func webSearchSnippet(snippet string) string {
+	snippet = strings.Join(strings.Fields(stripHTMLTags(snippet)), " ")
+	if len(snippet) > maxWebSearchSnippet {
+		cut := strings.LastIndex(snippet[:maxWebSearchSnippet], " ")
+		if cut <= 0 {
+			cut = maxWebSearchSnippet
+		}
+		snippet = snippet[:cut] + "..."
+	}
+	return snippet
+}
+
+// stripHTMLTags removes the markup some engines use to highlight the
+// query in snippets.
+func stripHTMLTags(text string) string {
+	var sb strings.Builder
+	inTag := false
+	for _, r := range text {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>' && inTag:
+			inTag = false
+		case !inTag:
+			sb.WriteRune(r)
+		}
+	}
+	return strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">", "&quot;", `"`, "&#39;", "'").Replace(sb.String())
+}
+
+// getSearchJSON sends a search request and decodes its JSON response.
+func getSearchJSON(client *http.Client, req *http.Request, name string, target any) error {
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "opencode/1.0")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not reach %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 5*1024*1024))
+	if err != nil {
+		return fmt.Errorf("failed to read the %s response: %w", name, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return webSearchStatusError(name, resp.StatusCode, body)
+	}
+	if err := json.Unmarshal(body, target); err != nil {
+		return fmt.Errorf("%s returned an invalid response, expected JSON: %w", name, err)
+	}
+	return nil
+}
+
+func webSearchStatusError(name string, status int, body []byte) error {
+	var hint string
+	switch status {
+	case http.StatusUnauthorized:
+		hint = ", check webSearch.apiKey in the config"
+	case http.StatusForbidden:
+		if name == "SearxNG" {
+			hint = ", enable the json format in search.formats of the SearxNG settings.yml"
+		} else {
+			hint = ", check webSearch.apiKey in the config"
+		}
+	case http.StatusTooManyRequests:
+		hint = ", try again later"
+	}
+	text := strings.TrimSpace(string(body))
+	if text != "" && !strings.HasPrefix(text, "<") && len(text) <= 300 {
+		hint += ": " + text
+	}
+	return fmt.Errorf("%s search failed with status code %d%s", name, status, hint)
+}
+
+// searxngBackend searches a SearxNG instance through its JSON format.
+type searxngBackend struct {
+	endpoint string
+}
+
+func newSearxngBackend(cfg config.WebSearchConfig) (webSearchBackend, error) {
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("the searxng backend needs webSearch.endpoint, the URL of the SearxNG instance")
+	}
+	return &searxngBackend{endpoint: strings.TrimRight(cfg.Endpoint, "/")}, nil
+}
+
+func (s *searxngBackend) Search(ctx context.Context, client *http.Client, query string, count int) ([]webSearchResult, error) {
+	values := url.Values{}
+	values.Set("q", query)
+	values.Set("format", "json")
+	req, err := http.NewRequestWithContext(ctx, "GET", s.endpoint+"/search?"+values.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SearxNG endpoint %q: %w", s.endpoint, err)
+	}
+
+	var response struct {
+		Results []struct {
+			Title   string `json:"title"`
+			URL     string `json:"url"`
+			Content string `json:"content"`
+		} `json:"results"`
+	}
+	if err := getSearchJSON(client, req, "SearxNG", &response); err != nil {
+		return nil, err
+	}
+
+	// SearxNG merges the results of several engines, which can list the
+	// same page twice
+	seen := make(map[string]bool)
+	var results []webSearchResult
+	for _, result := range response.Results {
+		if result.URL == "" || seen[result.URL] {
+			continue
+		}
+		seen[result.URL] = true
+		results = append(results, webSearchResult{Title: result.Title, URL: result.URL, Snippet: result.Content})
+		if len(results) == count {
+			break
+		}
+	}
+	return results, nil
+}
+
+// braveBackend searches with the Brave Search API.
+type braveBackend struct {
+	endpoint string
+	apiKey   string
+}
+
+func newBraveBackend(cfg config.WebSearchConfig) (webSearchBackend, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("the brave backend needs webSearch.apiKey, a Brave Search API key")
+	}
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = defaultBraveEndpoint
+	}
+	return &braveBackend{endpoint: endpoint, apiKey: cfg.APIKey}, nil
+}
+
+func (b *braveBackend) Search(ctx context.Context, client *http.Client, query string, count int) ([]webSearchResult, error) {
+	values := url.Values{}
+	values.Set("q", query)
+	values.Set("count", strconv.Itoa(count))
+	req, err := http.NewRequestWithContext(ctx, "GET", b.endpoint+"?"+values.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Brave Search endpoint %q: %w", b.endpoint, err)
+	}
+	req.Header.Set("X-Subscription-Token", b.apiKey)
+
+	var response struct {
+		Web struct {
+			Results []struct {
+				Title       string `json:"title"`
+				URL         string `json:"url"`
+				Description string `json:"description"`
+			} `json:"results"`
+		} `json:"web"`
+	}
+	if err := getSearchJSON(client, req, "Brave Search", &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]webSearchResult, 0, len(response.Web.Results))
+	for _, result := range response.Web.Results {
+		results = append(results, webSearchResult{Title: result.Title, URL: result.URL, Snippet: result.Description})
+	}
+	return results, nil
+}
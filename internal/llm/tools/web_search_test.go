@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const searxngResponse = `{"query": "go generics", "results": [
+  {"title": "Tutorial: Getting started with generics", "url": "https://go.dev/doc/tutorial/generics", "content": "This tutorial introduces the basics of <span class=\"highlight\">generics</span> in Go."},
+  {"title": "Tutorial: Getting started with generics", "url": "https://go.dev/doc/tutorial/generics", "content": "Duplicate from another engine"},
+  {"title": "", "url": "https://go.dev/blog/intro-generics", "content": ""},
+  {"title": "Type parameters proposal", "url": "https://go.googlesource.com/proposal", "content": "The proposal"}
+]}`
+
+func TestSearchWeb(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("searxng", func(t *testing.T) {
+		var query string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/search" || r.URL.Query().Get("format") != "json" {
+				http.NotFound(w, r)
+				return
+			}
+			query = r.URL.Query().Get("q")
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(searxngResponse))
+		}))
+		defer server.Close()
+		cfg := config.WebSearchConfig{Backend: "searxng", Endpoint: server.URL + "/"}
+
+		response, err := searchWeb(ctx, server.Client(), cfg, WebSearchParams{Query: "go generics", Count: 2})
+		require.NoError(t, err)
+		require.False(t, response.IsError, response.Content)
+		assert.Equal(t, "go generics", query)
+		assert.Equal(t, "1. Tutorial: Getting started with generics\n"+
+			"   https://go.dev/doc/tutorial/generics\n"+
+			"   This tutorial introduces the basics of generics in Go.\n\n"+
+			"2. https://go.dev/blog/intro-generics\n"+
+			"   https://go.dev/blog/intro-generics", response.Content)
+	})
+
+	t.Run("json format disabled", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("<html>403 Forbidden</html>"))
+		}))
+		defer server.Close()
+
+		response, err := searchWeb(ctx, server.Client(), config.WebSearchConfig{Backend: "searxng", Endpoint: server.URL}, WebSearchParams{Query: "go"})
+		require.NoError(t, err)
+		assert.True(t, response.IsError)
+		assert.Equal(t, "SearxNG search failed with status code 403, enable the json format in search.formats of the SearxNG settings.yml", response.Content)
+	})
+
+	t.Run("brave", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Subscription-Token") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			assert.Equal(t, "3", r.URL.Query().Get("count"))
+			w.Write([]byte(`{"web": {"results": [{"title": "Go", "url": "https://go.dev", "description": "The <strong>Go</strong> programming language"}]}}`))
+		}))
+		defer server.Close()
+		cfg := config.WebSearchConfig{Backend: "brave", Endpoint: server.URL, APIKey: "secret"}
+
+		response, err := searchWeb(ctx, server.Client(), cfg, WebSearchParams{Query: "golang", Count: 3})
+		require.NoError(t, err)
+		assert.Equal(t, "1. Go\n   https://go.dev\n   The Go programming language", response.Content)
+
+		cfg.APIKey = "other"
+		response, err = searchWeb(ctx, server.Client(), cfg, WebSearchParams{Query: "golang", Count: 3})
+		require.NoError(t, err)
+		assert.True(t, response.IsError)
+		assert.Contains(t, response.Content, "status code 401, check webSearch.apiKey")
+	})
+
+	t.Run("misconfigured", func(t *testing.T) {
+		response, err := searchWeb(ctx, http.DefaultClient, config.WebSearchConfig{Backend: "bing"}, WebSearchParams{Query: "go"})
+		require.NoError(t, err)
+		assert.Equal(t, `unknown web search backend "bing", use searxng or brave`, response.Content)
+
+		response, err = searchWeb(ctx, http.DefaultClient, config.WebSearchConfig{Backend: "brave"}, WebSearchParams{Query: "go"})
+		require.NoError(t, err)
+		assert.Contains(t, response.Content, "needs webSearch.apiKey")
+	})
+}
+
+func TestWebSearchSnippet(t *testing.T) {
+	assert.Equal(t, "Fish & chips <3", webSearchSnippet("  <b>Fish</b> &amp;\n chips &lt;3 "))
+}
+
+func TestWebSearchBackendName(t *testing.T) {
+	assert.Equal(t, "SearxNG at search.example.com:8888", webSearchBackendName(config.WebSearchConfig{Backend: "searxng", Endpoint: "https://search.example.com:8888/"}))
+	assert.Equal(t, "Brave Search", webSearchBackendName(config.WebSearchConfig{Backend: "brave"}))
+}
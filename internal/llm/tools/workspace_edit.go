@@ -0,0 +1,201 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/diff"
+	"github.com/opencode-ai/opencode/internal/history"
+	"github.com/opencode-ai/opencode/internal/logging"
+	"github.com/opencode-ai/opencode/internal/lsp"
+	"github.com/opencode-ai/opencode/internal/lsp/util"
+	"github.com/opencode-ai/opencode/internal/permission"
+)
+
+// FileDiff is the change to one file of a multi-file edit.
+type FileDiff struct {
+	FilePath string `json:"file_path"`
+	// Set when the file is renamed
+	NewFilePath string `json:"new_file_path,omitempty"`
+	Diff        string `json:"diff"`
+	Additions   int    `json:"additions"`
+	Removals    int    `json:"removals"`
+}
+
+// Title names the file, relative to the working directory, with its line
+// counts.
+func (d FileDiff) Title() string {
+	name := relativePath(d.FilePath)
+	if d.NewFilePath != "" {
+		name += " -> " + relativePath(d.NewFilePath)
+	}
+	return fmt.Sprintf("%s (+%d -%d)", name, d.Additions, d.Removals)
+}
+
+// WorkspaceEditPermissionsParams are shown in the permission dialog of tools
+// that apply a language server edit to several files.
+type WorkspaceEditPermissionsParams struct {
+	Files []FileDiff `json:"files"`
+}
+
+type WorkspaceEditResponseMetadata struct {
+	Files     []FileDiff `json:"files"`
+	Additions int        `json:"additions"`
+	Removals  int        `json:"removals"`
+}
+
+// workspaceEditApplier asks for permission to apply a workspace edit, writes
+// it and records every file in the history, like the edit tools do.
+type workspaceEditApplier struct {
+	lspClients  map[string]*lsp.Client
+	permissions permission.Service
+	files       history.Service
+}
+
+func fileDiffs(changes []util.FileChange) []FileDiff {
+	diffs := make([]FileDiff, 0, len(changes))
+	for _, change := range changes {
+		path := change.Path
+		if change.Dir {
+			path += string(filepath.Separator)
+		}
+		fileDiff, additions, removals := diff.GenerateDiff(change.OldContent, change.NewContent, path)
+		diffs = append(diffs, FileDiff{
+			FilePath:    path,
+			NewFilePath: change.NewPath,
+			Diff:        fileDiff,
+			Additions:   additions,
+			Removals:    removals,
+		})
+	}
+	return diffs
+}
+
+// FormatFileDiffs renders the diffs of a multi-file edit for the TUI.
+func FormatFileDiffs(files []FileDiff, width int) (string, error) {
+	titles := make([]string, len(files))
+	diffs := make([]string, len(files))
+	for i, file := range files {
+		titles[i] = file.Title()
+		diffs[i] = file.Diff
+	}
+	return diff.FormatFileDiffs(titles, diffs, diff.WithTotalWidth(width))
+}
+
+func (w workspaceEditApplier) apply(ctx context.Context, toolName, description string, changes []util.FileChange) (WorkspaceEditResponseMetadata, error) {
+	sessionID, messageID := GetContextValues(ctx)
+	if sessionID == "" || messageID == "" {
+		return WorkspaceEditResponseMetadata{}, fmt.Errorf("session ID and message ID are required for editing files")
+	}
+
+	diffs := fileDiffs(changes)
+	rootDir := config.WorkingDirectory()
+	permissionPath := rootDir
+	for _, change := range changes {
+		for _, path := range []string{change.Path, change.NewPath} {
+			if path != "" && !strings.HasPrefix(path, rootDir) {
+				permissionPath = filepath.Dir(path)
+			}
+		}
+	}
+	p := w.permissions.Request(
+		permission.CreatePermissionRequest{
+			SessionID:   sessionID,
+			Path:        permissionPath,
+			ToolName:    toolName,
+			Action:      "write",
+			Description: description,
+			Params: WorkspaceEditPermissionsParams{
+				Files: diffs,
+			},
+		},
+	)
+	if !p {
+		return WorkspaceEditResponseMetadata{}, permission.ErrorPermissionDenied
+	}
+
+	if err := util.WriteFileChanges(changes); err != nil {
+		return WorkspaceEditResponseMetadata{}, err
+	}
+
+	metadata := WorkspaceEditResponseMetadata{Files: diffs}
+	for i, change := range changes {
+		metadata.Additions += diffs[i].Additions
+		metadata.Removals += diffs[i].Removals
+
+		switch {
+		case change.NewPath != "":
+			// A renamed file is deleted under its old name and created
+			// under the new one
+			w.recordVersion(ctx, sessionID, change.Path, change.OldContent, "")
+			w.recordVersion(ctx, sessionID, change.NewPath, "", change.NewContent)
+			w.closeInLsp(ctx, change.Path)
+		case change.Dir:
+			// Its files are changes of their own
+		case change.Deleted:
+			w.recordVersion(ctx, sessionID, change.Path, change.OldContent, "")
+			w.closeInLsp(ctx, change.Path)
+		default:
+			w.recordVersion(ctx, sessionID, change.Path, change.OldContent, change.NewContent)
+			w.notifyLsp(ctx, change.Path)
+		}
+	}
+	return metadata, nil
+}
+
+func (w workspaceEditApplier) recordVersion(ctx context.Context, sessionID, path, oldContent, newContent string) {
+	file, err := w.files.GetByPathAndSession(ctx, path, sessionID)
+	if err != nil {
+		file, err = w.files.Create(ctx, sessionID, path, oldContent)
+		if err != nil {
+			logging.Debug("Error creating file history", "error", err)
+			return
+		}
+	}
+	if file.Content != oldContent {
+		// User manually changed the content, store an intermediate version
+		if _, err := w.files.CreateVersion(ctx, sessionID, path, oldContent); err != nil {
+			logging.Debug("Error creating file history version", "error", err)
+		}
+	}
+	if _, err := w.files.CreateVersion(ctx, sessionID, path, newContent); err != nil {
+		logging.Debug("Error creating file history version", "error", err)
+	}
+
+	if newContent != "" {
+		recordFileWrite(path)
+		recordFileRead(path)
+	}
+}
+
+// notifyLsp sends the new content of a file to the servers that have it
+// open, so later requests don't use stale positions.
+func (w workspaceEditApplier) notifyLsp(ctx context.Context, path string) {
+	for _, client := range w.lspClients {
+		if client.IsFileOpen(path) {
+			if err := client.NotifyChange(ctx, path); err != nil {
+				logging.Debug("Error notifying LSP of file change", "file", path, "error", err)
+			}
+		}
+	}
+}
+
+func (w workspaceEditApplier) closeInLsp(ctx context.Context, path string) {
+	for _, client := range w.lspClients {
+		if err := client.CloseFile(ctx, path); err != nil {
+			logging.Debug("Error closing file in LSP", "file", path, "error", err)
+		}
+	}
+}
+
+// summarizeFileDiffs lists the changed files with their line counts.
+func summarizeFileDiffs(metadata WorkspaceEditResponseMetadata) string {
+	lines := []string{fmt.Sprintf("%d file(s) changed, %d additions, %d removals", len(metadata.Files), metadata.Additions, metadata.Removals)}
+	for _, file := range metadata.Files {
+		lines = append(lines, "  "+file.Title())
+	}
+	return strings.Join(lines, "\n")
+}
@@ -188,6 +188,12 @@ func (w *writeTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error
 		return ToolResponse{}, fmt.Errorf("error writing file: %w", err)
 	}
 
+	formatted := formatOnWrite(ctx, filePath, oldContent, params.Content, w.lspClients)
+	if formatted.note != "" {
+		params.Content = formatted.content
+		diff, additions, removals = formatted.diff, formatted.additions, formatted.removals
+	}
+
 	// Check if file exists in history
 	file, err := w.files.GetByPathAndSession(ctx, filePath, sessionID)
 	if err != nil {
@@ -214,7 +220,7 @@ func (w *writeTool) Run(ctx context.Context, call ToolCall) (ToolResponse, error
 	recordFileRead(filePath)
 	waitForLspDiagnostics(ctx, filePath, w.lspClients)
 
-	result := fmt.Sprintf("File successfully written: %s", filePath)
+	result := formatted.withNote(fmt.Sprintf("File successfully written: %s", filePath))
 	result = fmt.Sprintf("<result>\n%s\n</result>", result)
 	result += getDiagnostics(filePath, w.lspClients)
 	return WithResponseMetadata(NewTextResponse(result),
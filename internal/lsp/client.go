@@ -24,6 +24,8 @@ type Client struct {
 	stdin  io.WriteCloser
 	stdout *bufio.Reader
 	stderr io.ReadCloser
+	// Requests and responses are written from several goroutines
+	stdinMu sync.Mutex
 
 	// Request ID counter
 	nextID atomic.Int32
@@ -126,6 +128,15 @@ func (c *Client) RegisterServerRequestHandler(method string, handler ServerReque
 	c.serverRequestHandlers[method] = handler
 }
 
+// WithApplyEditHandler sends the workspace/applyEdit requests the server makes
+// while fn runs to handler instead of applying them right away, e.g. to ask
+// for permission first.
+func (c *Client) WithApplyEditHandler(handler ServerRequestHandler, fn func() error) error {
+	c.RegisterServerRequestHandler("workspace/applyEdit", handler)
+	defer c.RegisterServerRequestHandler("workspace/applyEdit", HandleApplyEdit)
+	return fn()
+}
+
 func (c *Client) InitializeLSPClient(ctx context.Context, workspaceDir string) (*protocol.InitializeResult, error) {
 	initParams := &protocol.InitializeParams{
 		WorkspaceFoldersInitializeParams: protocol.WorkspaceFoldersInitializeParams{
@@ -148,6 +159,11 @@ func (c *Client) InitializeLSPClient(ctx context.Context, workspaceDir string) (
 			Capabilities: protocol.ClientCapabilities{
 				Workspace: protocol.WorkspaceClientCapabilities{
 					Configuration: true,
+					ApplyEdit:     true,
+					WorkspaceEdit: &protocol.WorkspaceEditClientCapabilities{
+						DocumentChanges:    true,
+						ResourceOperations: []protocol.ResourceOperationKind{protocol.Create, protocol.Rename, protocol.Delete},
+					},
 					DidChangeConfiguration: protocol.DidChangeConfigurationClientCapabilities{
 						DynamicRegistration: true,
 					},
@@ -171,9 +187,28 @@ func (c *Client) InitializeLSPClient(ctx context.Context, workspaceDir string) (
 					CodeAction: protocol.CodeActionClientCapabilities{
 						CodeActionLiteralSupport: protocol.ClientCodeActionLiteralOptions{
 							CodeActionKind: protocol.ClientCodeActionKindOptions{
-								ValueSet: []protocol.CodeActionKind{},
+								ValueSet: []protocol.CodeActionKind{
+									protocol.Empty,
+									protocol.QuickFix,
+									protocol.Refactor,
+									protocol.RefactorExtract,
+									protocol.RefactorInline,
+									protocol.RefactorRewrite,
+									protocol.Source,
+									protocol.SourceOrganizeImports,
+									protocol.SourceFixAll,
+								},
 							},
 						},
+						IsPreferredSupport: true,
+						DisabledSupport:    true,
+						DataSupport:        true,
+						ResolveSupport: &protocol.ClientCodeActionResolveOptions{
+							Properties: []string{"edit"},
+						},
+					},
+					Rename: &protocol.RenameClientCapabilities{
+						PrepareSupport: true,
 					},
 					PublishDiagnostics: protocol.PublishDiagnosticsClientCapabilities{
 						VersionSupport: true,
@@ -107,46 +107,14 @@ func (c *Client) handleMessages() {
 				logging.Debug("Received request from server", "method", msg.Method, "id", msg.ID)
 			}
 
-			response := &Message{
-				JSONRPC: "2.0",
-				ID:      msg.ID,
-			}
-
 			// Look up handler for this method
 			c.serverHandlersMu.RLock()
 			handler, ok := c.serverRequestHandlers[msg.Method]
 			c.serverHandlersMu.RUnlock()
 
-			if ok {
-				result, err := handler(msg.Params)
-				if err != nil {
-					response.Error = &ResponseError{
-						Code:    -32603,
-						Message: err.Error(),
-					}
-				} else {
-					rawJSON, err := json.Marshal(result)
-					if err != nil {
-						response.Error = &ResponseError{
-							Code:    -32603,
-							Message: fmt.Sprintf("failed to marshal response: %v", err),
-						}
-					} else {
-						response.Result = rawJSON
-					}
-				}
-			} else {
-				response.Error = &ResponseError{
-					Code:    -32601,
-					Message: fmt.Sprintf("method not found: %s", msg.Method),
-				}
-			}
-
-			// Send response back to server
-			if err := WriteMessage(c.stdin, response); err != nil {
-				logging.Error("Error sending response to server", "error", err)
-			}
-
+			// Handlers can wait for the user, like applyEdit asking for
+			// permission, so they don't hold up reading other messages
+			go c.respond(msg, handler, ok)
 			continue
 		}
 
@@ -186,6 +154,51 @@ func (c *Client) handleMessages() {
 	}
 }
 
+// respond runs the handler of a server request and sends its result back.
+func (c *Client) respond(msg *Message, handler ServerRequestHandler, ok bool) {
+	response := &Message{
+		JSONRPC: "2.0",
+		ID:      msg.ID,
+	}
+
+	if ok {
+		result, err := handler(msg.Params)
+		if err != nil {
+			response.Error = &ResponseError{
+				Code:    -32603,
+				Message: err.Error(),
+			}
+		} else {
+			rawJSON, err := json.Marshal(result)
+			if err != nil {
+				response.Error = &ResponseError{
+					Code:    -32603,
+					Message: fmt.Sprintf("failed to marshal response: %v", err),
+				}
+			} else {
+				response.Result = rawJSON
+			}
+		}
+	} else {
+		response.Error = &ResponseError{
+			Code:    -32601,
+			Message: fmt.Sprintf("method not found: %s", msg.Method),
+		}
+	}
+
+	// Send response back to server
+	if err := c.write(response); err != nil {
+		logging.Error("Error sending response to server", "error", err)
+	}
+}
+
+// write sends a message to the server, one at a time.
+func (c *Client) write(msg *Message) error {
+	c.stdinMu.Lock()
+	defer c.stdinMu.Unlock()
+	return WriteMessage(c.stdin, msg)
+}
+
 // Call makes a request and waits for the response
 func (c *Client) Call(ctx context.Context, method string, params any, result any) error {
 	cnf := config.Get()
@@ -213,7 +226,7 @@ func (c *Client) Call(ctx context.Context, method string, params any, result any
 	}()
 
 	// Send request
-	if err := WriteMessage(c.stdin, msg); err != nil {
+	if err := c.write(msg); err != nil {
 		return fmt.Errorf("failed to send request: %w", err)
 	}
 
@@ -259,7 +272,7 @@ func (c *Client) Notify(ctx context.Context, method string, params any) error {
 		return fmt.Errorf("failed to create notification: %w", err)
 	}
 
-	if err := WriteMessage(c.stdin, msg); err != nil {
+	if err := c.write(msg); err != nil {
 		return fmt.Errorf("failed to send notification: %w", err)
 	}
 
@@ -0,0 +1,76 @@
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerRequestsDontBlockResponses(t *testing.T) {
+	_, err := config.Load(t.TempDir(), false)
+	require.NoError(t, err)
+
+	clientIn, serverOut := io.Pipe()
+	serverIn, clientOut := io.Pipe()
+	defer serverOut.Close()
+	defer clientOut.Close()
+	client := &Client{
+		stdin:                 clientOut,
+		stdout:                bufio.NewReader(clientIn),
+		handlers:              make(map[int32]chan *Message),
+		serverRequestHandlers: make(map[string]ServerRequestHandler),
+		notificationHandlers:  make(map[string]NotificationHandler),
+	}
+	release := make(chan struct{})
+	client.RegisterServerRequestHandler("workspace/applyEdit", func(json.RawMessage) (any, error) {
+		// Like a permission dialog waiting for the user
+		<-release
+		return "applied", nil
+	})
+	go client.handleMessages()
+
+	fromClient := make(chan *Message)
+	go func() {
+		reader := bufio.NewReader(serverIn)
+		for {
+			msg, err := ReadMessage(reader)
+			if err != nil {
+				close(fromClient)
+				return
+			}
+			fromClient <- msg
+		}
+	}()
+
+	require.NoError(t, WriteMessage(serverOut, &Message{JSONRPC: "2.0", ID: 100, Method: "workspace/applyEdit"}))
+
+	// The client's own requests are answered while the edit waits
+	called := make(chan error)
+	var result string
+	go func() {
+		called <- client.Call(context.Background(), "workspace/executeCommand", nil, &result)
+	}()
+	request := <-fromClient
+	assert.Equal(t, "workspace/executeCommand", request.Method)
+	// In the background, a client stuck in a handler never reads it
+	go WriteMessage(serverOut, &Message{JSONRPC: "2.0", ID: request.ID, Result: json.RawMessage(`"done"`)})
+	select {
+	case err := <-called:
+		require.NoError(t, err)
+		assert.Equal(t, "done", result)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the response was not read while a server request was pending")
+	}
+
+	close(release)
+	response := <-fromClient
+	assert.Equal(t, int32(100), response.ID)
+	assert.JSONEq(t, `"applied"`, string(response.Result))
+}
@@ -1,51 +1,64 @@
 package util
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"unicode/utf16"
 
 	"github.com/opencode-ai/opencode/internal/lsp/protocol"
 )
 
-func applyTextEdits(uri protocol.DocumentUri, edits []protocol.TextEdit) error {
-	path := strings.TrimPrefix(string(uri), "file://")
-
-	// Read the file content
-	content, err := os.ReadFile(path)
-	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
-	}
+// FileChange is what a workspace edit does to one file.
+type FileChange struct {
+	Path string
+	// Set when the file is renamed, the new content goes there
+	NewPath    string
+	OldContent string
+	NewContent string
+	Created    bool
+	Deleted    bool
+	// Set for a deleted directory, the files in it are changes of their own
+	Dir bool
+}
 
+// ApplyTextEditsToContent applies text edits to the content of a document.
+func ApplyTextEditsToContent(content string, edits []protocol.TextEdit) (string, error) {
 	// Detect line ending style
 	var lineEnding string
-	if bytes.Contains(content, []byte("\r\n")) {
+	if strings.Contains(content, "\r\n") {
 		lineEnding = "\r\n"
 	} else {
 		lineEnding = "\n"
 	}
 
 	// Track if file ends with a newline
-	endsWithNewline := len(content) > 0 && bytes.HasSuffix(content, []byte(lineEnding))
+	endsWithNewline := len(content) > 0 && strings.HasSuffix(content, lineEnding)
 
 	// Split into lines without the endings
-	lines := strings.Split(string(content), lineEnding)
+	lines := strings.Split(content, lineEnding)
 
 	// Check for overlapping edits
 	for i, edit1 := range edits {
 		for j := i + 1; j < len(edits); j++ {
 			if rangesOverlap(edit1.Range, edits[j].Range) {
-				return fmt.Errorf("overlapping edits detected between edit %d and %d", i, j)
+				return "", fmt.Errorf("overlapping edits detected between edit %d and %d", i, j)
 			}
 		}
 	}
 
-	// Sort edits in reverse order
+	// Sort edits in reverse order. Inserts at the same position keep their
+	// order in the document, so the later one is applied first.
 	sortedEdits := make([]protocol.TextEdit, len(edits))
 	copy(sortedEdits, edits)
-	sort.Slice(sortedEdits, func(i, j int) bool {
+	for i, j := 0, len(sortedEdits)-1; i < j; i, j = i+1, j-1 {
+		sortedEdits[i], sortedEdits[j] = sortedEdits[j], sortedEdits[i]
+	}
+	sort.SliceStable(sortedEdits, func(i, j int) bool {
 		if sortedEdits[i].Range.Start.Line != sortedEdits[j].Range.Start.Line {
 			return sortedEdits[i].Range.Start.Line > sortedEdits[j].Range.Start.Line
 		}
@@ -54,46 +67,47 @@ func applyTextEdits(uri protocol.DocumentUri, edits []protocol.TextEdit) error {
 
 	// Apply each edit
 	for _, edit := range sortedEdits {
+		if lineEnding != "\n" {
+			edit.NewText = strings.ReplaceAll(edit.NewText, "\r\n", "\n")
+		}
 		newLines, err := applyTextEdit(lines, edit)
 		if err != nil {
-			return fmt.Errorf("failed to apply edit: %w", err)
+			return "", fmt.Errorf("failed to apply edit: %w", err)
 		}
 		lines = newLines
 	}
 
 	// Join lines with proper line endings
-	var newContent strings.Builder
-	for i, line := range lines {
-		if i > 0 {
-			newContent.WriteString(lineEnding)
-		}
-		newContent.WriteString(line)
-	}
+	newContent := strings.Join(lines, lineEnding)
 
 	// Only add a newline if the original file had one and we haven't already added it
-	if endsWithNewline && !strings.HasSuffix(newContent.String(), lineEnding) {
-		newContent.WriteString(lineEnding)
+	if endsWithNewline && !strings.HasSuffix(newContent, lineEnding) {
+		newContent += lineEnding
 	}
 
-	if err := os.WriteFile(path, []byte(newContent.String()), 0o644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
-	}
-
-	return nil
+	return newContent, nil
 }
 
 func applyTextEdit(lines []string, edit protocol.TextEdit) ([]string, error) {
 	startLine := int(edit.Range.Start.Line)
 	endLine := int(edit.Range.End.Line)
-	startChar := int(edit.Range.Start.Character)
-	endChar := int(edit.Range.End.Character)
 
 	// Validate positions
 	if startLine < 0 || startLine >= len(lines) {
 		return nil, fmt.Errorf("invalid start line: %d", startLine)
 	}
+
+	// Positions count UTF-16 code units, the lines are indexed by bytes
+	startLineContent := lines[startLine]
+	startChar := byteOffset(startLineContent, edit.Range.Start.Character)
+
+	endChar := 0
 	if endLine < 0 || endLine >= len(lines) {
+		// A range past the last line ends at the end of the document
 		endLine = len(lines) - 1
+		endChar = len(lines[endLine])
+	} else {
+		endChar = byteOffset(lines[endLine], edit.Range.End.Character)
 	}
 
 	// Create result slice with initial capacity
@@ -102,39 +116,20 @@ func applyTextEdit(lines []string, edit protocol.TextEdit) ([]string, error) {
 	// Copy lines before edit
 	result = append(result, lines[:startLine]...)
 
-	// Get the prefix of the start line
-	startLineContent := lines[startLine]
-	if startChar < 0 || startChar > len(startLineContent) {
-		startChar = len(startLineContent)
-	}
 	prefix := startLineContent[:startChar]
+	suffix := lines[endLine][endChar:]
 
-	// Get the suffix of the end line
-	endLineContent := lines[endLine]
-	if endChar < 0 || endChar > len(endLineContent) {
-		endChar = len(endLineContent)
-	}
-	suffix := endLineContent[endChar:]
+	// Split new text into lines, being careful not to add extra newlines
+	newLines := strings.Split(edit.NewText, "\n")
 
-	// Handle the edit
-	if edit.NewText == "" {
-		if prefix+suffix != "" {
-			result = append(result, prefix+suffix)
-		}
+	if len(newLines) == 1 {
+		// Single line change
+		result = append(result, prefix+newLines[0]+suffix)
 	} else {
-		// Split new text into lines, being careful not to add extra newlines
-		// newLines := strings.Split(strings.TrimRight(edit.NewText, "\n"), "\n")
-		newLines := strings.Split(edit.NewText, "\n")
-
-		if len(newLines) == 1 {
-			// Single line change
-			result = append(result, prefix+newLines[0]+suffix)
-		} else {
-			// Multi-line change
-			result = append(result, prefix+newLines[0])
-			result = append(result, newLines[1:len(newLines)-1]...)
-			result = append(result, newLines[len(newLines)-1]+suffix)
-		}
+		// Multi-line change
+		result = append(result, prefix+newLines[0])
+		result = append(result, newLines[1:len(newLines)-1]...)
+		result = append(result, newLines[len(newLines)-1]+suffix)
 	}
 
 	// Add remaining lines
@@ -145,50 +140,125 @@ func applyTextEdit(lines []string, edit protocol.TextEdit) ([]string, error) {
 	return result, nil
 }
 
+// byteOffset converts a UTF-16 character offset into a byte offset in line,
+// clamped to the line length.
+func byteOffset(line string, character uint32) int {
+	var units uint32
+	for i, r := range line {
+		if units >= character {
+			return i
+		}
+		units += uint32(utf16.RuneLen(r))
+	}
+	return len(line)
+}
+
+// workspaceEditPreview tracks the files of a workspace edit while it is
+// computed.
+type workspaceEditPreview struct {
+	changes []*FileChange
+	// By current path, renamed files are found under their new path
+	byPath map[string]*FileChange
+}
+
+func uriPath(uri protocol.DocumentUri) string {
+	return strings.TrimPrefix(string(uri), "file://")
+}
+
+func (p *workspaceEditPreview) file(path string) (*FileChange, error) {
+	if change, ok := p.byPath[path]; ok {
+		if change.Deleted {
+			return nil, fmt.Errorf("file was deleted by an earlier change: %s", path)
+		}
+		return change, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	change := &FileChange{Path: path, OldContent: string(content), NewContent: string(content)}
+	p.track(path, change)
+	return change, nil
+}
+
+func (p *workspaceEditPreview) track(path string, change *FileChange) {
+	p.changes = append(p.changes, change)
+	p.byPath[path] = change
+}
+
+func (p *workspaceEditPreview) exists(path string) bool {
+	if change, ok := p.byPath[path]; ok {
+		return !change.Deleted
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func (p *workspaceEditPreview) applyTextEdits(uri protocol.DocumentUri, edits []protocol.TextEdit) error {
+	change, err := p.file(uriPath(uri))
+	if err != nil {
+		return err
+	}
+	content, err := ApplyTextEditsToContent(change.NewContent, edits)
+	if err != nil {
+		return err
+	}
+	change.NewContent = content
+	return nil
+}
+
 // applyDocumentChange applies a DocumentChange (create/rename/delete operations)
-func applyDocumentChange(change protocol.DocumentChange) error {
+func (p *workspaceEditPreview) applyDocumentChange(change protocol.DocumentChange) error {
 	if change.CreateFile != nil {
-		path := strings.TrimPrefix(string(change.CreateFile.URI), "file://")
-		if change.CreateFile.Options != nil {
-			if change.CreateFile.Options.Overwrite {
-				// Proceed with overwrite
-			} else if change.CreateFile.Options.IgnoreIfExists {
-				if _, err := os.Stat(path); err == nil {
-					return nil // File exists and we're ignoring it
-				}
+		path := uriPath(change.CreateFile.URI)
+		options := change.CreateFile.Options
+		if p.exists(path) {
+			if options != nil && options.IgnoreIfExists && !options.Overwrite {
+				return nil // File exists and we're ignoring it
 			}
-		}
-		if err := os.WriteFile(path, []byte(""), 0o644); err != nil {
-			return fmt.Errorf("failed to create file: %w", err)
+			existing, err := p.file(path)
+			if err != nil {
+				return err
+			}
+			existing.NewContent = ""
+		} else {
+			p.track(path, &FileChange{Path: path, Created: true})
 		}
 	}
 
 	if change.DeleteFile != nil {
-		path := strings.TrimPrefix(string(change.DeleteFile.URI), "file://")
-		if change.DeleteFile.Options != nil && change.DeleteFile.Options.Recursive {
-			if err := os.RemoveAll(path); err != nil {
-				return fmt.Errorf("failed to delete directory recursively: %w", err)
-			}
-		} else {
-			if err := os.Remove(path); err != nil {
-				return fmt.Errorf("failed to delete file: %w", err)
+		path := uriPath(change.DeleteFile.URI)
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			return p.deleteDir(path, change.DeleteFile.Options != nil && change.DeleteFile.Options.Recursive)
+		}
+		existing, err := p.file(path)
+		if err != nil {
+			if change.DeleteFile.Options != nil && change.DeleteFile.Options.IgnoreIfNotExists {
+				return nil
 			}
+			return err
 		}
+		existing.Deleted = true
+		existing.NewContent = ""
 	}
 
 	if change.RenameFile != nil {
-		oldPath := strings.TrimPrefix(string(change.RenameFile.OldURI), "file://")
-		newPath := strings.TrimPrefix(string(change.RenameFile.NewURI), "file://")
-		if change.RenameFile.Options != nil {
-			if !change.RenameFile.Options.Overwrite {
-				if _, err := os.Stat(newPath); err == nil {
-					return fmt.Errorf("target file already exists and overwrite is not allowed: %s", newPath)
-				}
+		oldPath := uriPath(change.RenameFile.OldURI)
+		newPath := uriPath(change.RenameFile.NewURI)
+		options := change.RenameFile.Options
+		if p.exists(newPath) && (options == nil || !options.Overwrite) {
+			if options != nil && options.IgnoreIfExists {
+				return nil
 			}
+			return fmt.Errorf("target file already exists and overwrite is not allowed: %s", newPath)
 		}
-		if err := os.Rename(oldPath, newPath); err != nil {
-			return fmt.Errorf("failed to rename file: %w", err)
+		existing, err := p.file(oldPath)
+		if err != nil {
+			return err
 		}
+		delete(p.byPath, oldPath)
+		existing.NewPath = newPath
+		p.byPath[newPath] = existing
 	}
 
 	if change.TextDocumentEdit != nil {
@@ -200,39 +270,168 @@ func applyDocumentChange(change protocol.DocumentChange) error {
 				return fmt.Errorf("invalid edit type: %w", err)
 			}
 		}
-		return applyTextEdits(change.TextDocumentEdit.TextDocument.URI, textEdits)
+		return p.applyTextEdits(change.TextDocumentEdit.TextDocument.URI, textEdits)
 	}
 
 	return nil
 }
 
-// ApplyWorkspaceEdit applies the given WorkspaceEdit to the filesystem
-func ApplyWorkspaceEdit(edit protocol.WorkspaceEdit) error {
+// movedOrDeleted reports whether an earlier change already took the file
+// away from path.
+func (p *workspaceEditPreview) movedOrDeleted(path string) bool {
+	if change, ok := p.byPath[path]; ok {
+		return change.Deleted
+	}
+	return slices.ContainsFunc(p.changes, func(change *FileChange) bool {
+		return change.Path == path && change.NewPath != ""
+	})
+}
+
+// deleteDir deletes a directory. Without recursive it must be empty,
+// otherwise every file in it is listed as deleted before the directory.
+func (p *workspaceEditPreview) deleteDir(path string, recursive bool) error {
+	if !recursive {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return fmt.Errorf("failed to read directory: %w", err)
+		}
+		if len(entries) > 0 {
+			return fmt.Errorf("directory is not empty and the delete is not recursive: %s", path)
+		}
+	} else {
+		err := filepath.WalkDir(path, func(file string, entry os.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+			if p.movedOrDeleted(file) {
+				return nil
+			}
+			change, err := p.file(file)
+			if err != nil {
+				return err
+			}
+			change.Deleted = true
+			change.NewContent = ""
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read directory: %w", err)
+		}
+		// Files created in the directory by earlier changes
+		for file, change := range p.byPath {
+			if strings.HasPrefix(file, path+string(filepath.Separator)) {
+				change.Deleted = true
+				change.NewContent = ""
+			}
+		}
+	}
+	p.track(path, &FileChange{Path: path, Deleted: true, Dir: true})
+	return nil
+}
+
+// PreviewWorkspaceEdit computes what the given WorkspaceEdit does to each
+// file without touching the filesystem. Files are listed in the order they
+// are first changed.
+func PreviewWorkspaceEdit(edit protocol.WorkspaceEdit) ([]FileChange, error) {
+	preview := &workspaceEditPreview{byPath: map[string]*FileChange{}}
+
 	// Handle Changes field
-	for uri, textEdits := range edit.Changes {
-		if err := applyTextEdits(uri, textEdits); err != nil {
-			return fmt.Errorf("failed to apply text edits: %w", err)
+	uris := make([]protocol.DocumentUri, 0, len(edit.Changes))
+	for uri := range edit.Changes {
+		uris = append(uris, uri)
+	}
+	sort.Slice(uris, func(i, j int) bool { return uris[i] < uris[j] })
+	for _, uri := range uris {
+		if err := preview.applyTextEdits(uri, edit.Changes[uri]); err != nil {
+			return nil, fmt.Errorf("failed to apply text edits: %w", err)
 		}
 	}
 
 	// Handle DocumentChanges field
 	for _, change := range edit.DocumentChanges {
-		if err := applyDocumentChange(change); err != nil {
-			return fmt.Errorf("failed to apply document change: %w", err)
+		if err := preview.applyDocumentChange(change); err != nil {
+			return nil, fmt.Errorf("failed to apply document change: %w", err)
+		}
+	}
+
+	changes := make([]FileChange, 0, len(preview.changes))
+	for _, change := range preview.changes {
+		if change.OldContent == change.NewContent && change.NewPath == "" && !change.Created && !change.Deleted {
+			continue
 		}
+		changes = append(changes, *change)
 	}
+	return changes, nil
+}
 
+// WriteFileChanges writes the result of PreviewWorkspaceEdit to the
+// filesystem.
+func WriteFileChanges(changes []FileChange) error {
+	var errs []error
+	for _, change := range changes {
+		switch {
+		case change.Dir:
+			// The files in it were deleted before, RemoveAll only catches
+			// what was added since the preview
+			if err := os.RemoveAll(change.Path); err != nil {
+				errs = append(errs, fmt.Errorf("failed to delete directory: %w", err))
+			}
+		case change.Deleted:
+			if change.Created {
+				continue
+			}
+			if err := os.Remove(change.Path); err != nil {
+				errs = append(errs, fmt.Errorf("failed to delete file: %w", err))
+			}
+		case change.NewPath != "":
+			if err := writeFile(change.NewPath, change.NewContent); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if !change.Created {
+				if err := os.Remove(change.Path); err != nil {
+					errs = append(errs, fmt.Errorf("failed to rename file: %w", err))
+				}
+			}
+		default:
+			if err := writeFile(change.Path, change.NewContent); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func writeFile(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create parent directories: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
 	return nil
 }
 
+// ApplyWorkspaceEdit applies the given WorkspaceEdit to the filesystem. Nothing
+// is written when one of the changes can't be applied.
+func ApplyWorkspaceEdit(edit protocol.WorkspaceEdit) error {
+	changes, err := PreviewWorkspaceEdit(edit)
+	if err != nil {
+		return err
+	}
+	return WriteFileChanges(changes)
+}
+
+// rangesOverlap reports whether two ranges share characters. Ranges that only
+// touch, like an insert at the end of a replaced range, don't overlap.
 func rangesOverlap(r1, r2 protocol.Range) bool {
 	if r1.Start.Line > r2.End.Line || r2.Start.Line > r1.End.Line {
 		return false
 	}
-	if r1.Start.Line == r2.End.Line && r1.Start.Character > r2.End.Character {
+	if r1.Start.Line == r2.End.Line && r1.Start.Character >= r2.End.Character {
 		return false
 	}
-	if r2.Start.Line == r1.End.Line && r2.Start.Character > r1.End.Character {
+	if r2.Start.Line == r1.End.Line && r2.Start.Character >= r1.End.Character {
 		return false
 	}
 	return true
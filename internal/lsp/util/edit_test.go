@@ -0,0 +1,159 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencode-ai/opencode/internal/lsp/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func textEdit(startLine, startChar, endLine, endChar uint32, text string) protocol.TextEdit {
+	return protocol.TextEdit{
+		Range: protocol.Range{
+			Start: protocol.Position{Line: startLine, Character: startChar},
+			End:   protocol.Position{Line: endLine, Character: endChar},
+		},
+		NewText: text,
+	}
+}
+
+func TestApplyTextEditsToContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		edits    []protocol.TextEdit
+		expected string
+	}{
+		{
+			name:     "replace on several lines",
+			content:  "foo := 1\nbar(foo)\n",
+			edits:    []protocol.TextEdit{textEdit(0, 0, 0, 3, "baz"), textEdit(1, 4, 1, 7, "baz")},
+			expected: "baz := 1\nbar(baz)\n",
+		},
+		{
+			name:     "emptied line is kept",
+			content:  "a\n  \nb\n",
+			edits:    []protocol.TextEdit{textEdit(1, 0, 1, 2, "")},
+			expected: "a\n\nb\n",
+		},
+		{
+			name:     "characters are UTF-16 units",
+			content:  "s := \"😀\" + foo\n",
+			edits:    []protocol.TextEdit{textEdit(0, 12, 0, 15, "bar")},
+			expected: "s := \"😀\" + bar\n",
+		},
+		{
+			name:     "inserts at the same position keep their order",
+			content:  "x\n",
+			edits:    []protocol.TextEdit{textEdit(0, 0, 0, 0, "a"), textEdit(0, 0, 0, 0, "b")},
+			expected: "abx\n",
+		},
+		{
+			name:     "touching edits",
+			content:  "foo()\n",
+			edits:    []protocol.TextEdit{textEdit(0, 0, 0, 3, "bar"), textEdit(0, 3, 0, 3, "Baz")},
+			expected: "barBaz()\n",
+		},
+		{
+			name:     "range past the end",
+			content:  "a\nb",
+			edits:    []protocol.TextEdit{textEdit(1, 0, 5, 0, "c\n")},
+			expected: "a\nc\n",
+		},
+		{
+			name:     "CRLF line endings",
+			content:  "a\r\nb\r\n",
+			edits:    []protocol.TextEdit{textEdit(1, 0, 1, 1, "c\r\nd")},
+			expected: "a\r\nc\r\nd\r\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ApplyTextEditsToContent(tt.content, tt.edits)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	_, err := ApplyTextEditsToContent("abc", []protocol.TextEdit{textEdit(0, 0, 0, 2, "x"), textEdit(0, 1, 0, 3, "y")})
+	assert.Error(t, err)
+}
+
+func TestPreviewWorkspaceEdit(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.go")
+	b := filepath.Join(dir, "b.go")
+	moved := filepath.Join(dir, "moved.go")
+	require.NoError(t, os.WriteFile(a, []byte("func foo() {}\n"), 0o644))
+	require.NoError(t, os.WriteFile(b, []byte("foo()\n"), 0o644))
+
+	edit := protocol.WorkspaceEdit{
+		Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+			protocol.DocumentUri("file://" + a): {textEdit(0, 5, 0, 8, "bar")},
+		},
+		DocumentChanges: []protocol.DocumentChange{
+			{RenameFile: &protocol.RenameFile{Kind: "rename", OldURI: protocol.DocumentUri("file://" + b), NewURI: protocol.DocumentUri("file://" + moved)}},
+			{TextDocumentEdit: &protocol.TextDocumentEdit{
+				TextDocument: protocol.OptionalVersionedTextDocumentIdentifier{TextDocumentIdentifier: protocol.TextDocumentIdentifier{URI: protocol.DocumentUri("file://" + moved)}},
+				Edits:        []protocol.Or_TextDocumentEdit_edits_Elem{{Value: textEdit(0, 0, 0, 3, "bar")}},
+			}},
+		},
+	}
+
+	changes, err := PreviewWorkspaceEdit(edit)
+	require.NoError(t, err)
+	assert.Equal(t, []FileChange{
+		{Path: a, OldContent: "func foo() {}\n", NewContent: "func bar() {}\n"},
+		{Path: b, NewPath: moved, OldContent: "foo()\n", NewContent: "bar()\n"},
+	}, changes)
+
+	// Nothing is written by the preview
+	content, err := os.ReadFile(a)
+	require.NoError(t, err)
+	assert.Equal(t, "func foo() {}\n", string(content))
+
+	require.NoError(t, WriteFileChanges(changes))
+	content, err = os.ReadFile(moved)
+	require.NoError(t, err)
+	assert.Equal(t, "bar()\n", string(content))
+	assert.NoFileExists(t, b)
+}
+
+func TestPreviewWorkspaceEditDeleteDir(t *testing.T) {
+	dir := t.TempDir()
+	pkg := filepath.Join(dir, "pkg")
+	x := filepath.Join(pkg, "x.go")
+	y := filepath.Join(pkg, "sub", "y.go")
+	empty := filepath.Join(dir, "empty")
+	require.NoError(t, os.MkdirAll(filepath.Dir(y), 0o755))
+	require.NoError(t, os.Mkdir(empty, 0o755))
+	require.NoError(t, os.WriteFile(x, []byte("package pkg\n"), 0o644))
+	require.NoError(t, os.WriteFile(y, []byte("package sub\n"), 0o644))
+
+	deleteDir := func(path string, recursive bool) protocol.WorkspaceEdit {
+		return protocol.WorkspaceEdit{DocumentChanges: []protocol.DocumentChange{
+			{DeleteFile: &protocol.DeleteFile{Kind: "delete", URI: protocol.DocumentUri("file://" + path), Options: &protocol.DeleteFileOptions{Recursive: recursive}}},
+		}}
+	}
+
+	_, err := PreviewWorkspaceEdit(deleteDir(pkg, false))
+	assert.ErrorContains(t, err, "not recursive")
+
+	changes, err := PreviewWorkspaceEdit(deleteDir(pkg, true))
+	require.NoError(t, err)
+	assert.Equal(t, []FileChange{
+		{Path: y, OldContent: "package sub\n", Deleted: true},
+		{Path: x, OldContent: "package pkg\n", Deleted: true},
+		{Path: pkg, Deleted: true, Dir: true},
+	}, changes)
+	assert.DirExists(t, pkg)
+
+	require.NoError(t, WriteFileChanges(changes))
+	assert.NoDirExists(t, pkg)
+
+	require.NoError(t, ApplyWorkspaceEdit(deleteDir(empty, false)))
+	assert.NoDirExists(t, empty)
+}
@@ -44,6 +44,17 @@ func (tc ReasoningContent) String() string {
 }
 func (ReasoningContent) isPart() {}
 
+// ReasoningState holds opaque reasoning state that has to be sent back to the
+// provider on the next turn, like the encrypted reasoning items of the OpenAI
+// Responses API.
+type ReasoningState struct {
+	ID               string   `json:"id"`
+	EncryptedContent string   `json:"encrypted_content,omitempty"`
+	Summary          []string `json:"summary,omitempty"`
+}
+
+func (ReasoningState) isPart() {}
+
 type TextContent struct {
 	Text string `json:"text"`
 }
@@ -92,11 +103,12 @@ type ToolCall struct {
 func (ToolCall) isPart() {}
 
 type ToolResult struct {
-	ToolCallID string `json:"tool_call_id"`
-	Name       string `json:"name"`
-	Content    string `json:"content"`
-	Metadata   string `json:"metadata"`
-	IsError    bool   `json:"is_error"`
+	ToolCallID string          `json:"tool_call_id"`
+	Name       string          `json:"name"`
+	Content    string          `json:"content"`
+	Metadata   string          `json:"metadata"`
+	IsError    bool            `json:"is_error"`
+	Images     []BinaryContent `json:"images,omitempty"`
 }
 
 func (ToolResult) isPart() {}
@@ -136,6 +148,16 @@ func (m *Message) ReasoningContent() ReasoningContent {
 	return ReasoningContent{}
 }
 
+func (m *Message) ReasoningStates() []ReasoningState {
+	states := make([]ReasoningState, 0)
+	for _, part := range m.Parts {
+		if c, ok := part.(ReasoningState); ok {
+			states = append(states, c)
+		}
+	}
+	return states
+}
+
 func (m *Message) ImageURLContent() []ImageURLContent {
 	imageURLContents := make([]ImageURLContent, 0)
 	for _, part := range m.Parts {
@@ -297,6 +319,21 @@ func (m *Message) SetToolCalls(tc []ToolCall) {
 	}
 }
 
+func (m *Message) SetReasoningStates(states []ReasoningState) {
+	// remove any existing reasoning state, it is replaced as a whole
+	parts := make([]ContentPart, 0)
+	for _, part := range m.Parts {
+		if _, ok := part.(ReasoningState); ok {
+			continue
+		}
+		parts = append(parts, part)
+	}
+	m.Parts = parts
+	for _, state := range states {
+		m.Parts = append(m.Parts, state)
+	}
+}
+
 func (m *Message) AddToolResult(tr ToolResult) {
 	m.Parts = append(m.Parts, tr)
 }
@@ -163,13 +163,14 @@ func (s *service) fromDBItem(item db.Message) (Message, error) {
 type partType string
 
 const (
-	reasoningType  partType = "reasoning"
-	textType       partType = "text"
-	imageURLType   partType = "image_url"
-	binaryType     partType = "binary"
-	toolCallType   partType = "tool_call"
-	toolResultType partType = "tool_result"
-	finishType     partType = "finish"
+	reasoningType      partType = "reasoning"
+	reasoningStateType partType = "reasoning_state"
+	textType           partType = "text"
+	imageURLType       partType = "image_url"
+	binaryType         partType = "binary"
+	toolCallType       partType = "tool_call"
+	toolResultType     partType = "tool_result"
+	finishType         partType = "finish"
 )
 
 type partWrapper struct {
@@ -186,6 +187,8 @@ func marshallParts(parts []ContentPart) ([]byte, error) {
 		switch part.(type) {
 		case ReasoningContent:
 			typ = reasoningType
+		case ReasoningState:
+			typ = reasoningStateType
 		case TextContent:
 			typ = textType
 		case ImageURLContent:
@@ -236,6 +239,12 @@ func unmarshallParts(data []byte) ([]ContentPart, error) {
 				return nil, err
 			}
 			parts = append(parts, part)
+		case reasoningStateType:
+			part := ReasoningState{}
+			if err := json.Unmarshal(wrapper.Data, &part); err != nil {
+				return nil, err
+			}
+			parts = append(parts, part)
 		case textType:
 			part := TextContent{}
 			if err := json.Unmarshal(wrapper.Data, &part); err != nil {
@@ -0,0 +1,119 @@
+package question
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/opencode-ai/opencode/internal/pubsub"
+)
+
+var (
+	// ErrDismissed is returned when the user closes the question without
+	// answering it.
+	ErrDismissed = errors.New("the user dismissed the question")
+	// ErrNoUser is returned for sessions that run without a user, like
+	// the ones of non-interactive mode.
+	ErrNoUser = errors.New("no user is available to answer questions")
+)
+
+type CreateQuestionRequest struct {
+	SessionID string   `json:"session_id"`
+	Question  string   `json:"question"`
+	Options   []string `json:"options"`
+}
+
+type Question struct {
+	ID        string   `json:"id"`
+	SessionID string   `json:"session_id"`
+	Question  string   `json:"question"`
+	Options   []string `json:"options"`
+}
+
+type Service interface {
+	pubsub.Suscriber[Question]
+	Ask(ctx context.Context, opts CreateQuestionRequest) (string, error)
+	Answer(question Question, answer string)
+	Dismiss(question Question)
+	NoUserSession(sessionID string)
+}
+
+type response struct {
+	answer    string
+	dismissed bool
+}
+
+type questionService struct {
+	*pubsub.Broker[Question]
+
+	pendingQuestions sync.Map
+	noUserSessions   []string
+	mu               sync.RWMutex
+}
+
+func (s *questionService) Answer(question Question, answer string) {
+	respCh, ok := s.pendingQuestions.Load(question.ID)
+	if ok {
+		respCh.(chan response) <- response{answer: answer}
+	}
+}
+
+func (s *questionService) Dismiss(question Question) {
+	respCh, ok := s.pendingQuestions.Load(question.ID)
+	if ok {
+		respCh.(chan response) <- response{dismissed: true}
+	}
+}
+
+// Ask publishes the question and blocks until the user answers or
+// dismisses it. When ctx is done first, the question is withdrawn with a
+// deleted event.
+func (s *questionService) Ask(ctx context.Context, opts CreateQuestionRequest) (string, error) {
+	s.mu.RLock()
+	noUser := slices.Contains(s.noUserSessions, opts.SessionID)
+	s.mu.RUnlock()
+	if noUser {
+		return "", ErrNoUser
+	}
+
+	question := Question{
+		ID:        uuid.New().String(),
+		SessionID: opts.SessionID,
+		Question:  opts.Question,
+		Options:   opts.Options,
+	}
+
+	respCh := make(chan response, 1)
+
+	s.pendingQuestions.Store(question.ID, respCh)
+	defer s.pendingQuestions.Delete(question.ID)
+
+	s.Publish(pubsub.CreatedEvent, question)
+
+	select {
+	case resp := <-respCh:
+		if resp.dismissed {
+			return "", ErrDismissed
+		}
+		return resp.answer, nil
+	case <-ctx.Done():
+		s.Publish(pubsub.DeletedEvent, question)
+		return "", ctx.Err()
+	}
+}
+
+// NoUserSession makes questions of the session fail with ErrNoUser instead
+// of waiting for an answer.
+func (s *questionService) NoUserSession(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.noUserSessions = append(s.noUserSessions, sessionID)
+}
+
+func NewService() Service {
+	return &questionService{
+		Broker: pubsub.NewBroker[Question](),
+	}
+}
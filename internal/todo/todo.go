@@ -0,0 +1,181 @@
+package todo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/opencode-ai/opencode/internal/db"
+	"github.com/opencode-ai/opencode/internal/pubsub"
+)
+
+type Status string
+
+const (
+	StatusPending    Status = "pending"
+	StatusInProgress Status = "in_progress"
+	StatusCompleted  Status = "completed"
+)
+
+// Valid reports whether s is a known status.
+func (s Status) Valid() bool {
+	switch s {
+	case StatusPending, StatusInProgress, StatusCompleted:
+		return true
+	}
+	return false
+}
+
+// Todo is an item of the task list the agent keeps for a session. Items are
+// numbered by their position, which doesn't change when others are removed.
+type Todo struct {
+	ID        string
+	SessionID string
+	Content   string
+	Status    Status
+	Position  int64
+	CreatedAt int64
+	UpdatedAt int64
+}
+
+type Service interface {
+	pubsub.Suscriber[Todo]
+	Create(ctx context.Context, sessionID, content string) (Todo, error)
+	Get(ctx context.Context, id string) (Todo, error)
+	List(ctx context.Context, sessionID string) ([]Todo, error)
+	Update(ctx context.Context, todo Todo) (Todo, error)
+	Delete(ctx context.Context, id string) error
+	DeleteSessionTodos(ctx context.Context, sessionID string) error
+}
+
+type service struct {
+	*pubsub.Broker[Todo]
+	q db.Querier
+}
+
+func NewService(q db.Querier) Service {
+	return &service{
+		Broker: pubsub.NewBroker[Todo](),
+		q:      q,
+	}
+}
+
+func (s *service) Create(ctx context.Context, sessionID, content string) (Todo, error) {
+	todos, err := s.List(ctx, sessionID)
+	if err != nil {
+		return Todo{}, err
+	}
+	position := int64(1)
+	if len(todos) > 0 {
+		position = todos[len(todos)-1].Position + 1
+	}
+	dbTodo, err := s.q.CreateTodo(ctx, db.CreateTodoParams{
+		ID:        uuid.New().String(),
+		SessionID: sessionID,
+		Content:   content,
+		Status:    string(StatusPending),
+		Position:  position,
+	})
+	if err != nil {
+		return Todo{}, err
+	}
+	todo := s.fromDBItem(dbTodo)
+	s.Publish(pubsub.CreatedEvent, todo)
+	return todo, nil
+}
+
+func (s *service) Get(ctx context.Context, id string) (Todo, error) {
+	dbTodo, err := s.q.GetTodo(ctx, id)
+	if err != nil {
+		return Todo{}, err
+	}
+	return s.fromDBItem(dbTodo), nil
+}
+
+func (s *service) List(ctx context.Context, sessionID string) ([]Todo, error) {
+	dbTodos, err := s.q.ListTodosBySession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	todos := make([]Todo, len(dbTodos))
+	for i, dbTodo := range dbTodos {
+		todos[i] = s.fromDBItem(dbTodo)
+	}
+	return todos, nil
+}
+
+func (s *service) Update(ctx context.Context, todo Todo) (Todo, error) {
+	dbTodo, err := s.q.UpdateTodo(ctx, db.UpdateTodoParams{
+		ID:      todo.ID,
+		Content: todo.Content,
+		Status:  string(todo.Status),
+	})
+	if err != nil {
+		return Todo{}, err
+	}
+	todo = s.fromDBItem(dbTodo)
+	s.Publish(pubsub.UpdatedEvent, todo)
+	return todo, nil
+}
+
+func (s *service) Delete(ctx context.Context, id string) error {
+	todo, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = s.q.DeleteTodo(ctx, id)
+	if err != nil {
+		return err
+	}
+	s.Publish(pubsub.DeletedEvent, todo)
+	return nil
+}
+
+func (s *service) DeleteSessionTodos(ctx context.Context, sessionID string) error {
+	todos, err := s.List(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	err = s.q.DeleteSessionTodos(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	for _, todo := range todos {
+		s.Publish(pubsub.DeletedEvent, todo)
+	}
+	return nil
+}
+
+func (s *service) fromDBItem(item db.Todo) Todo {
+	return Todo{
+		ID:        item.ID,
+		SessionID: item.SessionID,
+		Content:   item.Content,
+		Status:    Status(item.Status),
+		Position:  item.Position,
+		CreatedAt: item.CreatedAt,
+		UpdatedAt: item.UpdatedAt,
+	}
+}
+
+// Format lists the items with their number and a checkbox for their status,
+// like "2. [~] Write the migration".
+func Format(todos []Todo) string {
+	lines := make([]string, 0, len(todos))
+	for _, todo := range todos {
+		lines = append(lines, fmt.Sprintf("%d. %s %s", todo.Position, todo.Status.checkbox(), todo.Content))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (s Status) checkbox() string {
+	switch s {
+	case StatusCompleted:
+		return "[x]"
+	case StatusInProgress:
+		return "[~]"
+	default:
+		return "[ ]"
+	}
+}
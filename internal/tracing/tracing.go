@@ -0,0 +1,143 @@
+// Package tracing exports OpenTelemetry traces of agent turns, provider calls
+// and tool runs.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/httpclient"
+	"github.com/opencode-ai/opencode/internal/version"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName       = "github.com/opencode-ai/opencode"
+	defaultTraceFile = "traces.jsonl"
+)
+
+// Init sets up the global tracer provider from the tracing configuration. The
+// returned function flushes pending spans and must be called on exit. When
+// tracing is disabled the global no-op provider is kept and spans cost nothing.
+func Init(ctx context.Context) (func(context.Context) error, error) {
+	cfg := config.Get()
+	if cfg.Tracing.Exporter == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName("opencode"),
+		semconv.ServiceVersion(version.Version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, cfg *config.Config) (sdktrace.SpanExporter, error) {
+	switch cfg.Tracing.Exporter {
+	case config.TracingExporterOTLP:
+		// The exporter builds its own client, with the proxy and certificate
+		// settings of the shared one
+		opts := []otlptracehttp.Option{otlptracehttp.WithProxy(httpclient.Proxy())}
+		if tlsConfig := httpclient.TLSConfig(); tlsConfig != nil {
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsConfig))
+		}
+		if endpoint := cfg.Tracing.Endpoint; endpoint != "" {
+			if strings.Contains(endpoint, "://") {
+				opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+			} else {
+				opts = append(opts, otlptracehttp.WithEndpoint(endpoint), otlptracehttp.WithInsecure())
+			}
+		}
+		if len(cfg.Tracing.Headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(cfg.Tracing.Headers))
+		}
+		exporter, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+		}
+		return exporter, nil
+	case config.TracingExporterFile:
+		path := cfg.Tracing.File
+		if path == "" {
+			path = filepath.Join(cfg.Data.Directory, defaultTraceFile)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create trace directory: %w", err)
+		}
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to create file trace exporter: %w", err)
+		}
+		return &fileExporter{SpanExporter: exporter, file: file}, nil
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter: %s", cfg.Tracing.Exporter)
+	}
+}
+
+// fileExporter closes the trace file once the exporter is shut down.
+type fileExporter struct {
+	sdktrace.SpanExporter
+	file *os.File
+}
+
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	err := e.SpanExporter.Shutdown(ctx)
+	if closeErr := e.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Start starts a span with the OpenCode tracer.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records the error, if any, on the span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// AddRetry records a provider retry on the span in the context.
+func AddRetry(ctx context.Context, attempt int, backoffMs int64) {
+	trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
+		attribute.Int("retry.attempt", attempt),
+		attribute.Int64("retry.backoff_ms", backoffMs),
+	))
+}
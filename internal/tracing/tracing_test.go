@@ -0,0 +1,110 @@
+package tracing
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencode-ai/opencode/internal/config"
+	"github.com/opencode-ai/opencode/internal/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func configureNetwork(t *testing.T, opts httpclient.Options) {
+	t.Helper()
+	require.NoError(t, httpclient.Configure(opts))
+	t.Cleanup(func() {
+		httpclient.Configure(httpclient.Options{})
+	})
+}
+
+func exportSpan(t *testing.T, endpoint string) error {
+	t.Helper()
+	exporter, err := newExporter(context.Background(), &config.Config{Tracing: config.TracingConfig{
+		Exporter: config.TracingExporterOTLP,
+		Endpoint: endpoint,
+	}})
+	require.NoError(t, err)
+	defer exporter.Shutdown(context.Background())
+	return exporter.ExportSpans(context.Background(), []sdktrace.ReadOnlySpan{tracetest.SpanStub{Name: "turn"}.Snapshot()})
+}
+
+func TestOTLPExporterUsesProxy(t *testing.T) {
+	var requested []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.String())
+	}))
+	defer proxy.Close()
+	configureNetwork(t, httpclient.Options{Proxy: proxy.URL})
+
+	require.NoError(t, exportSpan(t, "http://collector.invalid:4318"))
+	assert.Equal(t, []string{"http://collector.invalid:4318/v1/traces"}, requested)
+}
+
+func TestOTLPExporterUsesCACerts(t *testing.T) {
+	collector := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer collector.Close()
+
+	configureNetwork(t, httpclient.Options{})
+	assert.Error(t, exportSpan(t, collector.URL), "the test certificate is not trusted by default")
+
+	caCert := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caCert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: collector.Certificate().Raw}), 0o600))
+	configureNetwork(t, httpclient.Options{CACerts: []string{caCert}})
+	assert.NoError(t, exportSpan(t, collector.URL))
+}
+
+func TestTracedTurn(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	// The spans of a turn as the agent records them
+	ctx, turn := Start(context.Background(), "agent.turn", attribute.String("session.id", "session"))
+	streamCtx, stream := Start(ctx, "provider.stream", attribute.String("gen_ai.request.model", "model"))
+	AddRetry(streamCtx, 1, 2000)
+	End(stream, nil)
+	_, tool := Start(ctx, "tool.run", attribute.String("tool.name", "bash"))
+	End(tool, errors.New("permission denied"))
+	End(turn, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	byName := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range spans {
+		byName[span.Name()] = span
+	}
+	turnSpan, streamSpan, toolSpan := byName["agent.turn"], byName["provider.stream"], byName["tool.run"]
+	require.NotNil(t, turnSpan)
+	require.NotNil(t, streamSpan)
+	require.NotNil(t, toolSpan)
+
+	assert.False(t, turnSpan.Parent().IsValid())
+	assert.Equal(t, turnSpan.SpanContext().SpanID(), streamSpan.Parent().SpanID())
+	assert.Equal(t, turnSpan.SpanContext().SpanID(), toolSpan.Parent().SpanID())
+	assert.Contains(t, turnSpan.Attributes(), attribute.String("session.id", "session"))
+	assert.Equal(t, codes.Unset, turnSpan.Status().Code)
+
+	require.Len(t, streamSpan.Events(), 1)
+	retry := streamSpan.Events()[0]
+	assert.Equal(t, "retry", retry.Name)
+	assert.Equal(t, []attribute.KeyValue{attribute.Int("retry.attempt", 1), attribute.Int64("retry.backoff_ms", 2000)}, retry.Attributes)
+	assert.Equal(t, codes.Unset, streamSpan.Status().Code)
+
+	assert.Equal(t, codes.Error, toolSpan.Status().Code)
+	assert.Equal(t, "permission denied", toolSpan.Status().Description)
+	require.Len(t, toolSpan.Events(), 1)
+	assert.Equal(t, "exception", toolSpan.Events()[0].Name)
+}
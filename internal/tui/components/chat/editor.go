@@ -13,6 +13,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/opencode-ai/opencode/internal/app"
+	"github.com/opencode-ai/opencode/internal/llm/tokens"
 	"github.com/opencode-ai/opencode/internal/logging"
 	"github.com/opencode-ai/opencode/internal/message"
 	"github.com/opencode-ai/opencode/internal/session"
@@ -31,6 +32,11 @@ type editorCmp struct {
 	textarea    textarea.Model
 	attachments []message.Attachment
 	deleteMode  bool
+
+	// Token estimate of the message being typed
+	tokenCount         int64
+	countedValue       string
+	countedAttachments int
 }
 
 type EditorKeyMaps struct {
@@ -116,7 +122,12 @@ func (m *editorCmp) openEditor() tea.Cmd {
 }
 
 func (m *editorCmp) Init() tea.Cmd {
-	return textarea.Blink
+	model := m.app.CoderAgent.Model()
+	return tea.Batch(textarea.Blink, func() tea.Msg {
+		// Load the tokenizer up front so the first keystroke doesn't wait for it
+		tokens.Count(model, " ")
+		return nil
+	})
 }
 
 func (m *editorCmp) send() tea.Cmd {
@@ -142,6 +153,8 @@ func (m *editorCmp) send() tea.Cmd {
 
 func (m *editorCmp) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
+	// After every change to the text or the attachments, whatever the branch
+	defer m.updateTokenEstimate()
 	switch msg := msg.(type) {
 	case dialog.ThemeChangedMsg:
 		m.textarea = CreateTextArea(&m.textarea)
@@ -225,22 +238,71 @@ func (m *editorCmp) View() string {
 		Bold(true).
 		Foreground(t.Primary())
 
-	if len(m.attachments) == 0 {
+	if !m.showInfo() {
 		return lipgloss.JoinHorizontal(lipgloss.Top, style.Render(">"), m.textarea.View())
 	}
-	m.textarea.SetHeight(m.height - 1)
 	return lipgloss.JoinVertical(lipgloss.Top,
-		m.attachmentsContent(),
+		m.infoContent(m.tokenCount),
 		lipgloss.JoinHorizontal(lipgloss.Top, style.Render(">"),
 			m.textarea.View()),
 	)
 }
 
+// updateTokenEstimate recounts the tokens of the message being typed when the
+// text or the attachments changed, and makes room for the line above the input
+// that shows them.
+func (m *editorCmp) updateTokenEstimate() {
+	value := m.textarea.Value()
+	if value != m.countedValue || len(m.attachments) != m.countedAttachments {
+		model := m.app.CoderAgent.Model()
+		m.tokenCount = tokens.Count(model, value) + int64(len(m.attachments))*tokens.ImageTokens
+		m.countedValue = value
+		m.countedAttachments = len(m.attachments)
+	}
+	m.setTextareaHeight()
+}
+
+// showInfo reports whether the line with the attachments and the token
+// estimate is shown above the input.
+func (m *editorCmp) showInfo() bool {
+	return len(m.attachments) > 0 || m.tokenCount > 0
+}
+
+func (m *editorCmp) setTextareaHeight() {
+	if m.showInfo() {
+		m.textarea.SetHeight(m.height - 1)
+	} else {
+		m.textarea.SetHeight(m.height)
+	}
+}
+
+// infoContent renders the attachments on the left and the token estimate on
+// the right of the line above the input.
+func (m *editorCmp) infoContent(estimate int64) string {
+	t := theme.CurrentTheme()
+	var attachments string
+	if len(m.attachments) > 0 {
+		attachments = m.attachmentsContent()
+	}
+	var counter string
+	if estimate > 0 {
+		counter = styles.BaseStyle().
+			Foreground(t.TextMuted()).
+			Render(fmt.Sprintf("~%d tokens ", estimate))
+	}
+	gap := max(m.width-lipgloss.Width(attachments)-lipgloss.Width(counter), 1)
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		attachments,
+		styles.BaseStyle().Width(gap).Render(""),
+		counter,
+	)
+}
+
 func (m *editorCmp) SetSize(width, height int) tea.Cmd {
 	m.width = width
 	m.height = height
 	m.textarea.SetWidth(width - 3) // account for the prompt and padding right
-	m.textarea.SetHeight(height)
+	m.setTextareaHeight()
 	m.textarea.SetWidth(width)
 	return nil
 }
@@ -224,6 +224,8 @@ func toolName(name string) string {
 		return "Bash"
 	case tools.EditToolName:
 		return "Edit"
+	case tools.MultiEditToolName:
+		return "Multi-Edit"
 	case tools.FetchToolName:
 		return "Fetch"
 	case tools.GlobToolName:
@@ -234,12 +236,40 @@ func toolName(name string) string {
 		return "List"
 	case tools.SourcegraphToolName:
 		return "Sourcegraph"
+	case tools.WebSearchToolName:
+		return "Web Search"
 	case tools.ViewToolName:
 		return "View"
+	case tools.ReadOutputToolName:
+		return "Output"
 	case tools.WriteToolName:
 		return "Write"
 	case tools.PatchToolName:
 		return "Patch"
+	case tools.DefinitionToolName:
+		return "Definition"
+	case tools.ReferencesToolName:
+		return "References"
+	case tools.HoverToolName:
+		return "Hover"
+	case tools.CallHierarchyToolName:
+		return "Calls"
+	case tools.SymbolsToolName:
+		return "Symbols"
+	case tools.RenameSymbolToolName:
+		return "Rename"
+	case tools.CodeActionToolName:
+		return "Code Action"
+	case tools.ProcessToolName:
+		return "Process"
+	case tools.TodoToolName:
+		return "Todo"
+	case tools.AskUserToolName:
+		return "Question"
+	case tools.GitToolName:
+		return "Git"
+	case tools.RunTestsToolName:
+		return "Tests"
 	}
 	return name
 }
@@ -250,7 +280,7 @@ func getToolAction(name string) string {
 		return "Preparing prompt..."
 	case tools.BashToolName:
 		return "Building command..."
-	case tools.EditToolName:
+	case tools.EditToolName, tools.MultiEditToolName:
 		return "Preparing edit..."
 	case tools.FetchToolName:
 		return "Writing fetch..."
@@ -262,12 +292,34 @@ func getToolAction(name string) string {
 		return "Listing directory..."
 	case tools.SourcegraphToolName:
 		return "Searching code..."
+	case tools.WebSearchToolName:
+		return "Searching the web..."
 	case tools.ViewToolName:
 		return "Reading file..."
+	case tools.ReadOutputToolName:
+		return "Reading output..."
+	case tools.AskUserToolName:
+		return "Preparing question..."
 	case tools.WriteToolName:
 		return "Preparing write..."
 	case tools.PatchToolName:
 		return "Preparing patch..."
+	case tools.DefinitionToolName, tools.ReferencesToolName, tools.HoverToolName, tools.CallHierarchyToolName:
+		return "Navigating code..."
+	case tools.SymbolsToolName:
+		return "Searching symbols..."
+	case tools.RenameSymbolToolName:
+		return "Preparing rename..."
+	case tools.CodeActionToolName:
+		return "Finding code actions..."
+	case tools.ProcessToolName:
+		return "Checking process..."
+	case tools.TodoToolName:
+		return "Updating todos..."
+	case tools.GitToolName:
+		return "Running git..."
+	case tools.RunTestsToolName:
+		return "Running tests..."
 	}
 	return "Working..."
 }
@@ -344,12 +396,66 @@ func renderToolParams(paramWidth int, toolCall message.ToolCall) string {
 		var params tools.BashParams
 		json.Unmarshal([]byte(toolCall.Input), &params)
 		command := strings.ReplaceAll(params.Command, "\n", " ")
+		if params.Background {
+			return renderParams(paramWidth, command, "background", "true", "name", params.Name)
+		}
 		return renderParams(paramWidth, command)
+	case tools.ProcessToolName:
+		var params tools.ProcessParams
+		json.Unmarshal([]byte(toolCall.Input), &params)
+		if params.Name == "" {
+			return renderParams(paramWidth, params.Action)
+		}
+		return renderParams(paramWidth, params.Action, "name", params.Name)
+	case tools.RunTestsToolName:
+		var params tools.RunTestsParams
+		json.Unmarshal([]byte(toolCall.Input), &params)
+		target := params.Target
+		if target == "" {
+			target = "all"
+		}
+		toolParams := []string{target}
+		if params.Name != "" {
+			toolParams = append(toolParams, "name", params.Name)
+		}
+		if params.Framework != "" {
+			toolParams = append(toolParams, "framework", params.Framework)
+		}
+		return renderParams(paramWidth, toolParams...)
+	case tools.GitToolName:
+		var params tools.GitParams
+		json.Unmarshal([]byte(toolCall.Input), &params)
+		toolParams := []string{params.Operation}
+		if params.Action != "" {
+			toolParams = append(toolParams, "action", params.Action)
+		}
+		if params.Ref != "" {
+			toolParams = append(toolParams, "ref", params.Ref)
+		}
+		if len(params.Paths) > 0 {
+			toolParams = append(toolParams, "paths", strings.Join(params.Paths, ", "))
+		}
+		return renderParams(paramWidth, toolParams...)
+	case tools.TodoToolName:
+		var params tools.TodoParams
+		json.Unmarshal([]byte(toolCall.Input), &params)
+		switch {
+		case len(params.Items) > 0:
+			return renderParams(paramWidth, params.Action, "items", fmt.Sprintf("%d", len(params.Items)))
+		case params.Number > 0:
+			return renderParams(paramWidth, params.Action, "number", fmt.Sprintf("%d", params.Number))
+		}
+		return renderParams(paramWidth, params.Action)
 	case tools.EditToolName:
 		var params tools.EditParams
 		json.Unmarshal([]byte(toolCall.Input), &params)
 		filePath := removeWorkingDirPrefix(params.FilePath)
 		return renderParams(paramWidth, filePath)
+	case tools.MultiEditToolName:
+		var params tools.MultiEditParams
+		json.Unmarshal([]byte(toolCall.Input), &params)
+		filePath := removeWorkingDirPrefix(params.FilePath)
+		return renderParams(paramWidth, filePath, "edits", fmt.Sprintf("%d", len(params.Edits)))
 	case tools.FetchToolName:
 		var params tools.FetchParams
 		json.Unmarshal([]byte(toolCall.Input), &params)
@@ -388,9 +494,18 @@ func renderToolParams(paramWidth int, toolCall message.ToolCall) string {
 		if params.Include != "" {
 			toolParams = append(toolParams, "include", params.Include)
 		}
+		if params.Type != "" {
+			toolParams = append(toolParams, "type", params.Type)
+		}
 		if params.LiteralText {
 			toolParams = append(toolParams, "literal", "true")
 		}
+		if params.OutputMode != "" {
+			toolParams = append(toolParams, "output", params.OutputMode)
+		}
+		if params.Offset > 0 {
+			toolParams = append(toolParams, "offset", fmt.Sprintf("%d", params.Offset))
+		}
 		return renderParams(paramWidth, toolParams...)
 	case tools.LSToolName:
 		var params tools.LSParams
@@ -404,6 +519,45 @@ func renderToolParams(paramWidth int, toolCall message.ToolCall) string {
 		var params tools.SourcegraphParams
 		json.Unmarshal([]byte(toolCall.Input), &params)
 		return renderParams(paramWidth, params.Query)
+	case tools.AskUserToolName:
+		var params tools.AskUserParams
+		json.Unmarshal([]byte(toolCall.Input), &params)
+		toolParams := []string{
+			params.Question,
+		}
+		if len(params.Options) > 0 {
+			toolParams = append(toolParams, "options", fmt.Sprintf("%d", len(params.Options)))
+		}
+		return renderParams(paramWidth, toolParams...)
+	case tools.ReadOutputToolName:
+		var params tools.ReadOutputParams
+		json.Unmarshal([]byte(toolCall.Input), &params)
+		toolParams := []string{
+			params.ID,
+		}
+		if params.ID == "" {
+			toolParams[0] = "list"
+		}
+		if params.Pattern != "" {
+			toolParams = append(toolParams, "pattern", params.Pattern)
+		}
+		if params.Offset != 0 {
+			toolParams = append(toolParams, "offset", fmt.Sprintf("%d", params.Offset))
+		}
+		if params.Limit != 0 {
+			toolParams = append(toolParams, "limit", fmt.Sprintf("%d", params.Limit))
+		}
+		return renderParams(paramWidth, toolParams...)
+	case tools.WebSearchToolName:
+		var params tools.WebSearchParams
+		json.Unmarshal([]byte(toolCall.Input), &params)
+		toolParams := []string{
+			params.Query,
+		}
+		if params.Count > 0 {
+			toolParams = append(toolParams, "count", fmt.Sprintf("%d", params.Count))
+		}
+		return renderParams(paramWidth, toolParams...)
 	case tools.ViewToolName:
 		var params tools.ViewParams
 		json.Unmarshal([]byte(toolCall.Input), &params)
@@ -423,6 +577,40 @@ func renderToolParams(paramWidth int, toolCall message.ToolCall) string {
 		json.Unmarshal([]byte(toolCall.Input), &params)
 		filePath := removeWorkingDirPrefix(params.FilePath)
 		return renderParams(paramWidth, filePath)
+	case tools.DefinitionToolName, tools.ReferencesToolName, tools.HoverToolName:
+		var params tools.NavigationParams
+		json.Unmarshal([]byte(toolCall.Input), &params)
+		return renderParams(paramWidth, navigationTarget(params)...)
+	case tools.CallHierarchyToolName:
+		var params tools.CallHierarchyParams
+		json.Unmarshal([]byte(toolCall.Input), &params)
+		toolParams := navigationTarget(params.NavigationParams)
+		if params.Direction != "" {
+			toolParams = append(toolParams, "direction", params.Direction)
+		}
+		return renderParams(paramWidth, toolParams...)
+	case tools.RenameSymbolToolName:
+		var params tools.RenameSymbolParams
+		json.Unmarshal([]byte(toolCall.Input), &params)
+		return renderParams(paramWidth, append(navigationTarget(params.NavigationParams), "new_name", params.NewName)...)
+	case tools.CodeActionToolName:
+		var params tools.CodeActionParams
+		json.Unmarshal([]byte(toolCall.Input), &params)
+		toolParams := []string{removeWorkingDirPrefix(params.Location)}
+		if params.Kind != "" {
+			toolParams = append(toolParams, "kind", params.Kind)
+		}
+		if params.Action != "" {
+			toolParams = append(toolParams, "action", params.Action)
+		}
+		return renderParams(paramWidth, toolParams...)
+	case tools.SymbolsToolName:
+		var params tools.SymbolsParams
+		json.Unmarshal([]byte(toolCall.Input), &params)
+		if params.FilePath != "" {
+			return renderParams(paramWidth, removeWorkingDirPrefix(params.FilePath))
+		}
+		return renderParams(paramWidth, params.Query)
 	default:
 		input := strings.ReplaceAll(toolCall.Input, "\n", " ")
 		params = renderParams(paramWidth, input)
@@ -430,6 +618,19 @@ func renderToolParams(paramWidth int, toolCall message.ToolCall) string {
 	return params
 }
 
+// navigationTarget shows the location of a navigation tool call, or the
+// symbol name when it was searched by name.
+func navigationTarget(params tools.NavigationParams) []string {
+	if params.Location == "" {
+		return []string{params.Symbol}
+	}
+	toolParams := []string{removeWorkingDirPrefix(params.Location)}
+	if params.Symbol != "" {
+		toolParams = append(toolParams, "symbol", params.Symbol)
+	}
+	return toolParams
+}
+
 func truncateHeight(content string, height int) string {
 	lines := strings.Split(content, "\n")
 	if len(lines) > height {
@@ -458,13 +659,25 @@ func renderToolResponse(toolCall message.ToolCall, response message.ToolResult,
 			toMarkdown(resultContent, false, width),
 			t.Background(),
 		)
-	case tools.BashToolName:
+	case tools.BashToolName, tools.ProcessToolName:
 		resultContent = fmt.Sprintf("```bash\n%s\n```", resultContent)
 		return styles.ForceReplaceBackgroundWithLipgloss(
 			toMarkdown(resultContent, true, width),
 			t.Background(),
 		)
-	case tools.EditToolName:
+	case tools.GitToolName:
+		var params tools.GitParams
+		json.Unmarshal([]byte(toolCall.Input), &params)
+		lang := "text"
+		if !params.Stat && (params.Operation == "diff" || params.Operation == "show" || (params.Operation == "stash" && params.Action == "show")) {
+			lang = "diff"
+		}
+		resultContent = fmt.Sprintf("```%s\n%s\n```", lang, resultContent)
+		return styles.ForceReplaceBackgroundWithLipgloss(
+			toMarkdown(resultContent, true, width),
+			t.Background(),
+		)
+	case tools.EditToolName, tools.MultiEditToolName:
 		metadata := tools.EditResponseMetadata{}
 		json.Unmarshal([]byte(response.Metadata), &metadata)
 		truncDiff := truncateHeight(metadata.Diff, maxResultHeight)
@@ -485,17 +698,36 @@ func renderToolResponse(toolCall message.ToolCall, response message.ToolResult,
 			toMarkdown(resultContent, true, width),
 			t.Background(),
 		)
-	case tools.GlobToolName:
+	case tools.GlobToolName, tools.TodoToolName, tools.RunTestsToolName, tools.AskUserToolName:
 		return baseStyle.Width(width).Foreground(t.TextMuted()).Render(resultContent)
 	case tools.GrepToolName:
 		return baseStyle.Width(width).Foreground(t.TextMuted()).Render(resultContent)
-	case tools.LSToolName:
+	case tools.LSToolName, tools.ReadOutputToolName:
 		return baseStyle.Width(width).Foreground(t.TextMuted()).Render(resultContent)
-	case tools.SourcegraphToolName:
+	case tools.SourcegraphToolName, tools.WebSearchToolName:
+		return baseStyle.Width(width).Foreground(t.TextMuted()).Render(resultContent)
+	case tools.DefinitionToolName, tools.ReferencesToolName, tools.CallHierarchyToolName, tools.SymbolsToolName:
 		return baseStyle.Width(width).Foreground(t.TextMuted()).Render(resultContent)
+	case tools.RenameSymbolToolName, tools.CodeActionToolName:
+		metadata := tools.WorkspaceEditResponseMetadata{}
+		json.Unmarshal([]byte(response.Metadata), &metadata)
+		if len(metadata.Files) == 0 {
+			return baseStyle.Width(width).Foreground(t.TextMuted()).Render(resultContent)
+		}
+		formattedDiff, _ := tools.FormatFileDiffs(metadata.Files, width)
+		return truncateHeight(formattedDiff, maxResultHeight)
+	case tools.HoverToolName:
+		return styles.ForceReplaceBackgroundWithLipgloss(
+			toMarkdown(resultContent, true, width),
+			t.Background(),
+		)
 	case tools.ViewToolName:
 		metadata := tools.ViewResponseMetadata{}
 		json.Unmarshal([]byte(response.Metadata), &metadata)
+		// Images have no text content to show
+		if metadata.Content == "" {
+			return baseStyle.Width(width).Foreground(t.TextMuted()).Render(resultContent)
+		}
 		ext := filepath.Ext(metadata.FilePath)
 		if ext == "" {
 			ext = ""
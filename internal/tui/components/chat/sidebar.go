@@ -11,8 +11,10 @@ import (
 	"github.com/opencode-ai/opencode/internal/config"
 	"github.com/opencode-ai/opencode/internal/diff"
 	"github.com/opencode-ai/opencode/internal/history"
+	"github.com/opencode-ai/opencode/internal/llm/tools/shell"
 	"github.com/opencode-ai/opencode/internal/pubsub"
 	"github.com/opencode-ai/opencode/internal/session"
+	"github.com/opencode-ai/opencode/internal/todo"
 	"github.com/opencode-ai/opencode/internal/tui/styles"
 	"github.com/opencode-ai/opencode/internal/tui/theme"
 )
@@ -25,9 +27,15 @@ type sidebarCmp struct {
 		additions int
 		removals  int
 	}
+	processes shell.BackgroundService
+	procs     []shell.BackgroundProcess
+	todos     todo.Service
+	todoItems []todo.Todo
 }
 
 func (m *sidebarCmp) Init() tea.Cmd {
+	m.loadProcesses()
+	m.loadTodos()
 	if m.history != nil {
 		ctx := context.Background()
 		// Subscribe to file events
@@ -57,6 +65,8 @@ func (m *sidebarCmp) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.session = msg
 			ctx := context.Background()
 			m.loadModifiedFiles(ctx)
+			m.loadProcesses()
+			m.loadTodos()
 		}
 	case pubsub.Event[session.Session]:
 		if msg.Type == pubsub.UpdatedEvent {
@@ -64,6 +74,14 @@ func (m *sidebarCmp) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.session = msg.Payload
 			}
 		}
+	case pubsub.Event[shell.BackgroundProcess]:
+		if msg.Payload.SessionID == m.session.ID {
+			m.loadProcesses()
+		}
+	case pubsub.Event[todo.Todo]:
+		if msg.Payload.SessionID == m.session.ID {
+			m.loadTodos()
+		}
 	case pubsub.Event[history.File]:
 		if msg.Payload.SessionID == m.session.ID {
 			// Process the individual file change instead of reloading all files
@@ -99,6 +117,8 @@ func (m *sidebarCmp) View() string {
 				lspsConfigured(m.width),
 				" ",
 				m.modifiedFiles(),
+				m.todoList(),
+				m.backgroundProcesses(),
 			),
 		)
 }
@@ -225,6 +245,85 @@ func (m *sidebarCmp) modifiedFiles() string {
 		)
 }
 
+func (m *sidebarCmp) loadProcesses() {
+	if m.processes == nil {
+		return
+	}
+	m.procs = m.processes.List(m.session.ID)
+}
+
+func (m *sidebarCmp) backgroundProcesses() string {
+	if len(m.procs) == 0 {
+		return ""
+	}
+	t := theme.CurrentTheme()
+	baseStyle := styles.BaseStyle()
+
+	title := baseStyle.
+		Width(m.width).
+		Foreground(t.Primary()).
+		Bold(true).
+		Render("Background Processes:")
+
+	views := []string{" ", title}
+	for _, proc := range m.procs {
+		status := baseStyle.Foreground(t.Success()).Render(" running")
+		if !proc.Running {
+			status = baseStyle.Foreground(t.TextMuted()).Render(fmt.Sprintf(" exited %d", proc.ExitCode))
+		}
+		name := baseStyle.Foreground(t.Text()).Render(proc.Name)
+		views = append(views, baseStyle.Width(m.width).Render(lipgloss.JoinHorizontal(lipgloss.Left, name, status)))
+	}
+	return baseStyle.Width(m.width).Render(lipgloss.JoinVertical(lipgloss.Top, views...))
+}
+
+func (m *sidebarCmp) loadTodos() {
+	if m.todos == nil || m.session.ID == "" {
+		m.todoItems = nil
+		return
+	}
+	todos, err := m.todos.List(context.Background(), m.session.ID)
+	if err != nil {
+		return
+	}
+	m.todoItems = todos
+}
+
+func (m *sidebarCmp) todoList() string {
+	if len(m.todoItems) == 0 {
+		return ""
+	}
+	t := theme.CurrentTheme()
+	baseStyle := styles.BaseStyle()
+
+	completed := 0
+	for _, item := range m.todoItems {
+		if item.Status == todo.StatusCompleted {
+			completed++
+		}
+	}
+	title := baseStyle.
+		Width(m.width).
+		Foreground(t.Primary()).
+		Bold(true).
+		Render(fmt.Sprintf("Todo (%d/%d):", completed, len(m.todoItems)))
+
+	views := []string{" ", title}
+	for _, item := range m.todoItems {
+		var line string
+		switch item.Status {
+		case todo.StatusCompleted:
+			line = baseStyle.Foreground(t.TextMuted()).Strikethrough(true).Render("✓ " + item.Content)
+		case todo.StatusInProgress:
+			line = baseStyle.Foreground(t.Accent()).Bold(true).Render("› " + item.Content)
+		default:
+			line = baseStyle.Foreground(t.Text()).Render("○ " + item.Content)
+		}
+		views = append(views, baseStyle.Width(m.width).Render(line))
+	}
+	return baseStyle.Width(m.width).Render(lipgloss.JoinVertical(lipgloss.Top, views...))
+}
+
 func (m *sidebarCmp) SetSize(width, height int) tea.Cmd {
 	m.width = width
 	m.height = height
@@ -235,10 +334,12 @@ func (m *sidebarCmp) GetSize() (int, int) {
 	return m.width, m.height
 }
 
-func NewSidebarCmp(session session.Session, history history.Service) tea.Model {
+func NewSidebarCmp(session session.Session, history history.Service, processes shell.BackgroundService, todos todo.Service) tea.Model {
 	return &sidebarCmp{
-		session: session,
-		history: history,
+		session:   session,
+		processes: processes,
+		history:   history,
+		todos:     todos,
 	}
 }
 
@@ -227,9 +227,9 @@ func (p *permissionDialogCmp) renderHeader() string {
 
 	// Add tool-specific header information
 	switch p.permission.ToolName {
-	case tools.BashToolName:
+	case tools.BashToolName, tools.GitToolName, tools.RunTestsToolName:
 		headerParts = append(headerParts, baseStyle.Foreground(t.TextMuted()).Width(p.width).Bold(true).Render("Command"))
-	case tools.EditToolName:
+	case tools.EditToolName, tools.MultiEditToolName:
 		params := p.permission.Params.(tools.EditPermissionsParams)
 		fileKey := baseStyle.Foreground(t.TextMuted()).Bold(true).Render("File")
 		filePath := baseStyle.
@@ -262,6 +262,11 @@ func (p *permissionDialogCmp) renderHeader() string {
 		)
 	case tools.FetchToolName:
 		headerParts = append(headerParts, baseStyle.Foreground(t.TextMuted()).Width(p.width).Bold(true).Render("URL"))
+	case tools.RenameSymbolToolName, tools.CodeActionToolName:
+		headerParts = append(headerParts,
+			baseStyle.Foreground(t.Text()).Width(p.width).Render(p.permission.Description),
+			baseStyle.Render(strings.Repeat(" ", p.width)),
+		)
 	}
 
 	return lipgloss.NewStyle().Background(t.Background()).Render(lipgloss.JoinVertical(lipgloss.Left, headerParts...))
@@ -273,6 +278,36 @@ func (p *permissionDialogCmp) renderBashContent() string {
 
 	if pr, ok := p.permission.Params.(tools.BashPermissionsParams); ok {
 		content := fmt.Sprintf("```bash\n%s\n```", pr.Command)
+		if len(pr.Commands) > 1 {
+			content += "\n\nRuns these commands:\n\n" + bashCommandBreakdown(pr.Commands)
+		}
+
+		// Use the cache for markdown rendering
+		renderedContent := p.GetOrSetMarkdown(p.permission.ID, func() (string, error) {
+			r := styles.GetMarkdownRenderer(p.width - 10)
+			s, err := r.Render(content)
+			return styles.ForceReplaceBackgroundWithLipgloss(s, t.Background()), err
+		})
+
+		finalContent := baseStyle.
+			Width(p.contentViewPort.Width).
+			Render(renderedContent)
+		p.contentViewPort.SetContent(finalContent)
+		return p.styleViewport()
+	}
+	return ""
+}
+
+func (p *permissionDialogCmp) renderCodeActionCommandContent() string {
+	t := theme.CurrentTheme()
+	baseStyle := styles.BaseStyle()
+
+	if pr, ok := p.permission.Params.(tools.CodeActionCommandPermissionsParams); ok {
+		arguments := make([]string, len(pr.Arguments))
+		for i, argument := range pr.Arguments {
+			arguments[i] = string(argument)
+		}
+		content := fmt.Sprintf("```\n%s\n```", strings.Join(append([]string{pr.Command}, arguments...), "\n"))
 
 		// Use the cache for markdown rendering
 		renderedContent := p.GetOrSetMarkdown(p.permission.ID, func() (string, error) {
@@ -290,6 +325,51 @@ func (p *permissionDialogCmp) renderBashContent() string {
 	return ""
 }
 
+// renderGitContent shows the git command line and, for commits, the changes
+// it would record.
+func (p *permissionDialogCmp) renderGitContent() string {
+	t := theme.CurrentTheme()
+	baseStyle := styles.BaseStyle()
+
+	if pr, ok := p.permission.Params.(tools.GitPermissionsParams); ok {
+		content := fmt.Sprintf("```bash\n%s\n```", pr.Command)
+		if pr.Changes != "" {
+			content += fmt.Sprintf("\n\nChanges to commit (added, removed, path):\n\n```\n%s\n```", pr.Changes)
+		}
+
+		// Use the cache for markdown rendering
+		renderedContent := p.GetOrSetMarkdown(p.permission.ID, func() (string, error) {
+			r := styles.GetMarkdownRenderer(p.width - 10)
+			s, err := r.Render(content)
+			return styles.ForceReplaceBackgroundWithLipgloss(s, t.Background()), err
+		})
+
+		finalContent := baseStyle.
+			Width(p.contentViewPort.Width).
+			Render(renderedContent)
+		p.contentViewPort.SetContent(finalContent)
+		return p.styleViewport()
+	}
+	return ""
+}
+
+// bashCommandBreakdown lists the commands of a command line with what each
+// one needs.
+func bashCommandBreakdown(commands []tools.BashCommand) string {
+	lines := make([]string, 0, len(commands))
+	for _, command := range commands {
+		status := "needs approval"
+		switch {
+		case command.WritesTo != "":
+			status = "writes " + command.WritesTo
+		case command.ReadOnly:
+			status = "read-only"
+		}
+		lines = append(lines, fmt.Sprintf("- `%s` %s", strings.ReplaceAll(command.Command, "`", "'"), status))
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (p *permissionDialogCmp) renderEditContent() string {
 	if pr, ok := p.permission.Params.(tools.EditPermissionsParams); ok {
 		diff := p.GetOrSetDiff(p.permission.ID, func() (string, error) {
@@ -314,6 +394,18 @@ func (p *permissionDialogCmp) renderPatchContent() string {
 	return ""
 }
 
+func (p *permissionDialogCmp) renderWorkspaceEditContent() string {
+	if pr, ok := p.permission.Params.(tools.WorkspaceEditPermissionsParams); ok {
+		diff := p.GetOrSetDiff(p.permission.ID, func() (string, error) {
+			return tools.FormatFileDiffs(pr.Files, p.contentViewPort.Width)
+		})
+
+		p.contentViewPort.SetContent(diff)
+		return p.styleViewport()
+	}
+	return ""
+}
+
 func (p *permissionDialogCmp) renderWriteContent() string {
 	if pr, ok := p.permission.Params.(tools.WritePermissionsParams); ok {
 		// Use the cache for diff rendering
@@ -404,9 +496,11 @@ func (p *permissionDialogCmp) render() string {
 	// Render content based on tool type
 	var contentFinal string
 	switch p.permission.ToolName {
-	case tools.BashToolName:
+	case tools.BashToolName, tools.RunTestsToolName:
 		contentFinal = p.renderBashContent()
-	case tools.EditToolName:
+	case tools.GitToolName:
+		contentFinal = p.renderGitContent()
+	case tools.EditToolName, tools.MultiEditToolName:
 		contentFinal = p.renderEditContent()
 	case tools.PatchToolName:
 		contentFinal = p.renderPatchContent()
@@ -414,6 +508,14 @@ func (p *permissionDialogCmp) render() string {
 		contentFinal = p.renderWriteContent()
 	case tools.FetchToolName:
 		contentFinal = p.renderFetchContent()
+	case tools.RenameSymbolToolName:
+		contentFinal = p.renderWorkspaceEditContent()
+	case tools.CodeActionToolName:
+		if _, ok := p.permission.Params.(tools.WorkspaceEditPermissionsParams); ok {
+			contentFinal = p.renderWorkspaceEditContent()
+		} else {
+			contentFinal = p.renderCodeActionCommandContent()
+		}
 	default:
 		contentFinal = p.renderDefaultContent()
 	}
@@ -453,15 +555,18 @@ func (p *permissionDialogCmp) SetSize() tea.Cmd {
 		return nil
 	}
 	switch p.permission.ToolName {
-	case tools.BashToolName:
+	case tools.BashToolName, tools.RunTestsToolName:
 		p.width = int(float64(p.windowSize.Width) * 0.4)
 		p.height = int(float64(p.windowSize.Height) * 0.3)
-	case tools.EditToolName:
+	case tools.EditToolName, tools.MultiEditToolName:
 		p.width = int(float64(p.windowSize.Width) * 0.8)
 		p.height = int(float64(p.windowSize.Height) * 0.8)
-	case tools.WriteToolName:
+	case tools.WriteToolName, tools.RenameSymbolToolName, tools.CodeActionToolName:
 		p.width = int(float64(p.windowSize.Width) * 0.8)
 		p.height = int(float64(p.windowSize.Height) * 0.8)
+	case tools.GitToolName:
+		p.width = int(float64(p.windowSize.Width) * 0.5)
+		p.height = int(float64(p.windowSize.Height) * 0.4)
 	case tools.FetchToolName:
 		p.width = int(float64(p.windowSize.Width) * 0.4)
 		p.height = int(float64(p.windowSize.Height) * 0.3)
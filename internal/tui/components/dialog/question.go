@@ -0,0 +1,244 @@
+package dialog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/opencode-ai/opencode/internal/question"
+	"github.com/opencode-ai/opencode/internal/tui/layout"
+	"github.com/opencode-ai/opencode/internal/tui/styles"
+	"github.com/opencode-ai/opencode/internal/tui/theme"
+	"github.com/opencode-ai/opencode/internal/tui/util"
+)
+
+// QuestionResponseMsg is sent when the user answers or dismisses a question
+type QuestionResponseMsg struct {
+	Question  question.Question
+	Answer    string
+	Dismissed bool
+}
+
+// QuestionDialogCmp interface for the dialog that shows questions of the agent
+type QuestionDialogCmp interface {
+	tea.Model
+	layout.Bindings
+	SetQuestion(question question.Question) tea.Cmd
+	QuestionID() string
+}
+
+type questionKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Choose key.Binding
+	Enter  key.Binding
+	Escape key.Binding
+}
+
+var questionKeys = questionKeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "shift+tab"),
+		key.WithHelp("↑", "previous option"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "tab"),
+		key.WithHelp("↓", "next option"),
+	),
+	Choose: key.NewBinding(
+		key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"),
+		key.WithHelp("1-9", "choose option"),
+	),
+	Enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "answer"),
+	),
+	Escape: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "dismiss"),
+	),
+}
+
+type questionDialogCmp struct {
+	question    question.Question
+	selectedIdx int
+	input       textinput.Model
+	width       int
+}
+
+func (q *questionDialogCmp) Init() tea.Cmd {
+	return nil
+}
+
+func (q *questionDialogCmp) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		q.width = msg.Width
+		q.input.Width = q.maxWidth() - 4
+		return q, nil
+	case tea.KeyMsg:
+		typing := q.selectedIdx == len(q.question.Options)
+		switch {
+		case key.Matches(msg, questionKeys.Escape):
+			return q, util.CmdHandler(QuestionResponseMsg{Question: q.question, Dismissed: true})
+		case key.Matches(msg, questionKeys.Up):
+			if q.selectedIdx > 0 {
+				return q, q.selectOption(q.selectedIdx - 1)
+			}
+			return q, nil
+		case key.Matches(msg, questionKeys.Down):
+			if q.selectedIdx < len(q.question.Options) {
+				return q, q.selectOption(q.selectedIdx + 1)
+			}
+			return q, nil
+		case key.Matches(msg, questionKeys.Enter):
+			if !typing {
+				return q, q.answer(q.question.Options[q.selectedIdx])
+			}
+			if answer := strings.TrimSpace(q.input.Value()); answer != "" {
+				return q, q.answer(answer)
+			}
+			return q, nil
+		case key.Matches(msg, questionKeys.Choose) && !typing:
+			n, _ := strconv.Atoi(msg.String())
+			if n <= len(q.question.Options) {
+				return q, q.answer(q.question.Options[n-1])
+			}
+			return q, nil
+		}
+		if !typing {
+			if msg.Type != tea.KeyRunes {
+				return q, nil
+			}
+			// Typing on an option starts an own answer
+			focusCmd := q.selectOption(len(q.question.Options))
+			var cmd tea.Cmd
+			q.input, cmd = q.input.Update(msg)
+			return q, tea.Batch(focusCmd, cmd)
+		}
+	}
+
+	var cmd tea.Cmd
+	q.input, cmd = q.input.Update(msg)
+	return q, cmd
+}
+
+func (q *questionDialogCmp) maxWidth() int {
+	if q.width > 0 {
+		return min(60, q.width-10)
+	}
+	return 60
+}
+
+func (q *questionDialogCmp) selectOption(idx int) tea.Cmd {
+	q.selectedIdx = idx
+	if idx == len(q.question.Options) {
+		return q.input.Focus()
+	}
+	q.input.Blur()
+	return nil
+}
+
+func (q *questionDialogCmp) answer(answer string) tea.Cmd {
+	return util.CmdHandler(QuestionResponseMsg{Question: q.question, Answer: answer})
+}
+
+func (q *questionDialogCmp) View() string {
+	t := theme.CurrentTheme()
+	baseStyle := styles.BaseStyle()
+
+	maxWidth := q.maxWidth()
+
+	title := baseStyle.
+		Foreground(t.Primary()).
+		Bold(true).
+		Width(maxWidth).
+		Padding(0, 1).
+		Render("Question")
+
+	questionText := baseStyle.
+		Foreground(t.Text()).
+		Width(maxWidth).
+		Padding(0, 1).
+		Render(q.question.Question)
+
+	items := make([]string, 0, len(q.question.Options)+1)
+	for i, option := range q.question.Options {
+		itemStyle := baseStyle.Width(maxWidth)
+		if i == q.selectedIdx {
+			itemStyle = itemStyle.
+				Background(t.Primary()).
+				Foreground(t.Background()).
+				Bold(true)
+		}
+		items = append(items, itemStyle.Padding(0, 1).Render(fmt.Sprintf("%d. %s", i+1, option)))
+	}
+
+	label := "Your answer:"
+	if len(q.question.Options) > 0 {
+		label = "Or type your own answer:"
+	}
+	labelStyle := baseStyle.Width(maxWidth).Padding(0, 1).Foreground(t.TextMuted())
+	if q.selectedIdx == len(q.question.Options) {
+		labelStyle = labelStyle.Foreground(t.Primary()).Bold(true)
+	}
+
+	elements := []string{
+		title,
+		baseStyle.Width(maxWidth).Render(""),
+		questionText,
+		baseStyle.Width(maxWidth).Render(""),
+	}
+	if len(items) > 0 {
+		elements = append(elements,
+			lipgloss.JoinVertical(lipgloss.Left, items...),
+			baseStyle.Width(maxWidth).Render(""),
+		)
+	}
+	elements = append(elements,
+		labelStyle.Render(label),
+		baseStyle.Width(maxWidth).Padding(0, 1).Render(q.input.View()),
+	)
+
+	content := lipgloss.JoinVertical(lipgloss.Left, elements...)
+
+	return baseStyle.Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderBackground(t.Background()).
+		BorderForeground(t.TextMuted()).
+		Width(lipgloss.Width(content) + 4).
+		Render(content)
+}
+
+func (q *questionDialogCmp) BindingKeys() []key.Binding {
+	return layout.KeyMapToSlice(questionKeys)
+}
+
+func (q *questionDialogCmp) SetQuestion(question question.Question) tea.Cmd {
+	q.question = question
+	q.input.Reset()
+	// Without options the only way to answer is typing
+	return q.selectOption(0)
+}
+
+func (q *questionDialogCmp) QuestionID() string {
+	return q.question.ID
+}
+
+// NewQuestionDialogCmp creates a new dialog for the questions of the agent
+func NewQuestionDialogCmp() QuestionDialogCmp {
+	t := theme.CurrentTheme()
+	input := textinput.New()
+	input.Placeholder = "Type an answer..."
+	input.Prompt = ""
+	input.Width = 56
+	input.PlaceholderStyle = input.PlaceholderStyle.Background(t.Background())
+	input.PromptStyle = input.PromptStyle.Background(t.Background())
+	input.TextStyle = input.TextStyle.Background(t.Background()).Foreground(t.Primary())
+	return &questionDialogCmp{
+		input: input,
+	}
+}
@@ -12,7 +12,7 @@ import (
 	"github.com/opencode-ai/opencode/internal/tui/util"
 )
 
-const question = "Are you sure you want to quit?"
+const quitQuestion = "Are you sure you want to quit?"
 
 type CloseQuitMsg struct{}
 
@@ -102,7 +102,7 @@ func (q *quitDialogCmp) View() string {
 
 	buttons := lipgloss.JoinHorizontal(lipgloss.Left, yesButton, spacerStyle.Render("  "), noButton)
 
-	width := lipgloss.Width(question)
+	width := lipgloss.Width(quitQuestion)
 	remainingWidth := width - lipgloss.Width(buttons)
 	if remainingWidth > 0 {
 		buttons = spacerStyle.Render(strings.Repeat(" ", remainingWidth)) + buttons
@@ -111,7 +111,7 @@ func (q *quitDialogCmp) View() string {
 	content := baseStyle.Render(
 		lipgloss.JoinVertical(
 			lipgloss.Center,
-			question,
+			quitQuestion,
 			"",
 			buttons,
 		),
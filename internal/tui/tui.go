@@ -14,6 +14,7 @@ import (
 	"github.com/opencode-ai/opencode/internal/logging"
 	"github.com/opencode-ai/opencode/internal/permission"
 	"github.com/opencode-ai/opencode/internal/pubsub"
+	"github.com/opencode-ai/opencode/internal/question"
 	"github.com/opencode-ai/opencode/internal/session"
 	"github.com/opencode-ai/opencode/internal/tui/components/chat"
 	"github.com/opencode-ai/opencode/internal/tui/components/core"
@@ -108,6 +109,9 @@ type appModel struct {
 	showPermissions bool
 	permissions     dialog.PermissionDialogCmp
 
+	showQuestion   bool
+	questionDialog dialog.QuestionDialogCmp
+
 	showHelp bool
 	help     dialog.HelpCmp
 
@@ -196,6 +200,10 @@ func (a appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.permissions = prm.(dialog.PermissionDialogCmp)
 		cmds = append(cmds, permCmd)
 
+		qst, questionCmd := a.questionDialog.Update(msg)
+		a.questionDialog = qst.(dialog.QuestionDialogCmp)
+		cmds = append(cmds, questionCmd)
+
 		help, helpCmd := a.help.Update(msg)
 		a.help = help.(dialog.HelpCmp)
 		cmds = append(cmds, helpCmd)
@@ -288,6 +296,28 @@ func (a appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.showPermissions = false
 		return a, cmd
 
+	// Question
+	case pubsub.Event[question.Question]:
+		switch msg.Type {
+		case pubsub.CreatedEvent:
+			a.showQuestion = true
+			return a, a.questionDialog.SetQuestion(msg.Payload)
+		case pubsub.DeletedEvent:
+			// The agent stopped waiting for the answer
+			if msg.Payload.ID == a.questionDialog.QuestionID() {
+				a.showQuestion = false
+			}
+		}
+		return a, nil
+	case dialog.QuestionResponseMsg:
+		if msg.Dismissed {
+			a.app.Questions.Dismiss(msg.Question)
+		} else {
+			a.app.Questions.Answer(msg.Question, msg.Answer)
+		}
+		a.showQuestion = false
+		return a, nil
+
 	case page.PageChangeMsg:
 		return a, a.moveToPage(msg.ID)
 
@@ -336,7 +366,7 @@ func (a appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			model := a.app.CoderAgent.Model()
 			contextWindow := model.ContextWindow
 			tokens := a.selectedSession.CompletionTokens + a.selectedSession.PromptTokens
-			if (tokens >= int64(float64(contextWindow)*0.95)) && config.Get().AutoCompact {
+			if (tokens >= int64(float64(contextWindow)*0.95) || payload.ContextTrimmed) && config.Get().AutoCompact {
 				return a, util.CmdHandler(startCompactSessionMsg{})
 			}
 		}
@@ -443,6 +473,13 @@ func (a appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return a, nil
 
 	case tea.KeyMsg:
+		// An open question gets all key presses, apart from quitting
+		if a.showQuestion && !a.showQuit && !key.Matches(msg, keys.Quit) {
+			q, cmd := a.questionDialog.Update(msg)
+			a.questionDialog = q.(dialog.QuestionDialogCmp)
+			return a, cmd
+		}
+
 		// If multi-arguments dialog is open, let it handle the key press first
 		if a.showMultiArgumentsDialog {
 			args, cmd := a.multiArgumentsDialog.Update(msg)
@@ -723,6 +760,21 @@ func (a appModel) View() string {
 		)
 	}
 
+	if a.showQuestion {
+		overlay := a.questionDialog.View()
+		row := lipgloss.Height(appView) / 2
+		row -= lipgloss.Height(overlay) / 2
+		col := lipgloss.Width(appView) / 2
+		col -= lipgloss.Width(overlay) / 2
+		appView = layout.PlaceOverlay(
+			col,
+			row,
+			overlay,
+			appView,
+			true,
+		)
+	}
+
 	if a.showFilepicker {
 		overlay := a.filepicker.View()
 		row := lipgloss.Height(appView) / 2
@@ -772,6 +824,9 @@ func (a appModel) View() string {
 		if a.showPermissions {
 			bindings = append(bindings, a.permissions.BindingKeys()...)
 		}
+		if a.showQuestion {
+			bindings = append(bindings, a.questionDialog.BindingKeys()...)
+		}
 		if a.currentPage == page.LogsPage {
 			bindings = append(bindings, logsKeyReturnKey)
 		}
@@ -901,19 +956,20 @@ func (a appModel) View() string {
 func New(app *app.App) tea.Model {
 	startPage := page.ChatPage
 	model := &appModel{
-		currentPage:   startPage,
-		loadedPages:   make(map[page.PageID]bool),
-		status:        core.NewStatusCmp(app.LSPClients),
-		help:          dialog.NewHelpCmp(),
-		quit:          dialog.NewQuitCmp(),
-		sessionDialog: dialog.NewSessionDialogCmp(),
-		commandDialog: dialog.NewCommandDialogCmp(),
-		modelDialog:   dialog.NewModelDialogCmp(),
-		permissions:   dialog.NewPermissionDialogCmp(),
-		initDialog:    dialog.NewInitDialogCmp(),
-		themeDialog:   dialog.NewThemeDialogCmp(),
-		app:           app,
-		commands:      []dialog.Command{},
+		currentPage:    startPage,
+		loadedPages:    make(map[page.PageID]bool),
+		status:         core.NewStatusCmp(app.LSPClients),
+		help:           dialog.NewHelpCmp(),
+		quit:           dialog.NewQuitCmp(),
+		sessionDialog:  dialog.NewSessionDialogCmp(),
+		commandDialog:  dialog.NewCommandDialogCmp(),
+		modelDialog:    dialog.NewModelDialogCmp(),
+		permissions:    dialog.NewPermissionDialogCmp(),
+		questionDialog: dialog.NewQuestionDialogCmp(),
+		initDialog:     dialog.NewInitDialogCmp(),
+		themeDialog:    dialog.NewThemeDialogCmp(),
+		app:            app,
+		commands:       []dialog.Command{},
 		pages: map[page.PageID]tea.Model{
 			page.ChatPage: page.NewChatPage(app),
 			page.LogsPage: page.NewLogsPage(),